@@ -659,6 +659,16 @@ profiles:
 									BindTimeoutSeconds: 600,
 								},
 							},
+							{
+								Name: "VolumeRestrictions",
+								Args: &kubeschedulerconfig.VolumeRestrictionsArgs{
+									DiskConflictMode:              kubeschedulerconfig.DiskConflictEnforce,
+									UnknownCSIDriverPolicy:        kubeschedulerconfig.UnknownCSIDriverStrict,
+									ScoreWeight:                   1,
+									EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+									FlexVolumeIdentityKeys:        []string{"volumeID"},
+								},
+							},
 						},
 					},
 				},
@@ -775,6 +785,16 @@ profiles:
 									BindTimeoutSeconds: 600,
 								},
 							},
+							{
+								Name: "VolumeRestrictions",
+								Args: &kubeschedulerconfig.VolumeRestrictionsArgs{
+									DiskConflictMode:              kubeschedulerconfig.DiskConflictEnforce,
+									UnknownCSIDriverPolicy:        kubeschedulerconfig.UnknownCSIDriverStrict,
+									ScoreWeight:                   1,
+									EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+									FlexVolumeIdentityKeys:        []string{"volumeID"},
+								},
+							},
 						},
 					},
 				},
@@ -909,6 +929,16 @@ profiles:
 									BindTimeoutSeconds: 600,
 								},
 							},
+							{
+								Name: "VolumeRestrictions",
+								Args: &kubeschedulerconfig.VolumeRestrictionsArgs{
+									DiskConflictMode:              kubeschedulerconfig.DiskConflictEnforce,
+									UnknownCSIDriverPolicy:        kubeschedulerconfig.UnknownCSIDriverStrict,
+									ScoreWeight:                   1,
+									EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+									FlexVolumeIdentityKeys:        []string{"volumeID"},
+								},
+							},
 						},
 					},
 				},
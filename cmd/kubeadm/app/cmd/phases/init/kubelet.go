@@ -68,8 +68,8 @@ func runKubeletStart(c workflow.RunData) error {
 	// Try to stop the kubelet service so no race conditions occur when configuring it
 	if !data.DryRun() {
 		klog.V(1).Infoln("Stopping the kubelet")
-		kubeletphase.TryStopKubelet()
 	}
+	kubeletphase.TryStopKubelet(data.DryRun())
 
 	// Write env file with flags for the kubelet to use. We do not need to write the --register-with-taints for the control-plane,
 	// as we handle that ourselves in the mark-control-plane phase
@@ -96,8 +96,8 @@ func runKubeletStart(c workflow.RunData) error {
 	// Try to start the kubelet service in case it's inactive
 	if !data.DryRun() {
 		fmt.Println("[kubelet-start] Starting the kubelet")
-		kubeletphase.TryStartKubelet()
 	}
+	kubeletphase.TryStartKubelet(data.DryRun())
 
 	return nil
 }
@@ -143,7 +143,7 @@ func runKubeletFinalizeEnableClientCertRotation(c workflow.RunData) error {
 
 	// Restart the kubelet.
 	klog.V(1).Info("[kubelet-finalize] Restarting the kubelet to enable client certificate rotation")
-	kubeletphase.TryRestartKubelet()
+	kubeletphase.TryRestartKubelet(data.DryRun())
 
 	return nil
 }
@@ -19,44 +19,24 @@ package phases
 import (
 	"fmt"
 	"io"
-	"text/template"
+	"path/filepath"
 	"time"
 
-	"github.com/lithammer/dedent"
 	"github.com/pkg/errors"
 
 	clientset "k8s.io/client-go/kubernetes"
 	kubeletconfig "k8s.io/kubelet/config/v1beta1"
+	utilsexec "k8s.io/utils/exec"
 
 	"k8s.io/kubernetes/cmd/kubeadm/app/cmd/phases/workflow"
 	"k8s.io/kubernetes/cmd/kubeadm/app/componentconfigs"
+	kubeadmconstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
 	"k8s.io/kubernetes/cmd/kubeadm/app/features"
+	kubeletphase "k8s.io/kubernetes/cmd/kubeadm/app/phases/kubelet"
 	"k8s.io/kubernetes/cmd/kubeadm/app/util/apiclient"
 	dryrunutil "k8s.io/kubernetes/cmd/kubeadm/app/util/dryrun"
 )
 
-var (
-	kubeletFailTempl = template.Must(template.New("init").Parse(dedent.Dedent(`
-	Unfortunately, an error has occurred:
-		{{ .Error }}
-
-	This error is likely caused by:
-		- The kubelet is not running
-		- The kubelet is unhealthy due to a misconfiguration of the node in some way (required cgroups disabled)
-
-	If you are on a systemd-powered system, you can try to troubleshoot the error with the following commands:
-		- 'systemctl status kubelet'
-		- 'journalctl -xeu kubelet'
-
-	Additionally, a control plane component may have crashed or exited when started by the container runtime.
-	To troubleshoot, list all containers using your preferred container runtimes CLI.
-	Here is one example how you may list all running Kubernetes containers by using crictl:
-		- 'crictl --runtime-endpoint {{ .Socket }} ps -a | grep kube | grep -v pause'
-		Once you have found the failing container, you can inspect its logs with:
-		- 'crictl --runtime-endpoint {{ .Socket }} logs CONTAINERID'
-	`)))
-)
-
 // NewWaitControlPlanePhase is a hidden phase that runs after the control-plane and etcd phases
 func NewWaitControlPlanePhase() workflow.Phase {
 	phase := workflow.Phase{
@@ -100,15 +80,14 @@ func runWaitControlPlanePhase(c workflow.RunData) error {
 		data.ManifestDir())
 
 	handleError := func(err error) error {
-		context := struct {
-			Error  string
-			Socket string
-		}{
-			Error:  fmt.Sprintf("%v", err),
-			Socket: data.Cfg().NodeRegistration.CRISocket,
+		kubeletphase.PrintKubeletErrorHelpScreen(data.OutputWriter(), data.Cfg().NodeRegistration.CRISocket, err)
+		if warning := kubeletphase.TimeSyncWarning(utilsexec.New()); warning != "" {
+			fmt.Fprintf(data.OutputWriter(), "\n%s\n", warning)
+		}
+		envFilePath := filepath.Join(kubeadmconstants.KubeletRunDirectory, kubeadmconstants.KubeletEnvFileName)
+		if cmdline, err := kubeletphase.RenderKubeletCommandLine(kubeletphase.KubeletSystemdUnitPath, envFilePath); err == nil {
+			fmt.Fprintf(data.OutputWriter(), "\nThe kubelet is started with the following command line:\n\t%s\n", cmdline)
 		}
-
-		kubeletFailTempl.Execute(data.OutputWriter(), context)
 		return errors.New("could not initialize a Kubernetes cluster")
 	}
 
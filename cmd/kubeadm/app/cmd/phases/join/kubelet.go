@@ -35,6 +35,7 @@ import (
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 	kubeletconfig "k8s.io/kubelet/config/v1beta1"
+	utilsexec "k8s.io/utils/exec"
 
 	kubeadmapi "k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm"
 	"k8s.io/kubernetes/cmd/kubeadm/app/cmd/options"
@@ -65,6 +66,14 @@ var (
 		`)
 )
 
+// printTimeSyncWarning prints a warning if the node clock is unsynchronized,
+// which commonly causes kubelet TLS bootstrap failures.
+func printTimeSyncWarning() {
+	if warning := kubeletphase.TimeSyncWarning(utilsexec.New()); warning != "" {
+		fmt.Printf("\n%s\n", warning)
+	}
+}
+
 // NewKubeletStartPhase creates a kubeadm workflow phase that start kubelet on a node.
 func NewKubeletStartPhase() workflow.Phase {
 	return workflow.Phase{
@@ -227,10 +236,10 @@ func runKubeletStartJoinPhase(c workflow.RunData) (returnErr error) {
 	// Try to stop the kubelet service so no race conditions occur when configuring it
 	if !data.DryRun() {
 		klog.V(1).Infoln("[kubelet-start] Stopping the kubelet")
-		kubeletphase.TryStopKubelet()
 	} else {
 		fmt.Println("[kubelet-start] Would stop the kubelet")
 	}
+	kubeletphase.TryStopKubelet(data.DryRun())
 
 	// Write the instance kubelet configuration file to disk.
 	if features.Enabled(initCfg.FeatureGates, features.NodeLocalCRISocket) {
@@ -267,7 +276,7 @@ func runKubeletStartJoinPhase(c workflow.RunData) (returnErr error) {
 
 	// Try to start the kubelet service in case it's inactive
 	fmt.Println("[kubelet-start] Starting the kubelet")
-	kubeletphase.TryStartKubelet()
+	kubeletphase.TryStartKubelet(data.DryRun())
 
 	// Run the same code as KubeletWaitBootstrapPhase would do if the ControlPlaneKubeletLocalMode feature gate is disabled.
 	if !features.Enabled(initCfg.FeatureGates, features.ControlPlaneKubeletLocalMode) {
@@ -319,11 +328,13 @@ func runKubeletWaitBootstrapPhase(c workflow.RunData) (returnErr error) {
 	}
 	if err := waiter.WaitForKubelet(kubeletConfigTyped.HealthzBindAddress, *kubeletConfigTyped.HealthzPort); err != nil {
 		fmt.Printf(kubeadmJoinFailMsg, err)
+		printTimeSyncWarning()
 		return err
 	}
 
 	if err := waitForTLSBootstrappedClient(cfg.Timeouts.TLSBootstrap.Duration); err != nil {
 		fmt.Printf(kubeadmJoinFailMsg, err)
+		printTimeSyncWarning()
 		return err
 	}
 
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// KubeletSystemdUnitPath is the conventional location of the kubelet systemd unit
+// installed by the kubelet packages.
+const KubeletSystemdUnitPath = "/usr/lib/systemd/system/kubelet.service"
+
+// RenderKubeletCommandLine assembles the effective kubelet command line from the
+// systemd unit at unitPath, its drop-in files, their Environment directives and
+// the environment file at envFilePath, the same way systemd would when starting
+// the service. The result
+// can be shown on the kubelet error help screen to speed up diagnosis of kubelet
+// startup crashes. A missing environment file is tolerated; variables it would
+// have provided expand to the empty string.
+func RenderKubeletCommandLine(unitPath, envFilePath string) (string, error) {
+	return renderKubeletCommandLine(unitPath, envFilePath, os.ReadFile, os.ReadDir)
+}
+
+// renderKubeletCommandLine is separated out only for test purposes, DON'T call it directly, use RenderKubeletCommandLine instead
+func renderKubeletCommandLine(unitPath, envFilePath string, readFile func(string) ([]byte, error), readDir func(string) ([]os.DirEntry, error)) (string, error) {
+	unit, err := readFile(unitPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read the kubelet unit file %s", unitPath)
+	}
+	contents := [][]byte{unit}
+
+	// Drop-in files override the unit in lexical order, matching systemd behavior.
+	dropinDir := unitPath + ".d"
+	if entries, err := readDir(dropinDir); err == nil {
+		names := []string{}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+				continue
+			}
+			names = append(names, entry.Name())
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dropin, err := readFile(filepath.Join(dropinDir, name))
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to read the kubelet drop-in file %s", filepath.Join(dropinDir, name))
+			}
+			contents = append(contents, dropin)
+		}
+	}
+
+	execStart := ""
+	for _, content := range contents {
+		if value, ok := parseExecStart(string(content)); ok {
+			execStart = value
+		}
+	}
+	if execStart == "" {
+		return "", errors.Errorf("no ExecStart directive found in the unit file %s or its drop-ins", unitPath)
+	}
+
+	env := map[string]string{}
+	for _, content := range contents {
+		for key, value := range parseEnvironmentDirectives(string(content)) {
+			env[key] = value
+		}
+	}
+	if envFilePath != "" {
+		if envContent, err := readFile(envFilePath); err == nil {
+			for key, value := range parseEnvFile(string(envContent)) {
+				env[key] = value
+			}
+		} else if !os.IsNotExist(err) {
+			return "", errors.Wrapf(err, "failed to read the kubelet environment file %s", envFilePath)
+		}
+	}
+
+	expanded := os.Expand(execStart, func(variable string) string {
+		return env[variable]
+	})
+	return strings.Join(strings.Fields(expanded), " "), nil
+}
+
+// parseExecStart returns the value of the last ExecStart directive in a unit
+// file, honoring line continuations. The second return value reports whether
+// any ExecStart directive was found; an empty assignment resets the command,
+// as it does for systemd.
+func parseExecStart(content string) (string, bool) {
+	value := ""
+	found := false
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + " " + strings.TrimSpace(lines[i])
+		}
+		if !strings.HasPrefix(line, "ExecStart=") {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimPrefix(line, "ExecStart="))
+		found = true
+	}
+	return value, found
+}
+
+// parseEnvironmentDirectives collects the variable assignments made by the
+// Environment directives of a unit file.
+func parseEnvironmentDirectives(content string) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "Environment=") {
+			continue
+		}
+		for _, assignment := range splitEnvironmentValue(strings.TrimPrefix(line, "Environment=")) {
+			if key, value, ok := strings.Cut(assignment, "="); ok {
+				env[key] = value
+			}
+		}
+	}
+	return env
+}
+
+// splitEnvironmentValue splits the value of an Environment directive into its
+// space separated, optionally quoted, KEY=VALUE assignments.
+func splitEnvironmentValue(value string) []string {
+	var assignments []string
+	for value != "" {
+		value = strings.TrimLeft(value, " \t")
+		if value == "" {
+			break
+		}
+		if value[0] == '"' {
+			end := strings.IndexByte(value[1:], '"')
+			if end < 0 {
+				assignments = append(assignments, value[1:])
+				break
+			}
+			assignments = append(assignments, value[1:1+end])
+			value = value[end+2:]
+			continue
+		}
+		sep := strings.IndexAny(value, " \t")
+		if sep < 0 {
+			assignments = append(assignments, value)
+			break
+		}
+		assignments = append(assignments, value[:sep])
+		value = value[sep:]
+	}
+	return assignments
+}
+
+// parseEnvFile parses the KEY=VALUE lines of a systemd environment file,
+// stripping surrounding quotes from the values.
+func parseEnvFile(content string) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"' || value[0] == '\'' && value[len(value)-1] == '\'') {
+			value = value[1 : len(value)-1]
+		}
+		env[strings.TrimSpace(key)] = value
+	}
+	return env
+}
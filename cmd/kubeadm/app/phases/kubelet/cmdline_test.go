@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/lithammer/dedent"
+)
+
+type fakeDirEntry struct {
+	name string
+	dir  bool
+}
+
+func (e fakeDirEntry) Name() string               { return e.name }
+func (e fakeDirEntry) IsDir() bool                { return e.dir }
+func (e fakeDirEntry) Type() fs.FileMode          { return 0 }
+func (e fakeDirEntry) Info() (fs.FileInfo, error) { return nil, nil }
+
+func TestRenderKubeletCommandLine(t *testing.T) {
+	unit := dedent.Dedent(`
+		[Unit]
+		Description=kubelet: The Kubernetes Node Agent
+
+		[Service]
+		ExecStart=/usr/bin/kubelet
+	`)
+	dropin := dedent.Dedent(`
+		[Service]
+		Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
+		Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
+		EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
+		ExecStart=
+		ExecStart=/usr/bin/kubelet $KUBELET_KUBECONFIG_ARGS $KUBELET_CONFIG_ARGS $KUBELET_KUBEADM_ARGS $KUBELET_EXTRA_ARGS
+	`)
+	dropinContinuation := dedent.Dedent(`
+		[Service]
+		ExecStart=
+		ExecStart=/usr/bin/kubelet \
+		    $KUBELET_KUBEADM_ARGS
+	`)
+	envFile := dedent.Dedent(`
+		# Written by kubeadm init.
+		KUBELET_KUBEADM_ARGS="--container-runtime-endpoint=unix:///var/run/containerd/containerd.sock --pod-infra-container-image=registry.k8s.io/pause:3.10"
+	`)
+
+	tests := []struct {
+		name          string
+		files         map[string]string
+		dirs          map[string][]os.DirEntry
+		unitPath      string
+		envFilePath   string
+		expected      string
+		expectedError bool
+	}{
+		{
+			name: "unit with drop-in and environment file",
+			files: map[string]string{
+				"/usr/lib/systemd/system/kubelet.service":                   unit,
+				"/usr/lib/systemd/system/kubelet.service.d/10-kubeadm.conf": dropin,
+				"/var/lib/kubelet/kubeadm-flags.env":                        envFile,
+			},
+			dirs: map[string][]os.DirEntry{
+				"/usr/lib/systemd/system/kubelet.service.d": {fakeDirEntry{name: "10-kubeadm.conf"}},
+			},
+			unitPath:    "/usr/lib/systemd/system/kubelet.service",
+			envFilePath: "/var/lib/kubelet/kubeadm-flags.env",
+			expected: "/usr/bin/kubelet --bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf " +
+				"--config=/var/lib/kubelet/config.yaml " +
+				"--container-runtime-endpoint=unix:///var/run/containerd/containerd.sock --pod-infra-container-image=registry.k8s.io/pause:3.10",
+		},
+		{
+			name: "unit without drop-ins",
+			files: map[string]string{
+				"/usr/lib/systemd/system/kubelet.service": unit,
+			},
+			unitPath: "/usr/lib/systemd/system/kubelet.service",
+			expected: "/usr/bin/kubelet",
+		},
+		{
+			name: "drop-in with line continuation",
+			files: map[string]string{
+				"/usr/lib/systemd/system/kubelet.service":                   unit,
+				"/usr/lib/systemd/system/kubelet.service.d/10-kubeadm.conf": dropinContinuation,
+				"/var/lib/kubelet/kubeadm-flags.env":                        envFile,
+			},
+			dirs: map[string][]os.DirEntry{
+				"/usr/lib/systemd/system/kubelet.service.d": {fakeDirEntry{name: "10-kubeadm.conf"}},
+			},
+			unitPath:    "/usr/lib/systemd/system/kubelet.service",
+			envFilePath: "/var/lib/kubelet/kubeadm-flags.env",
+			expected:    "/usr/bin/kubelet --container-runtime-endpoint=unix:///var/run/containerd/containerd.sock --pod-infra-container-image=registry.k8s.io/pause:3.10",
+		},
+		{
+			name: "missing environment file is tolerated",
+			files: map[string]string{
+				"/usr/lib/systemd/system/kubelet.service":                   unit,
+				"/usr/lib/systemd/system/kubelet.service.d/10-kubeadm.conf": dropin,
+			},
+			dirs: map[string][]os.DirEntry{
+				"/usr/lib/systemd/system/kubelet.service.d": {fakeDirEntry{name: "10-kubeadm.conf"}},
+			},
+			unitPath:    "/usr/lib/systemd/system/kubelet.service",
+			envFilePath: "/var/lib/kubelet/kubeadm-flags.env",
+			expected: "/usr/bin/kubelet --bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf " +
+				"--config=/var/lib/kubelet/config.yaml",
+		},
+		{
+			name:          "missing unit file",
+			files:         map[string]string{},
+			unitPath:      "/usr/lib/systemd/system/kubelet.service",
+			expectedError: true,
+		},
+		{
+			name: "no ExecStart directive",
+			files: map[string]string{
+				"/usr/lib/systemd/system/kubelet.service": "[Service]\n",
+			},
+			unitPath:      "/usr/lib/systemd/system/kubelet.service",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			readFile := func(path string) ([]byte, error) {
+				content, ok := test.files[path]
+				if !ok {
+					return nil, os.ErrNotExist
+				}
+				return []byte(content), nil
+			}
+			readDir := func(path string) ([]os.DirEntry, error) {
+				entries, ok := test.dirs[path]
+				if !ok {
+					return nil, os.ErrNotExist
+				}
+				return entries, nil
+			}
+
+			cmdline, err := renderKubeletCommandLine(test.unitPath, test.envFilePath, readFile, readDir)
+
+			if (err != nil) != test.expectedError {
+				t.Fatalf("renderKubeletCommandLine returned unexpected result\n\tExpected error: %t\n\tGot error: %v", test.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if cmdline != test.expected {
+				t.Fatalf("renderKubeletCommandLine returned unexpected command line\n\tExpected: %s\n\tGot: %s", test.expected, cmdline)
+			}
+		})
+	}
+}
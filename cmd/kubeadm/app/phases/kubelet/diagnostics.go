@@ -0,0 +1,249 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	utilsexec "k8s.io/utils/exec"
+
+	"k8s.io/kubernetes/cmd/kubeadm/app/util/initsystem"
+)
+
+// DiagnosticsOptions selects which kubelet diagnostics to run and carries
+// their inputs. Each check is independently toggleable; disabled checks are
+// reported as skipped so the report always covers the full set.
+type DiagnosticsOptions struct {
+	// CheckService verifies via the init system that the kubelet service is active.
+	CheckService bool
+
+	// CheckVersion verifies that the kubelet binary on PATH reports the
+	// expected version, catching stale binaries left behind by upgrades.
+	CheckVersion           bool
+	ExpectedKubeletVersion string
+
+	// CheckHealthz waits for the kubelet healthz endpoint to report ok.
+	CheckHealthz   bool
+	HealthzAddress string
+	HealthzPort    int32
+	HealthzTimeout time.Duration
+
+	// CheckRuntime verifies that the container runtime is reachable and that
+	// its configuration, including the cgroup driver, matches the expectations.
+	CheckRuntime              bool
+	RuntimeSocket             string
+	RuntimeConfigExpectations map[string]string
+
+	// CheckSysctls verifies that the sysctls the kubelet requires are set to
+	// the expected values.
+	CheckSysctls    bool
+	RequiredSysctls map[string]string
+
+	// CheckDiskSpace verifies free space and writability of the kubelet data directory.
+	CheckDiskSpace bool
+	KubeletDataDir string
+	MinFreeBytes   int64
+
+	// CheckPorts verifies that the kubelet's ports are not occupied by another process.
+	CheckPorts bool
+	Ports      []int
+
+	// CheckTimeSync verifies that the node clock is synchronized.
+	CheckTimeSync bool
+	Execer        utilsexec.Interface
+}
+
+// DiagnosticResult holds the outcome of one kubelet diagnostic check.
+type DiagnosticResult struct {
+	// Name identifies the check, e.g. "healthz" or "disk-space".
+	Name string
+	// Skipped is true if the check was disabled or the diagnostics run was
+	// cancelled before the check started.
+	Skipped bool
+	// Error holds the failure message; empty if the check passed or was skipped.
+	Error string
+}
+
+// DiagnosticsReport is the structured outcome of a kubelet diagnostics run.
+type DiagnosticsReport struct {
+	Results []DiagnosticResult
+}
+
+// Passed returns true if no executed check failed.
+func (r *DiagnosticsReport) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Skipped && result.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary renders the report as text suitable for showing to the user next
+// to the kubelet troubleshooting instructions.
+func (r *DiagnosticsReport) Summary() string {
+	var b strings.Builder
+	b.WriteString("The kubelet diagnostics produced the following results:\n")
+	for _, result := range r.Results {
+		switch {
+		case result.Skipped:
+			fmt.Fprintf(&b, "\t[skipped] %s\n", result.Name)
+		case result.Error != "":
+			fmt.Fprintf(&b, "\t[failed] %s: %s\n", result.Name, result.Error)
+		default:
+			fmt.Fprintf(&b, "\t[passed] %s\n", result.Name)
+		}
+	}
+	return b.String()
+}
+
+// kubeletDiagnostic pairs a check name with the function executing it. A nil
+// run function marks the check as disabled.
+type kubeletDiagnostic struct {
+	name string
+	run  func() error
+}
+
+// RunKubeletDiagnostics runs the selected kubelet checks and returns a
+// structured report. Checks run sequentially; cancelling ctx marks the
+// remaining checks as skipped.
+func RunKubeletDiagnostics(ctx context.Context, opts DiagnosticsOptions) DiagnosticsReport {
+	return runKubeletDiagnostics(ctx, diagnosticsForOptions(opts))
+}
+
+// runKubeletDiagnostics is separated out only for test purposes, DON'T call it directly,
+// use RunKubeletDiagnostics instead.
+func runKubeletDiagnostics(ctx context.Context, diagnostics []kubeletDiagnostic) DiagnosticsReport {
+	report := DiagnosticsReport{}
+	for _, diagnostic := range diagnostics {
+		result := DiagnosticResult{Name: diagnostic.name}
+		if diagnostic.run == nil || ctx.Err() != nil {
+			result.Skipped = true
+		} else if err := diagnostic.run(); err != nil {
+			result.Error = err.Error()
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// diagnosticsForOptions translates the options into the list of checks to run,
+// in a fixed order so that reports are comparable across runs.
+func diagnosticsForOptions(opts DiagnosticsOptions) []kubeletDiagnostic {
+	diagnostics := []kubeletDiagnostic{
+		{name: "service"},
+		{name: "version"},
+		{name: "healthz"},
+		{name: "runtime"},
+		{name: "sysctls"},
+		{name: "disk-space"},
+		{name: "ports"},
+		{name: "time-sync"},
+	}
+	runs := map[string]func() error{}
+	if opts.CheckService {
+		runs["service"] = checkKubeletServiceActive
+	}
+	if opts.CheckVersion {
+		runs["version"] = func() error {
+			actual, ok, err := CheckKubeletVersion(opts.ExpectedKubeletVersion)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.Errorf("the kubelet binary reports version %s, but version %s is expected", actual, opts.ExpectedKubeletVersion)
+			}
+			return nil
+		}
+	}
+	if opts.CheckHealthz {
+		runs["healthz"] = func() error {
+			return WaitForKubeletHealthz(opts.HealthzAddress, opts.HealthzPort, opts.HealthzTimeout)
+		}
+	}
+	if opts.CheckRuntime {
+		runs["runtime"] = func() error {
+			mismatches, err := CheckRuntimeConfig(opts.RuntimeSocket, opts.RuntimeConfigExpectations)
+			if err != nil {
+				return err
+			}
+			if len(mismatches) > 0 {
+				return errors.Errorf("the runtime configuration does not match the expectations: %v", mismatches)
+			}
+			return nil
+		}
+	}
+	if opts.CheckSysctls {
+		runs["sysctls"] = func() error {
+			mismatches, err := CheckKubeletSysctls(opts.RequiredSysctls)
+			if err != nil {
+				return err
+			}
+			if len(mismatches) > 0 {
+				return errors.Errorf("the following sysctls do not match the kubelet's expectations: %v", mismatches)
+			}
+			return nil
+		}
+	}
+	if opts.CheckDiskSpace {
+		runs["disk-space"] = func() error {
+			return CheckKubeletDiskSpace(opts.KubeletDataDir, opts.MinFreeBytes)
+		}
+	}
+	if opts.CheckPorts {
+		runs["ports"] = func() error {
+			occupied, err := CheckKubeletPortsFree(opts.Ports)
+			if err != nil {
+				return err
+			}
+			if len(occupied) > 0 {
+				return errors.Errorf("the following ports are occupied by another process: %v", occupied)
+			}
+			return nil
+		}
+	}
+	if opts.CheckTimeSync {
+		runs["time-sync"] = func() error {
+			if warning := TimeSyncWarning(opts.Execer); warning != "" {
+				return errors.New(warning)
+			}
+			return nil
+		}
+	}
+	for i := range diagnostics {
+		diagnostics[i].run = runs[diagnostics[i].name]
+	}
+	return diagnostics
+}
+
+// checkKubeletServiceActive verifies via the init system that the kubelet
+// service is currently active.
+func checkKubeletServiceActive() error {
+	initSystem, err := initsystem.GetInitSystem()
+	if err != nil {
+		return errors.Wrap(err, "failed to detect the init system")
+	}
+	if !initSystem.ServiceIsActive("kubelet") {
+		return errors.New("the kubelet service is not active")
+	}
+	return nil
+}
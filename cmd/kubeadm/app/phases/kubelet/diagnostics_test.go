@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRunKubeletDiagnostics(t *testing.T) {
+	pass := func() error { return nil }
+	fail := func(msg string) func() error {
+		return func() error { return errors.New(msg) }
+	}
+
+	tests := []struct {
+		name            string
+		diagnostics     []kubeletDiagnostic
+		cancelled       bool
+		expectedResults []DiagnosticResult
+		expectedPassed  bool
+	}{
+		{
+			name: "all checks pass",
+			diagnostics: []kubeletDiagnostic{
+				{name: "healthz", run: pass},
+				{name: "ports", run: pass},
+			},
+			expectedResults: []DiagnosticResult{
+				{Name: "healthz"},
+				{Name: "ports"},
+			},
+			expectedPassed: true,
+		},
+		{
+			name: "failed check is reported and the rest still run",
+			diagnostics: []kubeletDiagnostic{
+				{name: "healthz", run: fail("the kubelet is not healthy")},
+				{name: "ports", run: pass},
+			},
+			expectedResults: []DiagnosticResult{
+				{Name: "healthz", Error: "the kubelet is not healthy"},
+				{Name: "ports"},
+			},
+			expectedPassed: false,
+		},
+		{
+			name: "disabled check is skipped",
+			diagnostics: []kubeletDiagnostic{
+				{name: "healthz", run: pass},
+				{name: "ports"},
+			},
+			expectedResults: []DiagnosticResult{
+				{Name: "healthz"},
+				{Name: "ports", Skipped: true},
+			},
+			expectedPassed: true,
+		},
+		{
+			name: "cancelled context skips all checks",
+			diagnostics: []kubeletDiagnostic{
+				{name: "healthz", run: fail("should not run")},
+			},
+			cancelled: true,
+			expectedResults: []DiagnosticResult{
+				{Name: "healthz", Skipped: true},
+			},
+			expectedPassed: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			report := runKubeletDiagnostics(ctx, tc.diagnostics)
+			if !reflect.DeepEqual(report.Results, tc.expectedResults) {
+				t.Errorf("expected results %+v, got %+v", tc.expectedResults, report.Results)
+			}
+			if report.Passed() != tc.expectedPassed {
+				t.Errorf("expected Passed() to return %v, got %v", tc.expectedPassed, report.Passed())
+			}
+		})
+	}
+}
+
+func TestDiagnosticsReportSummary(t *testing.T) {
+	report := DiagnosticsReport{
+		Results: []DiagnosticResult{
+			{Name: "healthz"},
+			{Name: "ports", Error: "the following ports are occupied by another process: [10250]"},
+			{Name: "time-sync", Skipped: true},
+		},
+	}
+
+	summary := report.Summary()
+	for _, line := range []string{
+		"[passed] healthz",
+		"[failed] ports: the following ports are occupied by another process: [10250]",
+		"[skipped] time-sync",
+	} {
+		if !strings.Contains(summary, line) {
+			t.Errorf("expected summary to contain %q, got:\n%s", line, summary)
+		}
+	}
+}
+
+func TestDiagnosticsForOptions(t *testing.T) {
+	diagnostics := diagnosticsForOptions(DiagnosticsOptions{
+		CheckHealthz:   true,
+		CheckDiskSpace: true,
+	})
+
+	enabled := map[string]bool{}
+	for _, diagnostic := range diagnostics {
+		enabled[diagnostic.name] = diagnostic.run != nil
+	}
+	expected := map[string]bool{
+		"service":    false,
+		"version":    false,
+		"healthz":    true,
+		"runtime":    false,
+		"sysctls":    false,
+		"disk-space": true,
+		"ports":      false,
+		"time-sync":  false,
+	}
+	if !reflect.DeepEqual(expected, enabled) {
+		t.Errorf("expected enabled checks %v, got %v", expected, enabled)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// CheckKubeletDiskSpace verifies that the filesystem backing path has at least
+// minFreeBytes of free space and accepts writes. The kubelet fails in opaque
+// ways when its data directory sits on a full or read-only disk, so the
+// returned error spells out which of the two conditions was hit.
+func CheckKubeletDiskSpace(path string, minFreeBytes int64) error {
+	return checkKubeletDiskSpace(path, minFreeBytes, statDiskFree, probeWrite)
+}
+
+// checkKubeletDiskSpace is separated out only for test purposes, DON'T call it directly,
+// use CheckKubeletDiskSpace instead.
+func checkKubeletDiskSpace(path string, minFreeBytes int64, statFree func(path string) (int64, error), probe func(path string) error) error {
+	freeBytes, err := statFree(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat the filesystem backing %q", path)
+	}
+	if freeBytes < minFreeBytes {
+		return errors.Errorf("the filesystem backing %q has %d bytes free, but at least %d bytes are required; "+
+			"free up disk space on this filesystem before proceeding", path, freeBytes, minFreeBytes)
+	}
+	if err := probe(path); err != nil {
+		return errors.Wrapf(err, "cannot write to %q; the filesystem may be mounted read-only", path)
+	}
+	return nil
+}
+
+// probeWrite verifies that path accepts writes by creating and removing a temporary file.
+func probeWrite(path string) error {
+	f, err := os.CreateTemp(path, ".kubeadm-disk-check-")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
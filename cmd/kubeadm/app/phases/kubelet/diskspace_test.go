@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckKubeletDiskSpace(t *testing.T) {
+	tests := []struct {
+		name          string
+		freeBytes     int64
+		statError     error
+		probeError    error
+		expectedError bool
+		expectedHint  string
+	}{
+		{
+			name:      "healthy disk",
+			freeBytes: 10 * 1024 * 1024 * 1024,
+		},
+		{
+			name:          "full disk",
+			freeBytes:     1024,
+			expectedError: true,
+			expectedHint:  "free up disk space",
+		},
+		{
+			name:          "read-only filesystem",
+			freeBytes:     10 * 1024 * 1024 * 1024,
+			probeError:    errors.New("read-only file system"),
+			expectedError: true,
+			expectedHint:  "may be mounted read-only",
+		},
+		{
+			name:          "stat failure",
+			statError:     errors.New("no such file or directory"),
+			expectedError: true,
+			expectedHint:  "failed to stat the filesystem",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			statFree := func(string) (int64, error) {
+				return tc.freeBytes, tc.statError
+			}
+			probe := func(string) error {
+				return tc.probeError
+			}
+
+			err := checkKubeletDiskSpace("/var/lib/kubelet", 100*1024*1024, statFree, probe)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), tc.expectedHint) {
+				t.Errorf("expected error to contain %q, got: %v", tc.expectedHint, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/lithammer/dedent"
+
+	kubeadmconstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	"k8s.io/kubernetes/cmd/kubeadm/app/util/initsystem"
+	utilruntime "k8s.io/kubernetes/cmd/kubeadm/app/util/runtime"
+)
+
+var (
+	// kubeletFailMsg is the general kubelet troubleshooting text shown when
+	// kubeadm gives up waiting for the kubelet. The init-system specific
+	// status and log command hints follow it.
+	kubeletFailMsg = dedent.Dedent(`
+	This error is likely caused by:
+		- The kubelet is not running
+		- The kubelet is unhealthy due to a misconfiguration of the node in some way (required cgroups disabled)
+	`)
+
+	// controlPlaneFailTempl renders the control plane troubleshooting
+	// examples, one line per candidate CRI endpoint. It is executed with
+	// TroubleshootingTemplateData and defaults to crictl examples.
+	controlPlaneFailTempl = template.Must(template.New("init").Parse(dedent.Dedent(`
+	Additionally, a control plane component may have crashed or exited when started by the container runtime.
+	To troubleshoot, list all containers using your preferred container runtimes CLI.
+	Here is one example how you may list all running Kubernetes containers by using {{ .CLIBinary }}:
+	{{- range .Sockets }}
+		- '{{ $.CLIBinary }} --runtime-endpoint {{ . }} ps -a | grep kube | {{ $.PauseFilter }}'
+	{{- end }}
+		Once you have found the failing container, you can inspect its logs with:
+	{{- range .Sockets }}
+		- '{{ $.CLIBinary }} --runtime-endpoint {{ . }} logs CONTAINERID'
+	{{- end }}
+	`)))
+
+	// sandboxInspectTempl renders a ready-to-run crictl command sequence
+	// inspecting a failed pod sandbox and the logs of its containers.
+	sandboxInspectTempl = template.Must(template.New("sandbox").Parse(dedent.Dedent(`
+	To inspect the most recently failed pod sandbox and the logs of its containers, run:
+		- 'crictl --runtime-endpoint {{ .Socket }} inspectp {{ .SandboxID }}'
+		- 'crictl --runtime-endpoint {{ .Socket }} ps -a --pod {{ .SandboxID }}'
+		- 'crictl --runtime-endpoint {{ .Socket }} logs CONTAINERID'
+	`)))
+)
+
+// TroubleshootingTemplateData is the data the control plane troubleshooting
+// template is rendered with.
+type TroubleshootingTemplateData struct {
+	// Sockets are the candidate CRI endpoints the examples cover.
+	Sockets []string
+	// CLIBinary is the container CLI the examples invoke.
+	CLIBinary string
+	// PauseFilter is the shell filter the listing examples pipe through to
+	// exclude pause containers.
+	PauseFilter string
+}
+
+// TroubleshootingOptions customizes the control plane troubleshooting block of
+// the kubelet error help screen, for operators whose nodes use a container
+// CLI other than crictl.
+type TroubleshootingOptions struct {
+	// Template overrides the built-in troubleshooting template. It is
+	// executed with TroubleshootingTemplateData; nil keeps the built-in
+	// template.
+	Template *template.Template
+	// CLIBinary is the container CLI the examples invoke. An empty string
+	// keeps "crictl".
+	CLIBinary string
+	// PauseFilter is the shell filter excluding pause containers from the
+	// listing examples. An empty string keeps "grep -v pause".
+	PauseFilter string
+}
+
+// troubleshootingOptions holds the active customizations of the help screen.
+// The zero value selects the built-in crictl examples.
+var troubleshootingOptions TroubleshootingOptions
+
+// SetTroubleshootingOptions customizes the control plane troubleshooting
+// block rendered by PrintKubeletErrorHelpScreen. Calling it with the zero
+// value restores the built-in crictl examples, which is the default.
+func SetTroubleshootingOptions(opts TroubleshootingOptions) {
+	troubleshootingOptions = opts
+}
+
+// CRICommandRunner runs a CRI CLI command and returns its combined output.
+// It is injected where kubeadm is allowed to consult the container runtime
+// while assembling troubleshooting output.
+type CRICommandRunner func(command string, args ...string) (string, error)
+
+// PrintFailedSandboxInspectCommands prints a crictl command sequence for
+// inspecting the most recently failed pod sandbox behind the given CRI
+// endpoint. When runner is non-nil it is used to resolve the sandbox ID by
+// listing the not-ready sandboxes; without a runner, or when the lookup fails
+// or finds nothing, the commands are rendered with a placeholder sandbox ID
+// so they remain ready to adapt.
+func PrintFailedSandboxInspectCommands(outputWriter io.Writer, criSocket string, runner CRICommandRunner) {
+	sandboxID := "SANDBOXID"
+	if runner != nil {
+		if out, err := runner("crictl", "--runtime-endpoint", criSocket, "pods", "--state", "NotReady", "--latest", "--quiet"); err == nil {
+			if id := strings.TrimSpace(out); id != "" {
+				sandboxID = id
+			}
+		}
+	}
+	_ = sandboxInspectTempl.Execute(outputWriter, struct{ Socket, SandboxID string }{Socket: criSocket, SandboxID: sandboxID})
+}
+
+// kubeletTroubleshootingHints renders the status and log command hints for
+// the kubelet service, matching the node's init system. The systemd wording
+// is kept as the default when no supported init system is detected.
+func kubeletTroubleshootingHints(getInitSystem func() (initsystem.InitSystem, error)) string {
+	name := "systemd"
+	commands := []string{
+		fmt.Sprintf("systemctl status %s", kubeadmconstants.Kubelet),
+		fmt.Sprintf("journalctl -xeu %s", kubeadmconstants.Kubelet),
+	}
+	if initSystem, err := getInitSystem(); err == nil {
+		name = initSystem.Name()
+		commands = initSystem.LogCommands(kubeadmconstants.Kubelet)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\nIf you are on a %s-powered system, you can try to troubleshoot the error with the following commands:\n", name)
+	for _, command := range commands {
+		fmt.Fprintf(&sb, "\t- '%s'\n", command)
+	}
+	return sb.String()
+}
+
+// PrintKubeletErrorHelpScreen prints a help screen explaining a kubelet
+// failure and how to troubleshoot it. When criSocket is empty, the well-known
+// CRI endpoints are scanned and the crictl examples are printed for every
+// endpoint found, so the examples are correct even when the socket was never
+// configured.
+func PrintKubeletErrorHelpScreen(outputWriter io.Writer, criSocket string, err error) {
+	printKubeletErrorHelpScreen(outputWriter, criSocket, err, utilruntime.DetectCRISockets, initsystem.GetInitSystem)
+}
+
+// printKubeletErrorHelpScreen is separated out only for test purposes, DON'T call it directly, use PrintKubeletErrorHelpScreen instead.
+func printKubeletErrorHelpScreen(outputWriter io.Writer, criSocket string, err error, detectSockets func() []string, getInitSystem func() (initsystem.InitSystem, error)) {
+	fmt.Fprintf(outputWriter, "\nUnfortunately, an error has occurred:\n\t%v\n", err)
+	fmt.Fprint(outputWriter, kubeletFailMsg)
+	fmt.Fprint(outputWriter, kubeletTroubleshootingHints(getInitSystem))
+
+	sockets := []string{criSocket}
+	if criSocket == "" {
+		sockets = detectSockets()
+	}
+	if len(sockets) == 0 {
+		// No runtime was detected; show the examples with a placeholder so
+		// the user knows the endpoint has to be filled in.
+		sockets = []string{"<CRI endpoint>"}
+	}
+	data := TroubleshootingTemplateData{
+		Sockets:     sockets,
+		CLIBinary:   troubleshootingOptions.CLIBinary,
+		PauseFilter: troubleshootingOptions.PauseFilter,
+	}
+	if data.CLIBinary == "" {
+		data.CLIBinary = "crictl"
+	}
+	if data.PauseFilter == "" {
+		data.PauseFilter = "grep -v pause"
+	}
+	tmpl := troubleshootingOptions.Template
+	if tmpl == nil {
+		tmpl = controlPlaneFailTempl
+	}
+	_ = tmpl.Execute(outputWriter, data)
+	for _, socket := range sockets {
+		// The help screen never executes crictl itself, so the sandbox
+		// inspect commands are rendered with a placeholder sandbox ID.
+		PrintFailedSandboxInspectCommands(outputWriter, socket, nil)
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/lithammer/dedent"
+	"github.com/pkg/errors"
+
+	"k8s.io/kubernetes/cmd/kubeadm/app/util/initsystem"
+)
+
+// noInitSystem stands in for init system detection failing, which keeps the
+// systemd hints as the default.
+func noInitSystem() (initsystem.InitSystem, error) {
+	return nil, errors.New("no supported init system detected")
+}
+
+func TestPrintKubeletErrorHelpScreen(t *testing.T) {
+	tests := []struct {
+		name            string
+		criSocket       string
+		detectedSockets []string
+		expectedLines   []string
+		unexpectedLines []string
+	}{
+		{
+			name:      "an explicitly passed socket is used as is",
+			criSocket: "unix:///var/run/containerd/containerd.sock",
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock ps -a",
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock logs CONTAINERID",
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock inspectp SANDBOXID",
+			},
+		},
+		{
+			name:            "an empty socket is filled in by detection",
+			detectedSockets: []string{"unix:///var/run/crio/crio.sock"},
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/crio/crio.sock ps -a",
+			},
+		},
+		{
+			name:            "multiple detected runtimes print a line per candidate",
+			detectedSockets: []string{"unix:///var/run/containerd/containerd.sock", "unix:///var/run/crio/crio.sock"},
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock ps -a",
+				"crictl --runtime-endpoint unix:///var/run/crio/crio.sock ps -a",
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock logs CONTAINERID",
+				"crictl --runtime-endpoint unix:///var/run/crio/crio.sock logs CONTAINERID",
+			},
+		},
+		{
+			name: "no detected runtime prints a placeholder",
+			expectedLines: []string{
+				"crictl --runtime-endpoint <CRI endpoint> ps -a",
+			},
+			unexpectedLines: []string{
+				"crictl --runtime-endpoint unix://",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out strings.Builder
+			detect := func() []string {
+				return tc.detectedSockets
+			}
+
+			printKubeletErrorHelpScreen(&out, tc.criSocket, errors.New("the kubelet is not healthy"), detect, noInitSystem)
+
+			output := out.String()
+			if !strings.Contains(output, "the kubelet is not healthy") {
+				t.Errorf("expected the output to contain the error, got:\n%s", output)
+			}
+			for _, line := range tc.expectedLines {
+				if !strings.Contains(output, line) {
+					t.Errorf("expected the output to contain %q, got:\n%s", line, output)
+				}
+			}
+			for _, line := range tc.unexpectedLines {
+				if strings.Contains(output, line) {
+					t.Errorf("expected the output to not contain %q, got:\n%s", line, output)
+				}
+			}
+		})
+	}
+}
+
+// hintedInitSystem is a fakeInitSystem with a configurable name and log
+// command hints, standing in for the per-platform init systems.
+type hintedInitSystem struct {
+	fakeInitSystem
+	name     string
+	commands []string
+}
+
+func (h *hintedInitSystem) Name() string                        { return h.name }
+func (h *hintedInitSystem) LogCommands(service string) []string { return h.commands }
+
+func TestPrintKubeletErrorHelpScreenInitSystemHints(t *testing.T) {
+	tests := []struct {
+		name            string
+		initSystem      initsystem.InitSystem
+		expectedLines   []string
+		unexpectedLines []string
+	}{
+		{
+			name: "no init system detected keeps the systemd hints",
+			expectedLines: []string{
+				"If you are on a systemd-powered system",
+				"- 'systemctl status kubelet'",
+				"- 'journalctl -xeu kubelet'",
+			},
+		},
+		{
+			name: "openrc hints",
+			initSystem: &hintedInitSystem{
+				name:     "openrc",
+				commands: []string{"rc-service kubelet status"},
+			},
+			expectedLines: []string{
+				"If you are on a openrc-powered system",
+				"- 'rc-service kubelet status'",
+			},
+			unexpectedLines: []string{
+				"journalctl",
+			},
+		},
+		{
+			name: "windows service hints",
+			initSystem: &hintedInitSystem{
+				name:     "windows-service",
+				commands: []string{"Get-Service 'kubelet'", "Get-EventLog -LogName System -Source 'kubelet' -Newest 20"},
+			},
+			expectedLines: []string{
+				"If you are on a windows-service-powered system",
+				"- 'Get-Service 'kubelet''",
+				"- 'Get-EventLog -LogName System -Source 'kubelet' -Newest 20'",
+			},
+			unexpectedLines: []string{
+				"systemctl",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			getInitSystem := noInitSystem
+			if tc.initSystem != nil {
+				getInitSystem = func() (initsystem.InitSystem, error) {
+					return tc.initSystem, nil
+				}
+			}
+
+			var out strings.Builder
+			printKubeletErrorHelpScreen(&out, "unix:///var/run/containerd/containerd.sock", errors.New("the kubelet is not healthy"), func() []string { return nil }, getInitSystem)
+
+			output := out.String()
+			for _, line := range tc.expectedLines {
+				if !strings.Contains(output, line) {
+					t.Errorf("expected the output to contain %q, got:\n%s", line, output)
+				}
+			}
+			for _, line := range tc.unexpectedLines {
+				if strings.Contains(output, line) {
+					t.Errorf("expected the output to not contain %q, got:\n%s", line, output)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintKubeletErrorHelpScreenTroubleshootingOptions(t *testing.T) {
+	const criSocket = "unix:///var/run/containerd/containerd.sock"
+
+	tests := []struct {
+		name          string
+		opts          TroubleshootingOptions
+		expectedLines []string
+	}{
+		{
+			name: "the CLI fields feed the built-in template",
+			opts: TroubleshootingOptions{
+				CLIBinary:   "nerdctl",
+				PauseFilter: "grep -v sandbox",
+			},
+			expectedLines: []string{
+				"nerdctl --runtime-endpoint unix:///var/run/containerd/containerd.sock ps -a | grep kube | grep -v sandbox",
+				"nerdctl --runtime-endpoint unix:///var/run/containerd/containerd.sock logs CONTAINERID",
+			},
+		},
+		{
+			name: "a custom template is rendered instead of the built-in one",
+			opts: TroubleshootingOptions{
+				Template: template.Must(template.New("custom").Parse(dedent.Dedent(`
+				List the control plane containers with:
+				{{- range .Sockets }}
+					- '{{ $.CLIBinary }} --address {{ . }} ps -a | {{ $.PauseFilter }}'
+				{{- end }}
+				`))),
+				CLIBinary: "nerdctl",
+			},
+			expectedLines: []string{
+				"nerdctl --address unix:///var/run/containerd/containerd.sock ps -a | grep -v pause",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetTroubleshootingOptions(tc.opts)
+			defer SetTroubleshootingOptions(TroubleshootingOptions{})
+
+			var out strings.Builder
+			printKubeletErrorHelpScreen(&out, criSocket, errors.New("the kubelet is not healthy"), func() []string { return nil }, noInitSystem)
+
+			output := out.String()
+			for _, line := range tc.expectedLines {
+				if !strings.Contains(output, line) {
+					t.Errorf("expected the output to contain %q, got:\n%s", line, output)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintFailedSandboxInspectCommands(t *testing.T) {
+	const criSocket = "unix:///var/run/containerd/containerd.sock"
+
+	tests := []struct {
+		name            string
+		runner          CRICommandRunner
+		expectedLines   []string
+		unexpectedLines []string
+	}{
+		{
+			name: "without a runner the commands are rendered with a placeholder",
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock inspectp SANDBOXID",
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock ps -a --pod SANDBOXID",
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock logs CONTAINERID",
+			},
+		},
+		{
+			name: "a runner resolves the sandbox ID of the failed sandbox",
+			runner: func(command string, args ...string) (string, error) {
+				wantArgs := "--runtime-endpoint unix:///var/run/containerd/containerd.sock pods --state NotReady --latest --quiet"
+				if command != "crictl" || strings.Join(args, " ") != wantArgs {
+					t.Errorf("unexpected command: %s %s", command, strings.Join(args, " "))
+				}
+				return "f1e2d3c4b5a6\n", nil
+			},
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock inspectp f1e2d3c4b5a6",
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock ps -a --pod f1e2d3c4b5a6",
+			},
+			unexpectedLines: []string{
+				"SANDBOXID",
+			},
+		},
+		{
+			name: "a failing runner falls back to the placeholder",
+			runner: func(command string, args ...string) (string, error) {
+				return "", errors.New("exec failed")
+			},
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock inspectp SANDBOXID",
+			},
+		},
+		{
+			name: "a runner finding no failed sandbox falls back to the placeholder",
+			runner: func(command string, args ...string) (string, error) {
+				return "\n", nil
+			},
+			expectedLines: []string{
+				"crictl --runtime-endpoint unix:///var/run/containerd/containerd.sock inspectp SANDBOXID",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out strings.Builder
+
+			PrintFailedSandboxInspectCommands(&out, criSocket, tc.runner)
+
+			output := out.String()
+			for _, line := range tc.expectedLines {
+				if !strings.Contains(output, line) {
+					t.Errorf("expected the output to contain %q, got:\n%s", line, output)
+				}
+			}
+			for _, line := range tc.unexpectedLines {
+				if strings.Contains(output, line) {
+					t.Errorf("expected the output to not contain %q, got:\n%s", line, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	netutil "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
+
+	"k8s.io/kubernetes/cmd/kubeadm/app/constants"
+)
+
+// healthzProbeTimeout bounds a single HTTP probe of the healthz endpoint,
+// so that a hanging connection does not consume the whole wait budget.
+const healthzProbeTimeout = time.Second * 5
+
+// defaultKubeletHealthzBackoff returns the backoff applied between probes of
+// the kubelet healthz endpoint. The jitter avoids probing in lockstep with
+// a kubelet that is restarting on a fixed schedule.
+func defaultKubeletHealthzBackoff() wait.Backoff {
+	return wait.Backoff{
+		Duration: time.Millisecond * 500,
+		Factor:   2,
+		Jitter:   0.2,
+		Steps:    5,
+		Cap:      time.Second * 10,
+	}
+}
+
+// WaitForKubeletHealthz polls the kubelet /healthz endpoint at the given
+// address and port with exponential backoff until it returns 'ok' or the
+// timeout expires.
+func WaitForKubeletHealthz(healthzAddress string, healthzPort int32, timeout time.Duration) error {
+	endpoint := fmt.Sprintf("http://%s:%d/healthz", healthzAddress, healthzPort)
+	return waitForKubeletHealthz(context.Background(), endpoint, timeout, clock.RealClock{}, defaultKubeletHealthzBackoff(), probeKubeletHealthz)
+}
+
+// WaitForKubeletHealthy polls the kubelet /healthz endpoint on the default
+// healthz port with exponential backoff until it returns 'ok', the timeout
+// expires or the context is canceled. It is meant to be called right after
+// starting the kubelet, which often takes several seconds to become healthy;
+// waiting here yields a clear error instead of a confusing failure in a later
+// phase that assumed a healthy kubelet.
+func WaitForKubeletHealthy(ctx context.Context, timeout time.Duration) error {
+	endpoint := fmt.Sprintf("http://localhost:%d/healthz", constants.KubeletHealthzPort)
+	return waitForKubeletHealthz(ctx, endpoint, timeout, clock.RealClock{}, defaultKubeletHealthzBackoff(), probeKubeletHealthz)
+}
+
+// waitForKubeletHealthz is separated out only for test purposes, DON'T call it directly, use WaitForKubeletHealthz instead.
+// The clock and backoff are injectable so that tests can advance time and count
+// retries deterministically without real sleeps.
+func waitForKubeletHealthz(ctx context.Context, endpoint string, timeout time.Duration, clk clock.Clock, backoff wait.Backoff, probe func(endpoint string) error) error {
+	var lastError error
+	deadline := clk.Now().Add(timeout)
+	for {
+		err := probe(endpoint)
+		if err == nil {
+			return nil
+		}
+		lastError = err
+		step := backoff.Step()
+		remaining := deadline.Sub(clk.Now())
+		if remaining <= 0 {
+			return errors.Wrapf(lastError, "timed out after %v waiting for the kubelet healthz endpoint %s", timeout, endpoint)
+		}
+		if step > remaining {
+			step = remaining
+		}
+		timer := clk.NewTimer(step)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrapf(ctx.Err(), "context canceled while waiting for the kubelet healthz endpoint %s", endpoint)
+		case <-timer.C():
+		}
+	}
+}
+
+// probeKubeletHealthz performs a single HTTP GET against the healthz endpoint
+// and returns nil only if it responds with status code 200.
+func probeKubeletHealthz(endpoint string) error {
+	client := &http.Client{
+		Transport: netutil.SetOldTransportDefaults(&http.Transport{}),
+		Timeout:   healthzProbeTimeout,
+	}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return errors.Wrapf(err, "failed to probe the kubelet healthz endpoint %s", endpoint)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("the kubelet healthz endpoint %s returned status code %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
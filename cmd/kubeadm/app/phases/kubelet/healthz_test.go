@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// runWaitForKubeletHealthz runs waitForKubeletHealthz against a fake clock and
+// pumps the clock forward in small steps whenever the waiter is sleeping, so
+// that the test never performs real backoff sleeps.
+func runWaitForKubeletHealthz(ctx context.Context, fakeClock *clocktesting.FakeClock, timeout time.Duration, backoff wait.Backoff, probe func(endpoint string) error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- waitForKubeletHealthz(ctx, "http://127.0.0.1:10248/healthz", timeout, fakeClock, backoff, probe)
+	}()
+	for {
+		select {
+		case err := <-errCh:
+			return err
+		default:
+		}
+		if fakeClock.HasWaiters() {
+			fakeClock.Step(time.Millisecond * 100)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWaitForKubeletHealthzRetries(t *testing.T) {
+	var (
+		fakeClock = clocktesting.NewFakeClock(time.Now())
+		attempts  = 0
+		backoff   = wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Steps:    3,
+		}
+	)
+	probe := func(endpoint string) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	if err := runWaitForKubeletHealthz(context.Background(), fakeClock, time.Second*30, backoff, probe); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 probe attempts, got %d", attempts)
+	}
+}
+
+func TestWaitForKubeletHealthzTimeout(t *testing.T) {
+	var (
+		fakeClock = clocktesting.NewFakeClock(time.Now())
+		attempts  = 0
+		backoff   = wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Steps:    3,
+		}
+	)
+	probe := func(endpoint string) error {
+		attempts++
+		return errors.New("connection refused")
+	}
+
+	// The backoff yields sleeps of 1s, 2s and 4s; with a 5s timeout the last
+	// sleep is truncated to the deadline, giving exactly 4 probe attempts.
+	err := runWaitForKubeletHealthz(context.Background(), fakeClock, time.Second*5, backoff, probe)
+	if err == nil {
+		t.Fatal("expected a timeout error, got nil")
+	}
+	if attempts != 4 {
+		t.Fatalf("expected 4 probe attempts, got %d", attempts)
+	}
+}
+
+func TestWaitForKubeletHealthzContextCanceled(t *testing.T) {
+	var (
+		fakeClock = clocktesting.NewFakeClock(time.Now())
+		backoff   = wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Steps:    3,
+		}
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	probe := func(endpoint string) error {
+		// Cancel the context during the first probe, so the waiter observes the
+		// cancellation instead of sleeping out the backoff.
+		cancel()
+		return errors.New("connection refused")
+	}
+
+	err := runWaitForKubeletHealthz(ctx, fakeClock, time.Second*30, backoff, probe)
+	if err == nil {
+		t.Fatal("expected a cancellation error, got nil")
+	}
+	if !strings.Contains(err.Error(), "context canceled") {
+		t.Fatalf("expected a cancellation error, got: %v", err)
+	}
+}
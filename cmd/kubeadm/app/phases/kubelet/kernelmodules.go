@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// kernelModulesPath is the procfs file listing the currently loaded kernel modules.
+const kernelModulesPath = "/proc/modules"
+
+// CheckKernelModules reports which of the required kernel modules are not
+// currently loaded, for use in diagnostics. Modules required by certain CSI or
+// network setups surface as kubelet or pod failures that are hard to trace
+// back to the missing module, so checking up front yields a much clearer
+// error. A nil slice means all required modules are loaded.
+func CheckKernelModules(required []string) ([]string, error) {
+	return checkKernelModules(required, os.ReadFile)
+}
+
+// checkKernelModules is separated out only for test purposes, DON'T call it directly, use CheckKernelModules instead.
+func checkKernelModules(required []string, readFile func(string) ([]byte, error)) ([]string, error) {
+	data, err := readFile(kernelModulesPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read %s", kernelModulesPath)
+	}
+	loaded := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// Each line starts with the module name, followed by size and usage fields.
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			loaded[fields[0]] = true
+		}
+	}
+	var missing []string
+	for _, module := range required {
+		if !loaded[module] {
+			missing = append(missing, module)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckKernelModules(t *testing.T) {
+	procModules := `br_netfilter 32768 0 - Live 0x0000000000000000
+overlay 151552 25 - Live 0x0000000000000000
+nf_conntrack 172032 3 nf_nat,xt_conntrack - Live 0x0000000000000000
+`
+
+	tests := []struct {
+		name            string
+		required        []string
+		procModules     string
+		readError       error
+		expectedMissing []string
+		expectedError   bool
+	}{
+		{
+			name:        "all required modules are loaded",
+			required:    []string{"br_netfilter", "overlay"},
+			procModules: procModules,
+		},
+		{
+			name:            "one required module is missing",
+			required:        []string{"br_netfilter", "rbd"},
+			procModules:     procModules,
+			expectedMissing: []string{"rbd"},
+		},
+		{
+			name:            "all required modules are missing",
+			required:        []string{"rbd", "nbd"},
+			procModules:     procModules,
+			expectedMissing: []string{"rbd", "nbd"},
+		},
+		{
+			name:     "no required modules",
+			required: nil,
+		},
+		{
+			name:          "unreadable modules file",
+			required:      []string{"br_netfilter"},
+			readError:     errors.New("open /proc/modules: permission denied"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readFile := func(path string) ([]byte, error) {
+				if path != kernelModulesPath {
+					t.Fatalf("expected read of %s, got %s", kernelModulesPath, path)
+				}
+				if tc.readError != nil {
+					return nil, tc.readError
+				}
+				return []byte(tc.procModules), nil
+			}
+
+			missing, err := checkKernelModules(tc.required, readFile)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if !reflect.DeepEqual(missing, tc.expectedMissing) {
+				t.Fatalf("expected missing modules %v, got %v", tc.expectedMissing, missing)
+			}
+		})
+	}
+}
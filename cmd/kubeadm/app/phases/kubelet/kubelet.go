@@ -17,45 +17,159 @@ limitations under the License.
 package kubelet
 
 import (
-	"fmt"
-
 	"k8s.io/klog/v2"
 
 	kubeadmconstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
 	"k8s.io/kubernetes/cmd/kubeadm/app/util/initsystem"
 )
 
-// TryStartKubelet attempts to bring up kubelet service
-func TryStartKubelet() {
+// componentKubeletStart is the component field value the kubelet phase
+// attaches to its structured log output.
+const componentKubeletStart = "kubelet-start"
+
+// ServiceAction identifies one lifecycle action on the kubelet service.
+type ServiceAction string
+
+const (
+	// ActionStart is starting the kubelet service.
+	ActionStart ServiceAction = "start"
+	// ActionStop is stopping the kubelet service.
+	ActionStop ServiceAction = "stop"
+	// ActionRestart is restarting the kubelet service.
+	ActionRestart ServiceAction = "restart"
+)
+
+// allowedActions holds the service actions kubeadm may perform on the
+// kubelet. A nil map allows all actions.
+var allowedActions map[ServiceAction]bool
+
+// SetAllowedActions restricts the kubelet service actions kubeadm may
+// perform, for locked-down environments where e.g. only starting the service
+// is permitted. Actions not listed are skipped with a message instead of
+// being performed. Calling it without actions allows all of them again,
+// which is the default.
+func SetAllowedActions(actions ...ServiceAction) {
+	if len(actions) == 0 {
+		allowedActions = nil
+		return
+	}
+	allowedActions = make(map[ServiceAction]bool, len(actions))
+	for _, action := range actions {
+		allowedActions[action] = true
+	}
+}
+
+// actionAllowed returns whether kubeadm may perform the given service action.
+func actionAllowed(action ServiceAction) bool {
+	return allowedActions == nil || allowedActions[action]
+}
+
+// KubeletServiceState describes the kubelet service as reported by the init
+// system, so callers can decide whether a service action is worthwhile before
+// performing it.
+type KubeletServiceState struct {
+	// Known is false when no supported init system was detected, in which
+	// case the remaining fields carry no information.
+	Known bool
+	// Exists reports whether a kubelet service is defined for the init system.
+	Exists bool
+	// Active reports whether the kubelet service is running, or attempting to run.
+	Active bool
+	// Enabled reports whether the kubelet service is enabled to start on each boot.
+	Enabled bool
+}
+
+// GetKubeletServiceState queries the init system for the current state of the
+// kubelet service without changing it. When no supported init system is
+// detected the returned state is marked unknown rather than an error.
+func GetKubeletServiceState() KubeletServiceState {
+	return getKubeletServiceState(initsystem.GetInitSystem)
+}
+
+// getKubeletServiceState is separated out only for test purposes, DON'T call it directly, use GetKubeletServiceState instead.
+func getKubeletServiceState(getInitSystem func() (initsystem.InitSystem, error)) KubeletServiceState {
+	initSystem, err := getInitSystem()
+	if err != nil {
+		klog.V(1).InfoS("No supported init system detected, the kubelet service state is unknown",
+			"component", componentKubeletStart)
+		return KubeletServiceState{}
+	}
+
+	state := KubeletServiceState{Known: true}
+	state.Exists = initSystem.ServiceExists(kubeadmconstants.Kubelet)
+	if !state.Exists {
+		return state
+	}
+	state.Active = initSystem.ServiceIsActive(kubeadmconstants.Kubelet)
+	state.Enabled = initSystem.ServiceIsEnabled(kubeadmconstants.Kubelet)
+	return state
+}
+
+// TryStartKubelet attempts to bring up kubelet service. When dryRun is true
+// the intended service actions are logged and recorded instead of performed.
+func TryStartKubelet(dryRun bool) {
+	tryStartKubelet(dryRun, initsystem.GetInitSystem)
+}
+
+// tryStartKubelet is separated out only for test purposes, DON'T call it directly, use TryStartKubelet instead.
+func tryStartKubelet(dryRun bool, getInitSystem func() (initsystem.InitSystem, error)) {
+	if !actionAllowed(ActionStart) {
+		klog.V(1).InfoS("Starting the kubelet service is not allowed by the configuration, skipping",
+			"component", componentKubeletStart)
+		return
+	}
 	// If we notice that the kubelet service is inactive, try to start it
-	initSystem, err := initsystem.GetInitSystem()
+	initSystem, err := getInitSystem()
 	if err != nil {
-		fmt.Println("[kubelet-start] No supported init system detected, won't make sure the kubelet is running properly.")
+		klog.InfoS("No supported init system detected, won't make sure the kubelet is running properly",
+			"component", componentKubeletStart)
 		return
 	}
+	if dryRun {
+		initSystem = &initsystem.DryRunInitSystem{Wrapped: initSystem}
+	}
 
 	if !initSystem.ServiceExists(kubeadmconstants.Kubelet) {
-		fmt.Println("[kubelet-start] Couldn't detect a kubelet service, can't make sure the kubelet is running properly.")
+		klog.InfoS("Couldn't detect a kubelet service, can't make sure the kubelet is running properly",
+			"component", componentKubeletStart, "initSystem", initSystem.Name())
 	}
 
 	// This runs "systemctl daemon-reload && systemctl restart kubelet"
 	if err := initSystem.ServiceRestart(kubeadmconstants.Kubelet); err != nil {
 		klog.Warningf("[kubelet-start] WARNING: unable to start the kubelet service: [%v]\n", err)
-		fmt.Printf("[kubelet-start] Please ensure kubelet is reloaded and running manually.\n")
+		klog.InfoS("Please ensure kubelet is reloaded and running manually",
+			"component", componentKubeletStart, "initSystem", initSystem.Name())
 	}
 }
 
-// TryStopKubelet attempts to bring down the kubelet service momentarily
-func TryStopKubelet() {
+// TryStopKubelet attempts to bring down the kubelet service momentarily. When
+// dryRun is true the intended service actions are logged and recorded instead
+// of performed.
+func TryStopKubelet(dryRun bool) {
+	tryStopKubelet(dryRun, initsystem.GetInitSystem)
+}
+
+// tryStopKubelet is separated out only for test purposes, DON'T call it directly, use TryStopKubelet instead.
+func tryStopKubelet(dryRun bool, getInitSystem func() (initsystem.InitSystem, error)) {
+	if !actionAllowed(ActionStop) {
+		klog.V(1).InfoS("Stopping the kubelet service is not allowed by the configuration, skipping",
+			"component", componentKubeletStart)
+		return
+	}
 	// If we notice that the kubelet service is inactive, try to start it
-	initSystem, err := initsystem.GetInitSystem()
+	initSystem, err := getInitSystem()
 	if err != nil {
-		fmt.Println("[kubelet-start] No supported init system detected, won't make sure the kubelet not running for a short period of time while setting up configuration for it.")
+		klog.InfoS("No supported init system detected, won't make sure the kubelet not running for a short period of time while setting up configuration for it",
+			"component", componentKubeletStart)
 		return
 	}
+	if dryRun {
+		initSystem = &initsystem.DryRunInitSystem{Wrapped: initSystem}
+	}
 
 	if !initSystem.ServiceExists(kubeadmconstants.Kubelet) {
-		fmt.Println("[kubelet-start] Couldn't detect a kubelet service, can't make sure the kubelet not running for a short period of time while setting up configuration for it.")
+		klog.InfoS("Couldn't detect a kubelet service, can't make sure the kubelet not running for a short period of time while setting up configuration for it",
+			"component", componentKubeletStart, "initSystem", initSystem.Name())
 	}
 
 	// This runs "systemctl daemon-reload && systemctl stop kubelet"
@@ -64,17 +178,34 @@ func TryStopKubelet() {
 	}
 }
 
-// TryRestartKubelet attempts to restart the kubelet service
-func TryRestartKubelet() {
+// TryRestartKubelet attempts to restart the kubelet service. When dryRun is
+// true the intended service actions are logged and recorded instead of
+// performed.
+func TryRestartKubelet(dryRun bool) {
+	tryRestartKubelet(dryRun, initsystem.GetInitSystem)
+}
+
+// tryRestartKubelet is separated out only for test purposes, DON'T call it directly, use TryRestartKubelet instead.
+func tryRestartKubelet(dryRun bool, getInitSystem func() (initsystem.InitSystem, error)) {
+	if !actionAllowed(ActionRestart) {
+		klog.V(1).InfoS("Restarting the kubelet service is not allowed by the configuration, skipping",
+			"component", componentKubeletStart)
+		return
+	}
 	// If we notice that the kubelet service is inactive, try to start it
-	initSystem, err := initsystem.GetInitSystem()
+	initSystem, err := getInitSystem()
 	if err != nil {
-		fmt.Println("[kubelet-start] No supported init system detected, won't make sure the kubelet not running for a short period of time while setting up configuration for it.")
+		klog.InfoS("No supported init system detected, won't make sure the kubelet not running for a short period of time while setting up configuration for it",
+			"component", componentKubeletStart)
 		return
 	}
+	if dryRun {
+		initSystem = &initsystem.DryRunInitSystem{Wrapped: initSystem}
+	}
 
 	if !initSystem.ServiceExists(kubeadmconstants.Kubelet) {
-		fmt.Println("[kubelet-start] Couldn't detect a kubelet service, can't make sure the kubelet not running for a short period of time while setting up configuration for it.")
+		klog.InfoS("Couldn't detect a kubelet service, can't make sure the kubelet not running for a short period of time while setting up configuration for it",
+			"component", componentKubeletStart, "initSystem", initSystem.Name())
 	}
 
 	// This runs "systemctl daemon-reload && systemctl stop kubelet"
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/kubernetes/cmd/kubeadm/app/util/initsystem"
+)
+
+// fakeInitSystem counts the mutating service calls it receives, so tests can
+// assert whether the init system was really invoked. The zero value reports
+// an existing, enabled and active service; set the missing, disabled or
+// inactive fields to report otherwise.
+type fakeInitSystem struct {
+	starts, stops, restarts     int
+	missing, disabled, inactive bool
+}
+
+func (f *fakeInitSystem) Name() string                         { return "fake" }
+func (f *fakeInitSystem) EnableCommand(service string) string  { return "enable " + service }
+func (f *fakeInitSystem) LogCommands(service string) []string  { return []string{"logs " + service} }
+func (f *fakeInitSystem) ServiceStart(service string) error    { f.starts++; return nil }
+func (f *fakeInitSystem) ServiceStop(service string) error     { f.stops++; return nil }
+func (f *fakeInitSystem) ServiceRestart(service string) error  { f.restarts++; return nil }
+func (f *fakeInitSystem) ServiceExists(service string) bool    { return !f.missing }
+func (f *fakeInitSystem) ServiceIsEnabled(service string) bool { return !f.disabled }
+func (f *fakeInitSystem) ServiceIsActive(service string) bool  { return !f.inactive }
+
+func TestTryKubeletServiceActionsDryRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		action       func(dryRun bool, getInitSystem func() (initsystem.InitSystem, error))
+		dryRun       bool
+		wantStops    int
+		wantRestarts int
+	}{
+		{
+			name:         "start invokes the init system",
+			action:       tryStartKubelet,
+			wantRestarts: 1,
+		},
+		{
+			name:   "start in dry-run does not invoke the init system",
+			action: tryStartKubelet,
+			dryRun: true,
+		},
+		{
+			name:      "stop invokes the init system",
+			action:    tryStopKubelet,
+			wantStops: 1,
+		},
+		{
+			name:   "stop in dry-run does not invoke the init system",
+			action: tryStopKubelet,
+			dryRun: true,
+		},
+		{
+			name:         "restart invokes the init system",
+			action:       tryRestartKubelet,
+			wantRestarts: 1,
+		},
+		{
+			name:   "restart in dry-run does not invoke the init system",
+			action: tryRestartKubelet,
+			dryRun: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeInitSystem{}
+			tc.action(tc.dryRun, func() (initsystem.InitSystem, error) {
+				return fake, nil
+			})
+			if fake.starts != 0 || fake.stops != tc.wantStops || fake.restarts != tc.wantRestarts {
+				t.Errorf("expected %d stop and %d restart calls, got %d start, %d stop and %d restart calls",
+					tc.wantStops, tc.wantRestarts, fake.starts, fake.stops, fake.restarts)
+			}
+		})
+	}
+}
+
+func TestGetKubeletServiceState(t *testing.T) {
+	tests := []struct {
+		name string
+		fake *fakeInitSystem
+		want KubeletServiceState
+	}{
+		{
+			name: "no init system detected",
+			want: KubeletServiceState{},
+		},
+		{
+			name: "service enabled and active",
+			fake: &fakeInitSystem{},
+			want: KubeletServiceState{Known: true, Exists: true, Active: true, Enabled: true},
+		},
+		{
+			name: "service stopped and disabled",
+			fake: &fakeInitSystem{inactive: true, disabled: true},
+			want: KubeletServiceState{Known: true, Exists: true},
+		},
+		{
+			name: "service not defined",
+			fake: &fakeInitSystem{missing: true},
+			want: KubeletServiceState{Known: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := getKubeletServiceState(func() (initsystem.InitSystem, error) {
+				if tc.fake == nil {
+					return nil, errors.New("no supported init system detected")
+				}
+				return tc.fake, nil
+			})
+			if got != tc.want {
+				t.Errorf("expected state %+v, got %+v", tc.want, got)
+			}
+			if tc.fake != nil && (tc.fake.starts != 0 || tc.fake.stops != 0 || tc.fake.restarts != 0) {
+				t.Errorf("expected no service actions, got %d start, %d stop and %d restart calls",
+					tc.fake.starts, tc.fake.stops, tc.fake.restarts)
+			}
+		})
+	}
+}
+
+func TestActionAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []ServiceAction
+		action  ServiceAction
+		want    bool
+	}{
+		{
+			name:   "all actions allowed by default",
+			action: ActionStop,
+			want:   true,
+		},
+		{
+			name:    "listed action is allowed",
+			allowed: []ServiceAction{ActionStart},
+			action:  ActionStart,
+			want:    true,
+		},
+		{
+			name:    "stop is skipped when only start is allowed",
+			allowed: []ServiceAction{ActionStart},
+			action:  ActionStop,
+			want:    false,
+		},
+		{
+			name:    "restart is skipped when only start is allowed",
+			allowed: []ServiceAction{ActionStart},
+			action:  ActionRestart,
+			want:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetAllowedActions(tc.allowed...)
+			defer SetAllowedActions()
+			if got := actionAllowed(tc.action); got != tc.want {
+				t.Errorf("expected actionAllowed(%q) to return %v, got %v", tc.action, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// CheckNodeNameCompliance validates that the given node name complies with the
+// RFC 1123 label rules the kubelet enforces when registering the node. A
+// non-compliant name makes registration fail without an obvious error, so the
+// returned message pinpoints what is wrong with the name for the diagnostics.
+func CheckNodeNameCompliance(name string) error {
+	if name == "" {
+		return errors.New("node name is empty")
+	}
+	if lowercased := strings.ToLower(name); lowercased != name {
+		return errors.Errorf("node name %q contains uppercase characters; the kubelet registers nodes with "+
+			"lowercase RFC 1123 names, consider using %q instead", name, lowercased)
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+		return errors.Errorf("node name %q is not a valid RFC 1123 label: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
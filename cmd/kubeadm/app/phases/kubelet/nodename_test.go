@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckNodeNameCompliance(t *testing.T) {
+	tests := []struct {
+		name          string
+		nodeName      string
+		expectedError bool
+		expectedHint  string
+	}{
+		{
+			name:     "valid name",
+			nodeName: "worker-1",
+		},
+		{
+			name:          "empty name",
+			nodeName:      "",
+			expectedError: true,
+			expectedHint:  "empty",
+		},
+		{
+			name:          "uppercase characters",
+			nodeName:      "Worker-1",
+			expectedError: true,
+			expectedHint:  "uppercase",
+		},
+		{
+			name:          "invalid characters",
+			nodeName:      "worker_1",
+			expectedError: true,
+			expectedHint:  "RFC 1123",
+		},
+		{
+			name:          "leading dash",
+			nodeName:      "-worker",
+			expectedError: true,
+			expectedHint:  "RFC 1123",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckNodeNameCompliance(tc.nodeName)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), tc.expectedHint) {
+				t.Errorf("expected error to contain %q, got: %v", tc.expectedHint, err)
+			}
+		})
+	}
+}
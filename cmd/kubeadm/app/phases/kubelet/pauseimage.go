@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"github.com/pkg/errors"
+
+	utilruntime "k8s.io/kubernetes/cmd/kubeadm/app/util/runtime"
+)
+
+// CheckPauseImageAvailable verifies that the pause/sandbox image is present in
+// the container runtime behind the given socket or can be pulled from its
+// registry. A kubelet that cannot obtain the pause image fails to create pod
+// sandboxes after startup.
+func CheckPauseImageAvailable(runtimeSocket, image string) error {
+	containerRuntime := utilruntime.NewContainerRuntime(runtimeSocket)
+	if err := containerRuntime.Connect(); err != nil {
+		return errors.Wrap(err, "failed to connect to the container runtime")
+	}
+	return checkPauseImageAvailable(image, containerRuntime.ImageExists, containerRuntime.PullImage)
+}
+
+// checkPauseImageAvailable is separated out only for test purposes, DON'T call it directly, use CheckPauseImageAvailable instead.
+func checkPauseImageAvailable(image string, imageExists func(image string) bool, pullImage func(image string) error) error {
+	if imageExists(image) {
+		return nil
+	}
+	if err := pullImage(image); err != nil {
+		return errors.Wrapf(err, "the pause image %q is not present in the container runtime and could not be pulled; "+
+			"the kubelet cannot create pod sandboxes without it. Verify that the image registry is reachable "+
+			"and that the configured image repository matches the runtime's sandbox image", image)
+	}
+	return nil
+}
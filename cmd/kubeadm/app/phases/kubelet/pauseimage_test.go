@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckPauseImageAvailable(t *testing.T) {
+	const image = "registry.k8s.io/pause:3.10"
+
+	tests := []struct {
+		name          string
+		imagePresent  bool
+		pullError     error
+		expectedError bool
+		expectedPull  bool
+	}{
+		{
+			name:         "image already present",
+			imagePresent: true,
+		},
+		{
+			name:         "image absent but pullable",
+			expectedPull: true,
+		},
+		{
+			name:          "image absent and unpullable",
+			pullError:     errors.New("connection refused"),
+			expectedPull:  true,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pulled := false
+			imageExists := func(string) bool {
+				return tc.imagePresent
+			}
+			pullImage := func(string) error {
+				pulled = true
+				return tc.pullError
+			}
+
+			err := checkPauseImageAvailable(image, imageExists, pullImage)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if pulled != tc.expectedPull {
+				t.Fatalf("expected pull attempt: %v, got: %v", tc.expectedPull, pulled)
+			}
+		})
+	}
+}
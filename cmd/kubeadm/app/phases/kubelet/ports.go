@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// portProbeTimeout bounds a single connection attempt to a kubelet port.
+const portProbeTimeout = time.Second
+
+// CheckKubeletPortsFree probes the given TCP ports on the local host and
+// returns those already occupied by another process. If something else holds
+// a port the kubelet listens on, such as 10250 or the healthz port 10248, the
+// kubelet fails to bind and crashes on startup.
+func CheckKubeletPortsFree(ports []int) ([]int, error) {
+	return checkKubeletPortsFree(ports, func(address string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("tcp", address, timeout)
+	})
+}
+
+// checkKubeletPortsFree is separated out only for test purposes, DON'T call it directly, use CheckKubeletPortsFree instead.
+func checkKubeletPortsFree(ports []int, dial func(address string, timeout time.Duration) (net.Conn, error)) ([]int, error) {
+	var occupied []int
+	for _, port := range ports {
+		if port <= 0 || port > 65535 {
+			return nil, errors.Errorf("invalid port number %d", port)
+		}
+		conn, err := dial(net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), portProbeTimeout)
+		if err != nil {
+			// Nothing accepted the connection, so the port is free.
+			continue
+		}
+		_ = conn.Close()
+		occupied = append(occupied, port)
+	}
+	return occupied, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckKubeletPortsFree(t *testing.T) {
+	tests := []struct {
+		name          string
+		ports         []int
+		occupiedPorts map[string]bool
+		wantOccupied  []int
+		expectedError bool
+	}{
+		{
+			name:          "all ports free",
+			ports:         []int{10250, 10248},
+			occupiedPorts: map[string]bool{},
+			wantOccupied:  nil,
+		},
+		{
+			name:  "one port occupied",
+			ports: []int{10250, 10248},
+			occupiedPorts: map[string]bool{
+				"127.0.0.1:10250": true,
+			},
+			wantOccupied: []int{10250},
+		},
+		{
+			name:  "all ports occupied",
+			ports: []int{10250, 10248},
+			occupiedPorts: map[string]bool{
+				"127.0.0.1:10250": true,
+				"127.0.0.1:10248": true,
+			},
+			wantOccupied: []int{10250, 10248},
+		},
+		{
+			name:          "invalid port",
+			ports:         []int{-1},
+			occupiedPorts: map[string]bool{},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dial := func(address string, timeout time.Duration) (net.Conn, error) {
+				if tc.occupiedPorts[address] {
+					server, client := net.Pipe()
+					defer func() {
+						_ = server.Close()
+					}()
+					return client, nil
+				}
+				return nil, errors.Errorf("dial tcp %s: connect: connection refused", address)
+			}
+
+			occupied, err := checkKubeletPortsFree(tc.ports, dial)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if !reflect.DeepEqual(occupied, tc.wantOccupied) {
+				t.Fatalf("expected occupied ports: %v, got: %v", tc.wantOccupied, occupied)
+			}
+		})
+	}
+}
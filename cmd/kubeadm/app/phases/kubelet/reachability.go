@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	kubeadmconstants "k8s.io/kubernetes/cmd/kubeadm/app/constants"
+	kubeadmutil "k8s.io/kubernetes/cmd/kubeadm/app/util"
+)
+
+// CheckControlPlaneReachable attempts a TCP connection to the given control
+// plane endpoint and returns an error with a remediation hint if the endpoint
+// cannot be reached. A kubelet that cannot reach the API server endpoint
+// crash-loops during bootstrap, so checking up front yields a much clearer
+// error than the kubelet logs do. The endpoint accepts both the "host:port"
+// form used by ClusterConfiguration.ControlPlaneEndpoint and the full URL
+// form returned by GetControlPlaneEndpoint.
+func CheckControlPlaneReachable(endpoint string, timeout time.Duration) error {
+	return checkControlPlaneReachable(endpoint, timeout, func(address string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("tcp", address, timeout)
+	})
+}
+
+// checkControlPlaneReachable is separated out only for test purposes, DON'T call it directly, use CheckControlPlaneReachable instead.
+func checkControlPlaneReachable(endpoint string, timeout time.Duration, dial func(address string, timeout time.Duration) (net.Conn, error)) error {
+	address, err := controlPlaneDialAddress(endpoint)
+	if err != nil {
+		return err
+	}
+	conn, err := dial(address, timeout)
+	if err != nil {
+		return errors.Wrapf(err, "the control plane endpoint %s is not reachable from this node; "+
+			"verify that the endpoint is correct and that no firewall blocks the connection", endpoint)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// controlPlaneDialAddress normalizes a control plane endpoint into a
+// "host:port" address suitable for dialing, defaulting the port to the
+// API server bind port when the endpoint does not specify one.
+func controlPlaneDialAddress(endpoint string) (string, error) {
+	hostport := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Scheme != "" && u.Host != "" {
+		hostport = u.Host
+	}
+	host, port, err := kubeadmutil.ParseHostPort(hostport)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid control plane endpoint %q", endpoint)
+	}
+	if port == "" {
+		port = strconv.Itoa(kubeadmconstants.KubeAPIServerPort)
+	}
+	return net.JoinHostPort(host, port), nil
+}
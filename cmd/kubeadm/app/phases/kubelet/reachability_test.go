@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckControlPlaneReachable(t *testing.T) {
+	tests := []struct {
+		name          string
+		endpoint      string
+		reachable     map[string]bool
+		wantAddress   string
+		expectedError bool
+	}{
+		{
+			name:        "reachable host:port endpoint",
+			endpoint:    "192.168.0.10:6443",
+			reachable:   map[string]bool{"192.168.0.10:6443": true},
+			wantAddress: "192.168.0.10:6443",
+		},
+		{
+			name:        "reachable URL endpoint",
+			endpoint:    "https://cp.example.com:6443",
+			reachable:   map[string]bool{"cp.example.com:6443": true},
+			wantAddress: "cp.example.com:6443",
+		},
+		{
+			name:        "endpoint without port defaults to the API server port",
+			endpoint:    "cp.example.com",
+			reachable:   map[string]bool{"cp.example.com:6443": true},
+			wantAddress: "cp.example.com:6443",
+		},
+		{
+			name:          "unreachable endpoint",
+			endpoint:      "192.168.0.10:6443",
+			reachable:     map[string]bool{},
+			expectedError: true,
+		},
+		{
+			name:          "invalid endpoint",
+			endpoint:      "not a valid endpoint",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var dialedAddress string
+			dial := func(address string, timeout time.Duration) (net.Conn, error) {
+				dialedAddress = address
+				if tc.reachable[address] {
+					server, client := net.Pipe()
+					defer func() {
+						_ = server.Close()
+					}()
+					return client, nil
+				}
+				return nil, errors.Errorf("dial tcp %s: connect: connection refused", address)
+			}
+
+			err := checkControlPlaneReachable(tc.endpoint, time.Second, dial)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if tc.wantAddress != "" && dialedAddress != tc.wantAddress {
+				t.Fatalf("expected dialed address: %q, got: %q", tc.wantAddress, dialedAddress)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	utilruntime "k8s.io/kubernetes/cmd/kubeadm/app/util/runtime"
+)
+
+// Mismatch describes one container runtime configuration key whose reported
+// value does not match what kubeadm expects.
+type Mismatch struct {
+	// Key is the flattened configuration key, e.g. "plugins.cri.systemdCgroup".
+	Key string
+	// Expected is the value kubeadm expects for the key.
+	Expected string
+	// Actual is the value the runtime reported, or an empty string if the
+	// runtime did not report the key at all.
+	Actual string
+}
+
+// CheckRuntimeConfig queries the container runtime behind the given socket for
+// its configuration via CRI and reports the keys whose values do not match the
+// given expectations. Mismatched runtime configuration (a wrong cgroup driver,
+// for instance) is a common cause of kubelet failures.
+func CheckRuntimeConfig(socket string, expectations map[string]string) ([]Mismatch, error) {
+	return checkRuntimeConfig(expectations, func() (map[string]string, error) {
+		containerRuntime := utilruntime.NewContainerRuntime(socket)
+		if err := containerRuntime.Connect(); err != nil {
+			return nil, errors.Wrap(err, "failed to connect to the container runtime")
+		}
+		return containerRuntime.(*utilruntime.CRIRuntime).RuntimeConfigInfo()
+	})
+}
+
+// checkRuntimeConfig is separated out only for test purposes, DON'T call it directly, use CheckRuntimeConfig instead.
+func checkRuntimeConfig(expectations map[string]string, queryConfig func() (map[string]string, error)) ([]Mismatch, error) {
+	config, err := queryConfig()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(expectations))
+	for key := range expectations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var mismatches []Mismatch
+	for _, key := range keys {
+		if actual := config[key]; actual != expectations[key] {
+			mismatches = append(mismatches, Mismatch{Key: key, Expected: expectations[key], Actual: actual})
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckRuntimeConfig(t *testing.T) {
+	tests := []struct {
+		name               string
+		expectations       map[string]string
+		config             map[string]string
+		queryError         error
+		expectedMismatches []Mismatch
+		expectedError      bool
+	}{
+		{
+			name: "all expectations match",
+			expectations: map[string]string{
+				"cgroupDriver":             "systemd",
+				"plugins.cri.sandboxImage": "registry.k8s.io/pause:3.10",
+			},
+			config: map[string]string{
+				"cgroupDriver":             "systemd",
+				"plugins.cri.sandboxImage": "registry.k8s.io/pause:3.10",
+				"unrelatedKey":             "unrelatedValue",
+			},
+		},
+		{
+			name: "several keys mismatch",
+			expectations: map[string]string{
+				"cgroupDriver":              "systemd",
+				"plugins.cri.sandboxImage":  "registry.k8s.io/pause:3.10",
+				"plugins.cri.systemdCgroup": "true",
+			},
+			config: map[string]string{
+				"cgroupDriver":              "cgroupfs",
+				"plugins.cri.sandboxImage":  "registry.k8s.io/pause:3.10",
+				"plugins.cri.systemdCgroup": "false",
+			},
+			expectedMismatches: []Mismatch{
+				{Key: "cgroupDriver", Expected: "systemd", Actual: "cgroupfs"},
+				{Key: "plugins.cri.systemdCgroup", Expected: "true", Actual: "false"},
+			},
+		},
+		{
+			name: "expected key missing from the runtime config",
+			expectations: map[string]string{
+				"cgroupDriver": "systemd",
+			},
+			config: map[string]string{},
+			expectedMismatches: []Mismatch{
+				{Key: "cgroupDriver", Expected: "systemd", Actual: ""},
+			},
+		},
+		{
+			name: "query error",
+			expectations: map[string]string{
+				"cgroupDriver": "systemd",
+			},
+			queryError:    errors.New("connection refused"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			queryConfig := func() (map[string]string, error) {
+				return tc.config, tc.queryError
+			}
+
+			mismatches, err := checkRuntimeConfig(tc.expectations, queryConfig)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if !reflect.DeepEqual(mismatches, tc.expectedMismatches) {
+				t.Errorf("expected mismatches %+v, got %+v", tc.expectedMismatches, mismatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// CheckStaticPodManifestsPresent reports which of the expected static Pod
+// manifests are missing from the manifest directory. On control-plane nodes
+// the kubelet reads the control-plane component manifests from this directory;
+// a wrong or empty directory means the components never start. Expected names
+// may be given with or without the ".yaml" extension.
+func CheckStaticPodManifestsPresent(dir string, expected []string) ([]string, error) {
+	return checkStaticPodManifestsPresent(dir, expected, func(dir string) ([]string, error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		return names, nil
+	})
+}
+
+// checkStaticPodManifestsPresent is separated out only for test purposes, DON'T call it directly, use CheckStaticPodManifestsPresent instead.
+func checkStaticPodManifestsPresent(dir string, expected []string, readDir func(dir string) ([]string, error)) ([]string, error) {
+	names, err := readDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the static Pod manifest directory %q", dir)
+	}
+	present := sets.New(names...)
+	var missing []string
+	for _, manifest := range expected {
+		if !present.Has(manifest) && !present.Has(manifest+".yaml") {
+			missing = append(missing, manifest)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckStaticPodManifestsPresent(t *testing.T) {
+	tests := []struct {
+		name            string
+		expected        []string
+		manifests       []string
+		readError       error
+		expectedMissing []string
+		expectedError   bool
+	}{
+		{
+			name:      "all manifests present",
+			expected:  []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"},
+			manifests: []string{"kube-apiserver.yaml", "kube-controller-manager.yaml", "kube-scheduler.yaml", "etcd.yaml"},
+		},
+		{
+			name:      "expected names may include the extension",
+			expected:  []string{"kube-apiserver.yaml"},
+			manifests: []string{"kube-apiserver.yaml"},
+		},
+		{
+			name:            "some manifests missing",
+			expected:        []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler"},
+			manifests:       []string{"kube-apiserver.yaml"},
+			expectedMissing: []string{"kube-controller-manager", "kube-scheduler"},
+		},
+		{
+			name:            "empty directory",
+			expected:        []string{"kube-apiserver"},
+			expectedMissing: []string{"kube-apiserver"},
+		},
+		{
+			name:          "unreadable directory",
+			expected:      []string{"kube-apiserver"},
+			readError:     errors.New("permission denied"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			readDir := func(string) ([]string, error) {
+				return tc.manifests, tc.readError
+			}
+
+			missing, err := checkStaticPodManifestsPresent("/etc/kubernetes/manifests", tc.expected, readDir)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if !reflect.DeepEqual(missing, tc.expectedMissing) {
+				t.Errorf("expected missing manifests %v, got %v", tc.expectedMissing, missing)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CheckKubeletSysctls reads the sysctls the kubelet requires from /proc/sys
+// and returns the ones whose current value differs from the required one,
+// with the sysctl name in dotted form as the mismatch key. The kubelet checks
+// these at startup and fails with messages that are hard to trace back to the
+// host configuration, so catching them early gives a clearer signal.
+func CheckKubeletSysctls(required map[string]string) ([]Mismatch, error) {
+	return checkKubeletSysctls(required, readSysctl)
+}
+
+// checkKubeletSysctls is separated out only for test purposes, DON'T call it directly,
+// use CheckKubeletSysctls instead.
+func checkKubeletSysctls(required map[string]string, read func(name string) (string, error)) ([]Mismatch, error) {
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	// Check in a fixed order so that reports are comparable across runs.
+	sort.Strings(names)
+
+	var mismatches []Mismatch
+	for _, name := range names {
+		actual, err := read(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read sysctl %q", name)
+		}
+		if actual != required[name] {
+			mismatches = append(mismatches, Mismatch{Key: name, Expected: required[name], Actual: actual})
+		}
+	}
+	return mismatches, nil
+}
+
+// readSysctl reads a sysctl's current value from /proc/sys, translating the
+// dotted name to its path form.
+func readSysctl(name string) (string, error) {
+	out, err := os.ReadFile(filepath.Join("/proc/sys", strings.ReplaceAll(name, ".", "/")))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
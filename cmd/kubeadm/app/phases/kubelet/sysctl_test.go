@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckKubeletSysctls(t *testing.T) {
+	tests := []struct {
+		name               string
+		required           map[string]string
+		current            map[string]string
+		expectedMismatches []Mismatch
+		expectedError      bool
+	}{
+		{
+			name: "all sysctls satisfied",
+			required: map[string]string{
+				"net.bridge.bridge-nf-call-iptables": "1",
+				"net.ipv4.ip_forward":                "1",
+			},
+			current: map[string]string{
+				"net.bridge.bridge-nf-call-iptables": "1",
+				"net.ipv4.ip_forward":                "1",
+			},
+		},
+		{
+			name: "mismatched sysctls are reported in a fixed order",
+			required: map[string]string{
+				"net.bridge.bridge-nf-call-iptables": "1",
+				"net.ipv4.ip_forward":                "1",
+			},
+			current: map[string]string{
+				"net.bridge.bridge-nf-call-iptables": "0",
+				"net.ipv4.ip_forward":                "0",
+			},
+			expectedMismatches: []Mismatch{
+				{Key: "net.bridge.bridge-nf-call-iptables", Expected: "1", Actual: "0"},
+				{Key: "net.ipv4.ip_forward", Expected: "1", Actual: "0"},
+			},
+		},
+		{
+			name: "unreadable sysctl returns an error",
+			required: map[string]string{
+				"net.bridge.bridge-nf-call-iptables": "1",
+			},
+			current:       map[string]string{},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			read := func(name string) (string, error) {
+				value, ok := tc.current[name]
+				if !ok {
+					return "", errors.Errorf("open /proc/sys/%s: no such file or directory", strings.ReplaceAll(name, ".", "/"))
+				}
+				return value, nil
+			}
+
+			mismatches, err := checkKubeletSysctls(tc.required, read)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if !reflect.DeepEqual(mismatches, tc.expectedMismatches) {
+				t.Errorf("expected mismatches %v, got %v", tc.expectedMismatches, mismatches)
+			}
+		})
+	}
+}
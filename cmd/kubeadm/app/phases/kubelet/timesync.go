@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	utilsexec "k8s.io/utils/exec"
+)
+
+// TimeSyncStatus holds the result of a node clock synchronization check.
+type TimeSyncStatus struct {
+	// Synchronized is true if the consulted daemon reports the node clock as synchronized.
+	Synchronized bool
+	// Source is the daemon that was consulted, e.g. "systemd-timesyncd", "chronyd" or "ntpd".
+	Source string
+	// Detail holds the relevant status line as reported by the daemon.
+	Detail string
+}
+
+// timeSyncProbe describes how to query one time synchronization client for its status.
+type timeSyncProbe struct {
+	binary string
+	source string
+	args   []string
+	parse  func(output string) (bool, string)
+}
+
+// timeSyncProbes lists the supported time synchronization clients in detection order.
+var timeSyncProbes = []timeSyncProbe{
+	{binary: "timedatectl", source: "systemd-timesyncd", args: []string{"show"}, parse: parseTimedatectlShow},
+	{binary: "chronyc", source: "chronyd", args: []string{"tracking"}, parse: parseChronycTracking},
+	{binary: "ntpstat", source: "ntpd", args: []string{}, parse: parseNtpstat},
+}
+
+// CheckTimeSync inspects the status of well known time synchronization daemons
+// (systemd-timesyncd via timedatectl, chrony and ntpd) and reports whether the
+// node clock is synchronized. An unsynchronized clock commonly surfaces as
+// kubelet TLS or certificate errors that look like startup crashes.
+// The first client binary found on the host is consulted. An error is returned
+// if no supported client could be found.
+func CheckTimeSync(execer utilsexec.Interface) (*TimeSyncStatus, error) {
+	for _, probe := range timeSyncProbes {
+		if _, err := execer.LookPath(probe.binary); err != nil {
+			continue
+		}
+		// The status commands of some clients (e.g. ntpstat) exit non-zero when the
+		// clock is unsynchronized, so the output is parsed regardless of the exit code.
+		out, err := execer.Command(probe.binary, probe.args...).CombinedOutput()
+		if err != nil && len(out) == 0 {
+			return nil, errors.Wrapf(err, "could not query %q for the time synchronization status", probe.binary)
+		}
+		synced, detail := probe.parse(string(out))
+		return &TimeSyncStatus{
+			Synchronized: synced,
+			Source:       probe.source,
+			Detail:       detail,
+		}, nil
+	}
+	return nil, errors.New("no supported time synchronization client found on this system")
+}
+
+// TimeSyncWarning returns a printable warning if the node clock is reported as
+// unsynchronized, and an empty string if the clock is synchronized or its status
+// could not be determined. It is included in the kubelet error help output.
+func TimeSyncWarning(execer utilsexec.Interface) string {
+	status, err := CheckTimeSync(execer)
+	if err != nil || status.Synchronized {
+		return ""
+	}
+	return fmt.Sprintf("WARNING: the node clock does not appear to be synchronized (%s reports %q).\n"+
+		"Clock skew can cause TLS and certificate validation errors in the kubelet.\n"+
+		"Please verify the time synchronization on this node.", status.Source, status.Detail)
+}
+
+// parseTimedatectlShow parses the "key=value" output of "timedatectl show".
+func parseTimedatectlShow(output string) (bool, string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "NTPSynchronized=") {
+			return strings.TrimPrefix(line, "NTPSynchronized=") == "yes", line
+		}
+	}
+	return false, "NTPSynchronized property not reported"
+}
+
+// parseChronycTracking parses the output of "chronyc tracking", whose
+// "Leap status" field is "Not synchronised" until the clock is in sync.
+func parseChronycTracking(output string) (bool, string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Leap status") {
+			return !strings.Contains(line, "Not synchronised"), line
+		}
+	}
+	return false, "Leap status field not reported"
+}
+
+// parseNtpstat parses the output of "ntpstat", whose first line starts with
+// "synchronised" or "unsynchronised".
+func parseNtpstat(output string) (bool, string) {
+	line := strings.TrimSpace(strings.Split(output, "\n")[0])
+	return strings.HasPrefix(line, "synchronised"), line
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lithammer/dedent"
+	"github.com/pkg/errors"
+
+	utilsexec "k8s.io/utils/exec"
+	fakeexec "k8s.io/utils/exec/testing"
+)
+
+func TestCheckTimeSync(t *testing.T) {
+	chronycSynced := dedent.Dedent(`
+		Reference ID    : A9FEA97B (169.254.169.123)
+		Stratum         : 4
+		Leap status     : Normal
+	`)
+	chronycUnsynced := dedent.Dedent(`
+		Reference ID    : 00000000 ()
+		Stratum         : 0
+		Leap status     : Not synchronised
+	`)
+
+	cases := []struct {
+		name           string
+		lookPathErr    error
+		output         string
+		outputErr      error
+		expectedSynced bool
+		expectedSource string
+		expectedErr    bool
+	}{
+		{
+			name:           "timedatectl reports a synchronized clock",
+			output:         "Timezone=Etc/UTC\nNTPSynchronized=yes\nNTP=yes",
+			expectedSynced: true,
+			expectedSource: "systemd-timesyncd",
+		},
+		{
+			name:           "timedatectl reports an unsynchronized clock",
+			output:         "Timezone=Etc/UTC\nNTPSynchronized=no\nNTP=no",
+			expectedSynced: false,
+			expectedSource: "systemd-timesyncd",
+		},
+		{
+			name:           "chronyc reports a synchronized clock",
+			output:         chronycSynced,
+			expectedSynced: true,
+			expectedSource: "chronyd",
+		},
+		{
+			name:           "chronyc reports an unsynchronized clock",
+			output:         chronycUnsynced,
+			expectedSynced: false,
+			expectedSource: "chronyd",
+		},
+		{
+			name:           "ntpstat reports a synchronized clock",
+			output:         "synchronised to NTP server (10.0.0.1) at stratum 3",
+			expectedSynced: true,
+			expectedSource: "ntpd",
+		},
+		{
+			name:           "ntpstat reports an unsynchronized clock with non-zero exit",
+			output:         "unsynchronised",
+			outputErr:      errors.New("exit status 1"),
+			expectedSynced: false,
+			expectedSource: "ntpd",
+		},
+		{
+			name:        "no supported client found",
+			lookPathErr: errors.New("not found"),
+			expectedErr: true,
+		},
+		{
+			name:        "status command fails without output",
+			outputErr:   errors.New("exit status 1"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fcmd := fakeexec.FakeCmd{
+				CombinedOutputScript: []fakeexec.FakeAction{
+					func() ([]byte, []byte, error) { return []byte(tc.output), nil, tc.outputErr },
+				},
+			}
+			fexec := &fakeexec.FakeExec{
+				CommandScript: []fakeexec.FakeCommandAction{
+					func(cmd string, args ...string) utilsexec.Cmd { return fakeexec.InitFakeCmd(&fcmd, cmd, args...) },
+				},
+				LookPathFunc: func(binary string) (string, error) {
+					// Make the probe matching the expected source the only one found.
+					if tc.lookPathErr != nil {
+						return "", tc.lookPathErr
+					}
+					for _, probe := range timeSyncProbes {
+						if probe.binary == binary && probe.source == tc.expectedSource {
+							return "/usr/bin/" + binary, nil
+						}
+					}
+					if tc.expectedSource == "" {
+						return "/usr/bin/" + binary, nil
+					}
+					return "", errors.New("not found")
+				},
+			}
+			status, err := CheckTimeSync(fexec)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("expected an error, got status %+v", status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status.Synchronized != tc.expectedSynced {
+				t.Errorf("expected Synchronized=%v, got %v (detail: %q)", tc.expectedSynced, status.Synchronized, status.Detail)
+			}
+			if status.Source != tc.expectedSource {
+				t.Errorf("expected Source=%q, got %q", tc.expectedSource, status.Source)
+			}
+		})
+	}
+}
+
+func TestTimeSyncWarning(t *testing.T) {
+	cases := []struct {
+		name          string
+		output        string
+		expectWarning bool
+	}{
+		{
+			name:          "no warning for a synchronized clock",
+			output:        "NTPSynchronized=yes",
+			expectWarning: false,
+		},
+		{
+			name:          "warning for an unsynchronized clock",
+			output:        "NTPSynchronized=no",
+			expectWarning: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fcmd := fakeexec.FakeCmd{
+				CombinedOutputScript: []fakeexec.FakeAction{
+					func() ([]byte, []byte, error) { return []byte(tc.output), nil, nil },
+				},
+			}
+			fexec := &fakeexec.FakeExec{
+				CommandScript: []fakeexec.FakeCommandAction{
+					func(cmd string, args ...string) utilsexec.Cmd { return fakeexec.InitFakeCmd(&fcmd, cmd, args...) },
+				},
+				LookPathFunc: func(binary string) (string, error) { return "/usr/bin/" + binary, nil },
+			}
+			warning := TimeSyncWarning(fexec)
+			if tc.expectWarning && !strings.Contains(warning, "not appear to be synchronized") {
+				t.Errorf("expected a clock skew warning, got %q", warning)
+			}
+			if !tc.expectWarning && warning != "" {
+				t.Errorf("expected no warning, got %q", warning)
+			}
+		})
+	}
+}
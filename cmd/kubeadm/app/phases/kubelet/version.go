@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/version"
+	utilsexec "k8s.io/utils/exec"
+
+	"k8s.io/kubernetes/cmd/kubeadm/app/preflight"
+)
+
+// CheckKubeletVersion runs "kubelet --version" and compares the reported
+// version against the expected one. It returns the actual version and whether
+// it matches the expectation. A stale kubelet binary left on PATH during an
+// upgrade is a common source of subtle failures.
+func CheckKubeletVersion(expected string) (string, bool, error) {
+	return checkKubeletVersion(expected, utilsexec.New())
+}
+
+// checkKubeletVersion is separated out only for test purposes, DON'T call it directly,
+// use CheckKubeletVersion instead.
+func checkKubeletVersion(expected string, execer utilsexec.Interface) (string, bool, error) {
+	expectedVersion, err := version.ParseSemantic(expected)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "invalid expected kubelet version %q", expected)
+	}
+	actualVersion, err := preflight.GetKubeletVersion(execer)
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to determine the kubelet version")
+	}
+	return actualVersion.String(), actualVersion.EqualTo(expectedVersion), nil
+}
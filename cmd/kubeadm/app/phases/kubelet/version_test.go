@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	utilsexec "k8s.io/utils/exec"
+	fakeexec "k8s.io/utils/exec/testing"
+)
+
+func TestCheckKubeletVersion(t *testing.T) {
+	cases := []struct {
+		name           string
+		expected       string
+		output         string
+		execError      error
+		expectedActual string
+		expectedOK     bool
+		expectedError  bool
+		expectedHint   string
+	}{
+		{
+			name:           "matching version",
+			expected:       "1.33.0",
+			output:         "Kubernetes v1.33.0",
+			expectedActual: "1.33.0",
+			expectedOK:     true,
+		},
+		{
+			name:           "mismatched version",
+			expected:       "1.33.0",
+			output:         "Kubernetes v1.32.2",
+			expectedActual: "1.32.2",
+		},
+		{
+			name:          "invalid expected version",
+			expected:      "not-a-version",
+			output:        "Kubernetes v1.33.0",
+			expectedError: true,
+			expectedHint:  "invalid expected kubelet version",
+		},
+		{
+			name:          "kubelet binary not found",
+			expected:      "1.33.0",
+			output:        "",
+			execError:     errors.New("kubelet not found"),
+			expectedError: true,
+			expectedHint:  "failed to determine the kubelet version",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fcmd := fakeexec.FakeCmd{
+				OutputScript: []fakeexec.FakeAction{
+					func() ([]byte, []byte, error) { return []byte(tc.output), nil, tc.execError },
+				},
+			}
+			fexec := &fakeexec.FakeExec{
+				CommandScript: []fakeexec.FakeCommandAction{
+					func(cmd string, args ...string) utilsexec.Cmd { return fakeexec.InitFakeCmd(&fcmd, cmd, args...) },
+				},
+			}
+
+			actual, ok, err := checkKubeletVersion(tc.expected, fexec)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tc.expectedHint) {
+					t.Errorf("expected error to contain %q, got %q", tc.expectedHint, err.Error())
+				}
+				return
+			}
+			if actual != tc.expectedActual {
+				t.Errorf("expected actual version %q, got %q", tc.expectedActual, actual)
+			}
+			if ok != tc.expectedOK {
+				t.Errorf("expected match %v, got %v", tc.expectedOK, ok)
+			}
+		})
+	}
+}
@@ -246,7 +246,13 @@ func UpdateKubeletLocalMode(cfg *kubeadmapi.InitConfiguration, dryRun bool) erro
 		return err
 	}
 
-	kubeletphase.TryRestartKubelet()
+	// Only attempt a restart when a kubelet service is known to exist; with an
+	// unknown state the restart is still tried and logs its own diagnostics.
+	if state := kubeletphase.GetKubeletServiceState(); state.Known && !state.Exists {
+		klog.V(2).Infof("No kubelet service detected, skipping the restart after updating %s", kubeletKubeConfigFilePath)
+		return nil
+	}
+	kubeletphase.TryRestartKubelet(dryRun)
 
 	return nil
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initsystem
+
+import (
+	"k8s.io/klog/v2"
+)
+
+// DryRunInitSystem wraps an InitSystem and records the service actions that
+// would have been performed instead of performing them. The read-only queries
+// are delegated to the wrapped init system, so callers observe the real
+// service state while the mutating actions stay without effect.
+type DryRunInitSystem struct {
+	// Wrapped is the init system the read-only queries are delegated to.
+	Wrapped InitSystem
+	// Actions records, in order, the "<action> <service>" pairs that would
+	// have been performed.
+	Actions []string
+}
+
+// record logs and records a service action that would have been performed.
+func (d *DryRunInitSystem) record(action, service string) {
+	d.Actions = append(d.Actions, action+" "+service)
+	klog.InfoS("Would perform the service action", "action", action, "service", service, "initSystem", d.Wrapped.Name())
+}
+
+// Name returns the name of the wrapped init system
+func (d *DryRunInitSystem) Name() string {
+	return d.Wrapped.Name()
+}
+
+// EnableCommand return a string describing how to enable a service
+func (d *DryRunInitSystem) EnableCommand(service string) string {
+	return d.Wrapped.EnableCommand(service)
+}
+
+// LogCommands returns the wrapped init system's log inspection commands
+func (d *DryRunInitSystem) LogCommands(service string) []string {
+	return d.Wrapped.LogCommands(service)
+}
+
+// ServiceStart records that the service would have been started
+func (d *DryRunInitSystem) ServiceStart(service string) error {
+	d.record("start", service)
+	return nil
+}
+
+// ServiceStop records that the service would have been stopped
+func (d *DryRunInitSystem) ServiceStop(service string) error {
+	d.record("stop", service)
+	return nil
+}
+
+// ServiceRestart records that the service would have been restarted
+func (d *DryRunInitSystem) ServiceRestart(service string) error {
+	d.record("restart", service)
+	return nil
+}
+
+// ServiceExists ensures the service is defined for the wrapped init system.
+func (d *DryRunInitSystem) ServiceExists(service string) bool {
+	return d.Wrapped.ServiceExists(service)
+}
+
+// ServiceIsEnabled ensures the service is enabled to start on each boot.
+func (d *DryRunInitSystem) ServiceIsEnabled(service string) bool {
+	return d.Wrapped.ServiceIsEnabled(service)
+}
+
+// ServiceIsActive ensures the service is running, or attempting to run.
+func (d *DryRunInitSystem) ServiceIsActive(service string) bool {
+	return d.Wrapped.ServiceIsActive(service)
+}
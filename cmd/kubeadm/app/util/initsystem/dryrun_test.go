@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initsystem
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordingInitSystem counts the mutating service calls it receives.
+type recordingInitSystem struct {
+	mutations int
+}
+
+func (r *recordingInitSystem) Name() string                        { return "recording" }
+func (r *recordingInitSystem) EnableCommand(service string) string { return "enable " + service }
+func (r *recordingInitSystem) LogCommands(service string) []string {
+	return []string{"logs " + service}
+}
+func (r *recordingInitSystem) ServiceStart(service string) error   { r.mutations++; return nil }
+func (r *recordingInitSystem) ServiceStop(service string) error    { r.mutations++; return nil }
+func (r *recordingInitSystem) ServiceRestart(service string) error { r.mutations++; return nil }
+func (r *recordingInitSystem) ServiceExists(service string) bool   { return true }
+func (r *recordingInitSystem) ServiceIsEnabled(service string) bool {
+	return false
+}
+func (r *recordingInitSystem) ServiceIsActive(service string) bool { return true }
+
+func TestDryRunInitSystem(t *testing.T) {
+	wrapped := &recordingInitSystem{}
+	dry := &DryRunInitSystem{Wrapped: wrapped}
+
+	if err := dry.ServiceStart("kubelet"); err != nil {
+		t.Errorf("expected ServiceStart to succeed, got: %v", err)
+	}
+	if err := dry.ServiceStop("kubelet"); err != nil {
+		t.Errorf("expected ServiceStop to succeed, got: %v", err)
+	}
+	if err := dry.ServiceRestart("kubelet"); err != nil {
+		t.Errorf("expected ServiceRestart to succeed, got: %v", err)
+	}
+	if wrapped.mutations != 0 {
+		t.Errorf("expected no mutating calls on the wrapped init system, got %d", wrapped.mutations)
+	}
+	wantActions := []string{"start kubelet", "stop kubelet", "restart kubelet"}
+	if !reflect.DeepEqual(dry.Actions, wantActions) {
+		t.Errorf("expected the recorded actions to be %v, got %v", wantActions, dry.Actions)
+	}
+
+	// The read-only queries are delegated to the wrapped init system.
+	if got := dry.Name(); got != "recording" {
+		t.Errorf("expected Name to be delegated, got %q", got)
+	}
+	if !dry.ServiceExists("kubelet") || dry.ServiceIsEnabled("kubelet") || !dry.ServiceIsActive("kubelet") {
+		t.Errorf("expected the queries to be delegated to the wrapped init system")
+	}
+}
@@ -16,11 +16,25 @@ limitations under the License.
 
 package initsystem
 
+// InitSystemEnvVar is the environment variable naming the init system
+// implementation GetInitSystem should use instead of auto-detecting one, for
+// images where auto-detection misfires (e.g. a stub systemctl binary).
+// Supported values are "systemd", "openrc" and "windows", each only on the
+// platforms providing that implementation.
+const InitSystemEnvVar = "KUBEADM_INIT_SYSTEM"
+
 // InitSystem is the interface that describe behaviors of an init system
 type InitSystem interface {
+	// Name returns the name of the init system
+	Name() string
+
 	// EnableCommand returns a string describing how to enable a service
 	EnableCommand(service string) string
 
+	// LogCommands returns example commands for inspecting the status and
+	// recent logs of a service, for troubleshooting output
+	LogCommands(service string) []string
+
 	// ServiceStart tries to start a specific service
 	ServiceStart(service string) error
 
@@ -21,6 +21,7 @@ package initsystem
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 
@@ -30,22 +31,33 @@ import (
 // OpenRCInitSystem defines openrc
 type OpenRCInitSystem struct{}
 
+// Name returns the name of the init system
+func (openrc OpenRCInitSystem) Name() string {
+	return "openrc"
+}
+
 // ServiceStart tries to start a specific service
 func (openrc OpenRCInitSystem) ServiceStart(service string) error {
-	args := []string{service, "start"}
-	return exec.Command("rc-service", args...).Run()
+	return withRetry(func() error {
+		args := []string{service, "start"}
+		return exec.Command("rc-service", args...).Run()
+	}, retryPolicy)
 }
 
 // ServiceStop tries to stop a specific service
 func (openrc OpenRCInitSystem) ServiceStop(service string) error {
-	args := []string{service, "stop"}
-	return exec.Command("rc-service", args...).Run()
+	return withRetry(func() error {
+		args := []string{service, "stop"}
+		return exec.Command("rc-service", args...).Run()
+	}, retryPolicy)
 }
 
 // ServiceRestart tries to reload the environment and restart the specific service
 func (openrc OpenRCInitSystem) ServiceRestart(service string) error {
-	args := []string{service, "restart"}
-	return exec.Command("rc-service", args...).Run()
+	return withRetry(func() error {
+		args := []string{service, "restart"}
+		return exec.Command("rc-service", args...).Run()
+	}, retryPolicy)
 }
 
 // ServiceExists ensures the service is defined for this init system.
@@ -78,14 +90,37 @@ func (openrc OpenRCInitSystem) EnableCommand(service string) string {
 	return fmt.Sprintf("rc-update add %s default", service)
 }
 
+// LogCommands returns example commands for inspecting the status and recent
+// logs of a service. OpenRC has no standard log store, so only the status
+// command is suggested.
+func (openrc OpenRCInitSystem) LogCommands(service string) []string {
+	return []string{
+		fmt.Sprintf("rc-service %s status", service),
+	}
+}
+
 // SystemdInitSystem defines systemd
 type SystemdInitSystem struct{}
 
+// Name returns the name of the init system
+func (sysd SystemdInitSystem) Name() string {
+	return "systemd"
+}
+
 // EnableCommand return a string describing how to enable a service
 func (sysd SystemdInitSystem) EnableCommand(service string) string {
 	return fmt.Sprintf("systemctl enable %s.service", service)
 }
 
+// LogCommands returns example commands for inspecting the status and recent
+// logs of a service
+func (sysd SystemdInitSystem) LogCommands(service string) []string {
+	return []string{
+		fmt.Sprintf("systemctl status %s", service),
+		fmt.Sprintf("journalctl -xeu %s", service),
+	}
+}
+
 // reloadSystemd reloads the systemd daemon
 func (sysd SystemdInitSystem) reloadSystemd() error {
 	if err := exec.Command("systemctl", "daemon-reload").Run(); err != nil {
@@ -96,28 +131,34 @@ func (sysd SystemdInitSystem) reloadSystemd() error {
 
 // ServiceStart tries to start a specific service
 func (sysd SystemdInitSystem) ServiceStart(service string) error {
-	// Before we try to start any service, make sure that systemd is ready
-	if err := sysd.reloadSystemd(); err != nil {
-		return err
-	}
-	args := []string{"start", service}
-	return exec.Command("systemctl", args...).Run()
+	return withRetry(func() error {
+		// Before we try to start any service, make sure that systemd is ready
+		if err := sysd.reloadSystemd(); err != nil {
+			return err
+		}
+		args := []string{"start", service}
+		return exec.Command("systemctl", args...).Run()
+	}, retryPolicy)
 }
 
 // ServiceRestart tries to reload the environment and restart the specific service
 func (sysd SystemdInitSystem) ServiceRestart(service string) error {
-	// Before we try to restart any service, make sure that systemd is ready
-	if err := sysd.reloadSystemd(); err != nil {
-		return err
-	}
-	args := []string{"restart", service}
-	return exec.Command("systemctl", args...).Run()
+	return withRetry(func() error {
+		// Before we try to restart any service, make sure that systemd is ready
+		if err := sysd.reloadSystemd(); err != nil {
+			return err
+		}
+		args := []string{"restart", service}
+		return exec.Command("systemctl", args...).Run()
+	}, retryPolicy)
 }
 
 // ServiceStop tries to stop a specific service
 func (sysd SystemdInitSystem) ServiceStop(service string) error {
-	args := []string{"stop", service}
-	return exec.Command("systemctl", args...).Run()
+	return withRetry(func() error {
+		args := []string{"stop", service}
+		return exec.Command("systemctl", args...).Run()
+	}, retryPolicy)
 }
 
 // ServiceExists ensures the service is defined for this init system.
@@ -153,15 +194,34 @@ func (sysd SystemdInitSystem) ServiceIsActive(service string) bool {
 // if we cannot detect a supported init system.
 // This indicates we will skip init system checks, not an error.
 func GetInitSystem() (InitSystem, error) {
+	// An explicitly named init system takes precedence over auto-detection.
+	if name := os.Getenv(InitSystemEnvVar); name != "" {
+		return initSystemFromName(name)
+	}
 	// Assume existence of systemctl in path implies this is a systemd system:
 	_, err := exec.LookPath("systemctl")
 	if err == nil {
 		return &SystemdInitSystem{}, nil
 	}
-	_, err = exec.LookPath("openrc")
-	if err == nil {
-		return &OpenRCInitSystem{}, nil
+	// OpenRC ships rc-service, which the implementation shells out to; some
+	// distributions additionally install an "openrc" binary.
+	for _, binary := range []string{"rc-service", "openrc"} {
+		if _, err := exec.LookPath(binary); err == nil {
+			return &OpenRCInitSystem{}, nil
+		}
 	}
 
 	return nil, errors.New("no supported init system detected, skipping checking for services")
 }
+
+// initSystemFromName returns the init system implementation named in the
+// KUBEADM_INIT_SYSTEM environment variable.
+func initSystemFromName(name string) (InitSystem, error) {
+	switch name {
+	case "systemd":
+		return &SystemdInitSystem{}, nil
+	case "openrc":
+		return &OpenRCInitSystem{}, nil
+	}
+	return nil, errors.Errorf("unknown init system %q set in %s, supported values are \"systemd\" and \"openrc\"", name, InitSystemEnvVar)
+}
@@ -0,0 +1,200 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initsystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFakeBinary places an executable shell script named name in dir that
+// prints the value of the FAKE_RC_OUTPUT environment variable, standing in for
+// the real init system binary.
+func writeFakeBinary(t *testing.T, dir, name string) {
+	t.Helper()
+	script := "#!/bin/sh\nprintf '%s' \"$FAKE_RC_OUTPUT\"\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+		t.Fatalf("could not write fake %s: %v", name, err)
+	}
+}
+
+func TestGetInitSystemDetectsOpenRC(t *testing.T) {
+	dir := t.TempDir()
+	writeFakeBinary(t, dir, "rc-service")
+	t.Setenv("PATH", dir)
+
+	sys, err := GetInitSystem()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := sys.(*OpenRCInitSystem); !ok {
+		t.Fatalf("expected an OpenRCInitSystem, got %T", sys)
+	}
+}
+
+func TestGetInitSystemNoneDetected(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	if sys, err := GetInitSystem(); err == nil {
+		t.Fatalf("expected an error, got init system %T", sys)
+	}
+}
+
+func TestGetInitSystemEnvOverride(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    InitSystem
+		wantErr bool
+	}{
+		{
+			name:  "systemd override without systemctl installed",
+			value: "systemd",
+			want:  &SystemdInitSystem{},
+		},
+		{
+			name:  "openrc override without rc-service installed",
+			value: "openrc",
+			want:  &OpenRCInitSystem{},
+		},
+		{
+			name:    "unknown init system name",
+			value:   "upstart",
+			wantErr: true,
+		},
+		{
+			name:    "empty value falls back to detection",
+			value:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// No init system binaries in PATH, so any non-error result
+			// must come from the environment variable override.
+			t.Setenv("PATH", t.TempDir())
+			t.Setenv(InitSystemEnvVar, tc.value)
+
+			sys, err := GetInitSystem()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got init system %T", sys)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprintf("%T", sys) != fmt.Sprintf("%T", tc.want) {
+				t.Fatalf("expected a %T, got %T", tc.want, sys)
+			}
+		})
+	}
+}
+
+func TestOpenRCServiceStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantExists bool
+		wantActive bool
+	}{
+		{
+			name:       "running service",
+			output:     " * status: started",
+			wantExists: true,
+			wantActive: true,
+		},
+		{
+			name:       "stopped service",
+			output:     " * status: stopped",
+			wantExists: true,
+			wantActive: false,
+		},
+		{
+			name:       "unknown service",
+			output:     " * rc-service: service `kubelet' does not exist",
+			wantExists: false,
+			wantActive: false,
+		},
+	}
+
+	dir := t.TempDir()
+	writeFakeBinary(t, dir, "rc-service")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("PATH", dir)
+			t.Setenv("FAKE_RC_OUTPUT", tc.output)
+
+			openrc := OpenRCInitSystem{}
+			if exists := openrc.ServiceExists("kubelet"); exists != tc.wantExists {
+				t.Errorf("expected ServiceExists to return %v, got %v", tc.wantExists, exists)
+			}
+			if active := openrc.ServiceIsActive("kubelet"); active != tc.wantActive {
+				t.Errorf("expected ServiceIsActive to return %v, got %v", tc.wantActive, active)
+			}
+		})
+	}
+}
+
+func TestOpenRCEnableCommand(t *testing.T) {
+	openrc := OpenRCInitSystem{}
+	want := fmt.Sprintf("rc-update add %s default", "kubelet")
+	if got := openrc.EnableCommand("kubelet"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestLogCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		initSystem InitSystem
+		want       []string
+	}{
+		{
+			name:       "systemd",
+			initSystem: SystemdInitSystem{},
+			want:       []string{"systemctl status kubelet", "journalctl -xeu kubelet"},
+		},
+		{
+			name:       "openrc",
+			initSystem: OpenRCInitSystem{},
+			want:       []string{"rc-service kubelet status"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.initSystem.LogCommands("kubelet")
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("expected %v, got %v", tc.want, got)
+				}
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ package initsystem
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,17 +29,40 @@ import (
 	"golang.org/x/sys/windows/svc/mgr"
 )
 
-// WindowsInitSystem is the windows implementation of InitSystem
+// WindowsInitSystem is the windows implementation of InitSystem. It drives
+// services through the Service Control Manager, so a kubelet running as a
+// Windows service can be queried and restarted like its systemd counterpart.
 type WindowsInitSystem struct{}
 
+// Name returns the name of the init system
+func (sysd WindowsInitSystem) Name() string {
+	return "windows-service"
+}
+
 // EnableCommand return a string describing how to enable a service
 func (sysd WindowsInitSystem) EnableCommand(service string) string {
 	return fmt.Sprintf("Set-Service '%s' -StartupType Automatic", service)
 }
 
+// LogCommands returns example commands for inspecting the status and recent
+// logs of a service
+func (sysd WindowsInitSystem) LogCommands(service string) []string {
+	return []string{
+		fmt.Sprintf("Get-Service '%s'", service),
+		fmt.Sprintf("Get-EventLog -LogName System -Source '%s' -Newest 20", service),
+	}
+}
+
 // ServiceStart tries to start a specific service
-// Following Windows documentation: https://docs.microsoft.com/en-us/windows/desktop/Services/starting-a-service
 func (sysd WindowsInitSystem) ServiceStart(service string) error {
+	return withRetry(func() error {
+		return sysd.serviceStart(service)
+	}, retryPolicy)
+}
+
+// serviceStart performs a single start attempt.
+// Following Windows documentation: https://docs.microsoft.com/en-us/windows/desktop/Services/starting-a-service
+func (sysd WindowsInitSystem) serviceStart(service string) error {
 	m, err := mgr.Connect()
 	if err != nil {
 		return err
@@ -98,21 +122,32 @@ func (sysd WindowsInitSystem) ServiceStart(service string) error {
 	return nil
 }
 
-// ServiceRestart tries to reload the environment and restart the specific service
+// ServiceRestart tries to reload the environment and restart the specific service.
+// The stop and start are retried as one operation so that a successful stop
+// followed by a failing start is retried from the beginning.
 func (sysd WindowsInitSystem) ServiceRestart(service string) error {
-	if err := sysd.ServiceStop(service); err != nil {
-		return errors.Wrapf(err, "couldn't stop service %s", service)
-	}
-	if err := sysd.ServiceStart(service); err != nil {
-		return errors.Wrapf(err, "couldn't start service %s", service)
-	}
+	return withRetry(func() error {
+		if err := sysd.serviceStop(service); err != nil {
+			return errors.Wrapf(err, "couldn't stop service %s", service)
+		}
+		if err := sysd.serviceStart(service); err != nil {
+			return errors.Wrapf(err, "couldn't start service %s", service)
+		}
 
-	return nil
+		return nil
+	}, retryPolicy)
 }
 
 // ServiceStop tries to stop a specific service
-// Following Windows documentation: https://docs.microsoft.com/en-us/windows/desktop/Services/stopping-a-service
 func (sysd WindowsInitSystem) ServiceStop(service string) error {
+	return withRetry(func() error {
+		return sysd.serviceStop(service)
+	}, retryPolicy)
+}
+
+// serviceStop performs a single stop attempt.
+// Following Windows documentation: https://docs.microsoft.com/en-us/windows/desktop/Services/stopping-a-service
+func (sysd WindowsInitSystem) serviceStop(service string) error {
 	m, err := mgr.Connect()
 	if err != nil {
 		return err
@@ -238,6 +273,10 @@ func (sysd WindowsInitSystem) ServiceIsActive(service string) bool {
 // if we cannot detect a supported init system.
 // This indicates we will skip init system checks, not an error.
 func GetInitSystem() (InitSystem, error) {
+	// An explicitly named init system takes precedence over auto-detection.
+	if name := os.Getenv(InitSystemEnvVar); name != "" {
+		return initSystemFromName(name)
+	}
 	m, err := mgr.Connect()
 	if err != nil {
 		return nil, errors.Wrap(err, "no supported init system detected")
@@ -245,3 +284,12 @@ func GetInitSystem() (InitSystem, error) {
 	defer m.Disconnect()
 	return &WindowsInitSystem{}, nil
 }
+
+// initSystemFromName returns the init system implementation named in the
+// KUBEADM_INIT_SYSTEM environment variable.
+func initSystemFromName(name string) (InitSystem, error) {
+	if name != "windows" {
+		return nil, errors.Errorf("unknown init system %q set in %s, the only supported value is \"windows\"", name, InitSystemEnvVar)
+	}
+	return &WindowsInitSystem{}, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initsystem
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy describes how transiently failing init system operations are
+// retried: a fixed number of attempts with exponentially growing delays.
+type RetryPolicy struct {
+	// Attempts is the total number of times an operation is tried before its
+	// last error is returned. Values below one are treated as one.
+	Attempts int
+	// BaseDelay is the delay after the first failed attempt. The delay
+	// doubles after every further failure.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts. Zero leaves it uncapped.
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay added to it at random, spreading
+	// out retries that would otherwise run in lockstep. Zero disables jitter.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the policy applied to init system lifecycle
+// operations unless SetRetryPolicy configured another one. Service managers
+// fail transiently while a unit is being reconfigured or the manager itself
+// is restarting, and a few short retries absorb most of those failures.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:  3,
+		BaseDelay: 500 * time.Millisecond,
+		MaxDelay:  5 * time.Second,
+		Jitter:    0.1,
+	}
+}
+
+// retryPolicy is the globally configured policy applied to all init system
+// lifecycle operations.
+var retryPolicy = DefaultRetryPolicy()
+
+// SetRetryPolicy configures the policy applied to subsequent init system
+// lifecycle operations. It is meant to be called once during startup and is
+// not safe to call concurrently with operations in flight.
+func SetRetryPolicy(policy RetryPolicy) {
+	retryPolicy = policy
+}
+
+// sleep is stubbed out by tests to observe delays without waiting them out.
+var sleep = time.Sleep
+
+// withRetry runs op, retrying failures per the policy, and returns the error
+// of the last attempt.
+func withRetry(op func() error, policy RetryPolicy) error {
+	delay := policy.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if attempt >= policy.Attempts {
+			return err
+		}
+		step := delay
+		if policy.MaxDelay > 0 && step > policy.MaxDelay {
+			step = policy.MaxDelay
+		}
+		if policy.Jitter > 0 {
+			step += time.Duration(policy.Jitter * rand.Float64() * float64(step))
+		}
+		sleep(step)
+		delay *= 2
+	}
+}
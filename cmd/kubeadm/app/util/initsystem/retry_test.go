@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initsystem
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestWithRetry(t *testing.T) {
+	policy := RetryPolicy{
+		Attempts:  3,
+		BaseDelay: 500 * time.Millisecond,
+		MaxDelay:  5 * time.Second,
+	}
+
+	// The same policy backs every lifecycle operation (start, stop, restart,
+	// enable), so exercising the wrapper covers them uniformly.
+	tests := []struct {
+		name          string
+		policy        RetryPolicy
+		failures      int
+		wantCalls     int
+		wantDelays    []time.Duration
+		expectedError bool
+	}{
+		{
+			name:      "success on the first attempt",
+			policy:    policy,
+			failures:  0,
+			wantCalls: 1,
+		},
+		{
+			name:       "success after retries",
+			policy:     policy,
+			failures:   2,
+			wantCalls:  3,
+			wantDelays: []time.Duration{500 * time.Millisecond, time.Second},
+		},
+		{
+			name:          "exhaustion returns the last error",
+			policy:        policy,
+			failures:      5,
+			wantCalls:     3,
+			wantDelays:    []time.Duration{500 * time.Millisecond, time.Second},
+			expectedError: true,
+		},
+		{
+			name: "delays are capped at the maximum",
+			policy: RetryPolicy{
+				Attempts:  4,
+				BaseDelay: 2 * time.Second,
+				MaxDelay:  3 * time.Second,
+			},
+			failures:      5,
+			wantCalls:     4,
+			wantDelays:    []time.Duration{2 * time.Second, 3 * time.Second, 3 * time.Second},
+			expectedError: true,
+		},
+		{
+			name:          "attempts below one still run the operation once",
+			policy:        RetryPolicy{Attempts: 0},
+			failures:      1,
+			wantCalls:     1,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var delays []time.Duration
+			sleep = func(d time.Duration) {
+				delays = append(delays, d)
+			}
+			defer func() { sleep = time.Sleep }()
+
+			calls := 0
+			op := func() error {
+				calls++
+				if calls <= tc.failures {
+					return errors.Errorf("attempt %d failed", calls)
+				}
+				return nil
+			}
+
+			err := withRetry(op, tc.policy)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("expected error: %v, got: %v, error: %v", tc.expectedError, err != nil, err)
+			}
+			if calls != tc.wantCalls {
+				t.Fatalf("expected %d calls, got %d", tc.wantCalls, calls)
+			}
+			if !reflect.DeepEqual(delays, tc.wantDelays) {
+				t.Fatalf("expected delays %v, got %v", tc.wantDelays, delays)
+			}
+		})
+	}
+}
+
+func TestSetRetryPolicy(t *testing.T) {
+	defer SetRetryPolicy(DefaultRetryPolicy())
+
+	want := RetryPolicy{Attempts: 1}
+	SetRetryPolicy(want)
+	if !reflect.DeepEqual(retryPolicy, want) {
+		t.Fatalf("expected policy %+v, got %+v", want, retryPolicy)
+	}
+}
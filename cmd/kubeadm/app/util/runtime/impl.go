@@ -36,6 +36,7 @@ type impl interface {
 	RemovePodSandbox(ctx context.Context, runtimeService criapi.RuntimeService, podSandboxID string) error
 	PullImage(ctx context.Context, imageService criapi.ImageManagerService, image *runtimeapi.ImageSpec, auth *runtimeapi.AuthConfig, podSandboxConfig *runtimeapi.PodSandboxConfig) (string, error)
 	ImageStatus(ctx context.Context, imageService criapi.ImageManagerService, image *runtimeapi.ImageSpec, verbose bool) (*runtimeapi.ImageStatusResponse, error)
+	Version(ctx context.Context, runtimeService criapi.RuntimeService, apiVersion string) (*runtimeapi.VersionResponse, error)
 }
 
 func (*defaultImpl) NewRemoteRuntimeService(endpoint string, connectionTimeout time.Duration) (criapi.RuntimeService, error) {
@@ -69,3 +70,7 @@ func (*defaultImpl) PullImage(ctx context.Context, imageService criapi.ImageMana
 func (*defaultImpl) ImageStatus(ctx context.Context, imageService criapi.ImageManagerService, image *runtimeapi.ImageSpec, verbose bool) (*runtimeapi.ImageStatusResponse, error) {
 	return imageService.ImageStatus(ctx, image, verbose)
 }
+
+func (*defaultImpl) Version(ctx context.Context, runtimeService criapi.RuntimeService, apiVersion string) (*runtimeapi.VersionResponse, error) {
+	return runtimeService.Version(ctx, apiVersion)
+}
@@ -20,6 +20,7 @@ package runtime
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -240,8 +241,8 @@ func (runtime *CRIRuntime) ImageExists(image string) bool {
 	return true
 }
 
-// detectCRISocketImpl is separated out only for test purposes, DON'T call it directly, use DetectCRISocket instead
-func detectCRISocketImpl(isSocket func(string) bool, knownCRISockets []string) (string, error) {
+// detectCRISocketsImpl is separated out only for test purposes, DON'T call it directly, use DetectCRISockets instead
+func detectCRISocketsImpl(isSocket func(string) bool, knownCRISockets []string) []string {
 	foundCRISockets := []string{}
 
 	for _, socket := range knownCRISockets {
@@ -249,6 +250,18 @@ func detectCRISocketImpl(isSocket func(string) bool, knownCRISockets []string) (
 			foundCRISockets = append(foundCRISockets, socket)
 		}
 	}
+	return foundCRISockets
+}
+
+// DetectCRISockets returns every known CRI endpoint present on the host, for
+// callers that want to surface all candidates instead of requiring exactly one.
+func DetectCRISockets() []string {
+	return detectCRISocketsImpl(isExistingSocket, defaultKnownCRISockets)
+}
+
+// detectCRISocketImpl is separated out only for test purposes, DON'T call it directly, use DetectCRISocket instead
+func detectCRISocketImpl(isSocket func(string) bool, knownCRISockets []string) (string, error) {
+	foundCRISockets := detectCRISocketsImpl(isSocket, knownCRISockets)
 
 	switch len(foundCRISockets) {
 	case 0:
@@ -270,6 +283,105 @@ func DetectCRISocket() (string, error) {
 	return detectCRISocketImpl(isExistingSocket, defaultKnownCRISockets)
 }
 
+// RuntimeVersion queries the runtime for its reported name and version.
+func (runtime *CRIRuntime) RuntimeVersion() (string, string, error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+
+	res, err := runtime.impl.Version(ctx, runtime.runtimeService, "")
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get runtime version")
+	}
+	return res.GetRuntimeName(), res.GetRuntimeVersion(), nil
+}
+
+// RuntimeConfigInfo queries the runtime for its verbose status and returns the
+// configuration it reports under the "config" info key, flattened into dotted
+// string keys (e.g. "cgroupDriver" or "plugins.cri.sandboxImage").
+func (runtime *CRIRuntime) RuntimeConfigInfo() (map[string]string, error) {
+	ctx, cancel := defaultContext()
+	defer cancel()
+
+	res, err := runtime.impl.Status(ctx, runtime.runtimeService, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get runtime status")
+	}
+	configJSON, ok := res.GetInfo()["config"]
+	if !ok {
+		return nil, errors.New("the runtime status info does not contain a configuration")
+	}
+	config := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the runtime configuration")
+	}
+	flattened := map[string]string{}
+	flattenConfigInfo("", config, flattened)
+	return flattened, nil
+}
+
+// flattenConfigInfo recursively flattens a parsed runtime configuration into
+// dotted string keys with their values rendered as strings.
+func flattenConfigInfo(prefix string, value interface{}, out map[string]string) {
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		out[prefix] = fmt.Sprintf("%v", value)
+		return
+	}
+	for key, val := range nested {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenConfigInfo(key, val, out)
+	}
+}
+
+// runtimeNameForSocket returns the runtime name implied by one of the known CRI
+// socket paths, or an empty string if the socket path is not a known one.
+func runtimeNameForSocket(socket string) string {
+	switch socket {
+	case constants.CRISocketContainerd:
+		return "containerd"
+	case constants.CRISocketCRIO:
+		return "cri-o"
+	case constants.CRISocketDocker:
+		return "docker"
+	}
+	return ""
+}
+
+// checkRuntimeSocketMatchImpl is separated out only for test purposes, DON'T call it directly, use CheckRuntimeSocketMatch instead
+func checkRuntimeSocketMatchImpl(configuredSocket string, probe func(socket string) (string, string, error)) (bool, string, error) {
+	impliedName := runtimeNameForSocket(configuredSocket)
+	if impliedName == "" {
+		return true, fmt.Sprintf("the CRI socket %s does not imply a known container runtime; skipping the runtime name check", configuredSocket), nil
+	}
+
+	name, version, err := probe(configuredSocket)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to query the container runtime on the CRI socket %s", configuredSocket)
+	}
+
+	if name != impliedName {
+		return false, fmt.Sprintf("the CRI socket %s implies the container runtime %q, but the runtime on the socket reported its name as %q (version %s)",
+			configuredSocket, impliedName, name, version), nil
+	}
+	return true, fmt.Sprintf("the container runtime %q (version %s) matches the CRI socket %s", name, version, configuredSocket), nil
+}
+
+// CheckRuntimeSocketMatch probes the given CRI socket and verifies that the runtime
+// answering on it is the runtime the socket path implies. It returns whether the
+// runtimes match and a human readable detail about the outcome. Sockets that do not
+// map to a known runtime are treated as matching, since nothing can be implied from them.
+func CheckRuntimeSocketMatch(configuredSocket string) (bool, string, error) {
+	return checkRuntimeSocketMatchImpl(configuredSocket, func(socket string) (string, string, error) {
+		containerRuntime := NewContainerRuntime(socket)
+		if err := containerRuntime.Connect(); err != nil {
+			return "", "", err
+		}
+		return containerRuntime.(*CRIRuntime).RuntimeVersion()
+	})
+}
+
 // SandboxImage returns the sandbox image used by the container runtime
 func (runtime *CRIRuntime) SandboxImage() (string, error) {
 	ctx, cancel := defaultContext()
@@ -55,6 +55,10 @@ type fakeImpl struct {
 	stopPodSandboxReturns struct {
 		res error
 	}
+	versionReturns struct {
+		res *v1.VersionResponse
+		err error
+	}
 }
 
 func (fake *fakeImpl) ImageStatus(context.Context, cri.ImageManagerService, *v1.ImageSpec, bool) (*v1.ImageStatusResponse, error) {
@@ -150,3 +154,15 @@ func (fake *fakeImpl) StopPodSandboxReturns(res error) {
 		res error
 	}{res}
 }
+
+func (fake *fakeImpl) Version(context.Context, cri.RuntimeService, string) (*v1.VersionResponse, error) {
+	fakeReturns := fake.versionReturns
+	return fakeReturns.res, fakeReturns.err
+}
+
+func (fake *fakeImpl) VersionReturns(res *v1.VersionResponse, err error) {
+	fake.versionReturns = struct {
+		res *v1.VersionResponse
+		err error
+	}{res, err}
+}
@@ -415,6 +415,179 @@ func TestIsExistingSocket(t *testing.T) {
 	}
 }
 
+func TestRuntimeVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		prepare         func(*fakeImpl)
+		shouldError     bool
+		expectedName    string
+		expectedVersion string
+	}{
+		{
+			name: "valid",
+			prepare: func(mock *fakeImpl) {
+				mock.VersionReturns(&v1.VersionResponse{
+					RuntimeName:    "containerd",
+					RuntimeVersion: "1.7.0",
+				}, nil)
+			},
+			expectedName:    "containerd",
+			expectedVersion: "1.7.0",
+		},
+		{
+			name: "invalid: version query fails",
+			prepare: func(mock *fakeImpl) {
+				mock.VersionReturns(nil, errTest)
+			},
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			containerRuntime := NewContainerRuntime("")
+			mock := &fakeImpl{}
+			if tc.prepare != nil {
+				tc.prepare(mock)
+			}
+			containerRuntime.SetImpl(mock)
+
+			name, version, err := containerRuntime.(*CRIRuntime).RuntimeVersion()
+
+			assert.Equal(t, tc.shouldError, err != nil)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestRuntimeConfigInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		prepare        func(*fakeImpl)
+		shouldError    bool
+		expectedConfig map[string]string
+	}{
+		{
+			name: "valid: nested configuration gets flattened",
+			prepare: func(mock *fakeImpl) {
+				mock.StatusReturns(&v1.StatusResponse{
+					Info: map[string]string{
+						"config": `{"cgroupDriver":"systemd","plugins":{"cri":{"sandboxImage":"registry.k8s.io/pause:3.10","systemdCgroup":true}}}`,
+					},
+				}, nil)
+			},
+			expectedConfig: map[string]string{
+				"cgroupDriver":              "systemd",
+				"plugins.cri.sandboxImage":  "registry.k8s.io/pause:3.10",
+				"plugins.cri.systemdCgroup": "true",
+			},
+		},
+		{
+			name: "invalid: status query fails",
+			prepare: func(mock *fakeImpl) {
+				mock.StatusReturns(nil, errTest)
+			},
+			shouldError: true,
+		},
+		{
+			name: "invalid: status info has no config key",
+			prepare: func(mock *fakeImpl) {
+				mock.StatusReturns(&v1.StatusResponse{
+					Info: map[string]string{},
+				}, nil)
+			},
+			shouldError: true,
+		},
+		{
+			name: "invalid: config is not valid JSON",
+			prepare: func(mock *fakeImpl) {
+				mock.StatusReturns(&v1.StatusResponse{
+					Info: map[string]string{"config": "not JSON"},
+				}, nil)
+			},
+			shouldError: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			containerRuntime := NewContainerRuntime("")
+			mock := &fakeImpl{}
+			if tc.prepare != nil {
+				tc.prepare(mock)
+			}
+			containerRuntime.SetImpl(mock)
+
+			config, err := containerRuntime.(*CRIRuntime).RuntimeConfigInfo()
+
+			assert.Equal(t, tc.shouldError, err != nil)
+			assert.Equal(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func TestCheckRuntimeSocketMatchImpl(t *testing.T) {
+	tests := []struct {
+		name          string
+		socket        string
+		runtimeName   string
+		probeError    error
+		expectedMatch bool
+		expectedError bool
+	}{
+		{
+			name:          "matching runtime",
+			socket:        constants.CRISocketContainerd,
+			runtimeName:   "containerd",
+			expectedMatch: true,
+		},
+		{
+			name:          "mismatched runtime",
+			socket:        constants.CRISocketContainerd,
+			runtimeName:   "cri-o",
+			expectedMatch: false,
+		},
+		{
+			name:          "unknown socket skips the check",
+			socket:        "unix:///foo/bar.sock",
+			expectedMatch: true,
+		},
+		{
+			name:          "probe failure",
+			socket:        constants.CRISocketCRIO,
+			probeError:    errTest,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			probed := false
+			match, detail, err := checkRuntimeSocketMatchImpl(test.socket, func(socket string) (string, string, error) {
+				probed = true
+				if test.probeError != nil {
+					return "", "", test.probeError
+				}
+				return test.runtimeName, "1.0.0", nil
+			})
+
+			if (err != nil) != test.expectedError {
+				t.Fatalf("checkRuntimeSocketMatchImpl returned unexpected result\n\tExpected error: %t\n\tGot error: %v", test.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if match != test.expectedMatch {
+				t.Fatalf("checkRuntimeSocketMatchImpl returned unexpected match\n\tExpected match: %t\n\tGot match: %t\n\tDetail: %s",
+					test.expectedMatch, match, detail)
+			}
+			if test.runtimeName == "" && probed {
+				t.Fatal("checkRuntimeSocketMatchImpl probed the runtime for a socket that does not imply one")
+			}
+			if detail == "" {
+				t.Fatal("checkRuntimeSocketMatchImpl returned an empty detail")
+			}
+		})
+	}
+}
+
 func TestDetectCRISocketImpl(t *testing.T) {
 	tests := []struct {
 		name            string
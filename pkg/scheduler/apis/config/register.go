@@ -43,6 +43,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NodeResourcesFitArgs{},
 		&PodTopologySpreadArgs{},
 		&VolumeBindingArgs{},
+		&VolumeRestrictionsArgs{},
 		&NodeResourcesBalancedAllocationArgs{},
 		&NodeAffinityArgs{},
 	)
@@ -164,6 +164,16 @@ profiles:
 									{Name: "scalar3", Weight: 2}},
 							},
 						},
+						{
+							Name: "VolumeRestrictions",
+							Args: &config.VolumeRestrictionsArgs{
+								DiskConflictMode:              config.DiskConflictEnforce,
+								UnknownCSIDriverPolicy:        config.UnknownCSIDriverStrict,
+								ScoreWeight:                   1,
+								EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+								FlexVolumeIdentityKeys:        []string{"volumeID"},
+							},
+						},
 					},
 				},
 			},
@@ -264,6 +274,16 @@ profiles:
 								BindTimeoutSeconds: 600,
 							},
 						},
+						{
+							Name: "VolumeRestrictions",
+							Args: &config.VolumeRestrictionsArgs{
+								DiskConflictMode:              config.DiskConflictEnforce,
+								UnknownCSIDriverPolicy:        config.UnknownCSIDriverStrict,
+								ScoreWeight:                   1,
+								EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+								FlexVolumeIdentityKeys:        []string{"volumeID"},
+							},
+						},
 					},
 				},
 			},
@@ -412,6 +432,16 @@ profiles:
 								Resources: []config.ResourceSpec{{Name: "cpu", Weight: 1}, {Name: "memory", Weight: 1}},
 							},
 						},
+						{
+							Name: "VolumeRestrictions",
+							Args: &config.VolumeRestrictionsArgs{
+								DiskConflictMode:              config.DiskConflictEnforce,
+								UnknownCSIDriverPolicy:        config.UnknownCSIDriverStrict,
+								ScoreWeight:                   1,
+								EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+								FlexVolumeIdentityKeys:        []string{"volumeID"},
+							},
+						},
 					},
 				},
 			},
@@ -477,6 +507,16 @@ profiles:
 								BindTimeoutSeconds: 600,
 							},
 						},
+						{
+							Name: "VolumeRestrictions",
+							Args: &config.VolumeRestrictionsArgs{
+								DiskConflictMode:              config.DiskConflictEnforce,
+								UnknownCSIDriverPolicy:        config.UnknownCSIDriverStrict,
+								ScoreWeight:                   1,
+								EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+								FlexVolumeIdentityKeys:        []string{"volumeID"},
+							},
+						},
 					},
 				},
 			},
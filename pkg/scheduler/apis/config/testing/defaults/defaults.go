@@ -195,4 +195,14 @@ var PluginConfigsV1 = []config.PluginConfig{
 			BindTimeoutSeconds: 600,
 		},
 	},
+	{
+		Name: "VolumeRestrictions",
+		Args: &config.VolumeRestrictionsArgs{
+			DiskConflictMode:              config.DiskConflictEnforce,
+			UnknownCSIDriverPolicy:        config.UnknownCSIDriverStrict,
+			ScoreWeight:                   1,
+			EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+			FlexVolumeIdentityKeys:        []string{"volumeID"},
+		},
+	},
 }
@@ -206,6 +206,24 @@ func SetDefaults_VolumeBindingArgs(obj *configv1.VolumeBindingArgs) {
 	}
 }
 
+func SetDefaults_VolumeRestrictionsArgs(obj *configv1.VolumeRestrictionsArgs) {
+	if obj.DiskConflictMode == "" {
+		obj.DiskConflictMode = configv1.DiskConflictEnforce
+	}
+	if obj.UnknownCSIDriverPolicy == "" {
+		obj.UnknownCSIDriverPolicy = configv1.UnknownCSIDriverStrict
+	}
+	if obj.ScoreWeight == nil {
+		obj.ScoreWeight = ptr.To[int32](1)
+	}
+	if obj.EnabledDiskConflictCategories == nil {
+		obj.EnabledDiskConflictCategories = []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"}
+	}
+	if obj.FlexVolumeIdentityKeys == nil {
+		obj.FlexVolumeIdentityKeys = []string{"volumeID"}
+	}
+}
+
 func SetDefaults_NodeResourcesBalancedAllocationArgs(obj *configv1.NodeResourcesBalancedAllocationArgs) {
 	if len(obj.Resources) == 0 {
 		obj.Resources = defaultResourceSpec
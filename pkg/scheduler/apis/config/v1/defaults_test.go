@@ -113,6 +113,20 @@ var pluginConfigs = []configv1.PluginConfig{
 			BindTimeoutSeconds: ptr.To[int64](600),
 		}},
 	},
+	{
+		Name: "VolumeRestrictions",
+		Args: runtime.RawExtension{Object: &configv1.VolumeRestrictionsArgs{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "VolumeRestrictionsArgs",
+				APIVersion: "kubescheduler.config.k8s.io/v1",
+			},
+			DiskConflictMode:              configv1.DiskConflictEnforce,
+			UnknownCSIDriverPolicy:        configv1.UnknownCSIDriverStrict,
+			ScoreWeight:                   ptr.To[int32](1),
+			EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+			FlexVolumeIdentityKeys:        []string{"volumeID"},
+		}},
+	},
 }
 
 func TestSchedulerDefaults(t *testing.T) {
@@ -324,6 +338,20 @@ func TestSchedulerDefaults(t *testing.T) {
 									BindTimeoutSeconds: ptr.To[int64](600),
 								}},
 							},
+							{
+								Name: "VolumeRestrictions",
+								Args: runtime.RawExtension{Object: &configv1.VolumeRestrictionsArgs{
+									TypeMeta: metav1.TypeMeta{
+										Kind:       "VolumeRestrictionsArgs",
+										APIVersion: "kubescheduler.config.k8s.io/v1",
+									},
+									DiskConflictMode:              configv1.DiskConflictEnforce,
+									UnknownCSIDriverPolicy:        configv1.UnknownCSIDriverStrict,
+									ScoreWeight:                   ptr.To[int32](1),
+									EnabledDiskConflictCategories: []string{"GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk"},
+									FlexVolumeIdentityKeys:        []string{"volumeID"},
+								}},
+							},
 						},
 					},
 					{
@@ -230,6 +230,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*configv1.VolumeRestrictionsArgs)(nil), (*config.VolumeRestrictionsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_VolumeRestrictionsArgs_To_config_VolumeRestrictionsArgs(a.(*configv1.VolumeRestrictionsArgs), b.(*config.VolumeRestrictionsArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.VolumeRestrictionsArgs)(nil), (*configv1.VolumeRestrictionsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_VolumeRestrictionsArgs_To_v1_VolumeRestrictionsArgs(a.(*config.VolumeRestrictionsArgs), b.(*configv1.VolumeRestrictionsArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*config.KubeSchedulerConfiguration)(nil), (*configv1.KubeSchedulerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_config_KubeSchedulerConfiguration_To_v1_KubeSchedulerConfiguration(a.(*config.KubeSchedulerConfiguration), b.(*configv1.KubeSchedulerConfiguration), scope)
 	}); err != nil {
@@ -944,3 +954,67 @@ func autoConvert_config_VolumeBindingArgs_To_v1_VolumeBindingArgs(in *config.Vol
 func Convert_config_VolumeBindingArgs_To_v1_VolumeBindingArgs(in *config.VolumeBindingArgs, out *configv1.VolumeBindingArgs, s conversion.Scope) error {
 	return autoConvert_config_VolumeBindingArgs_To_v1_VolumeBindingArgs(in, out, s)
 }
+
+func autoConvert_v1_VolumeRestrictionsArgs_To_config_VolumeRestrictionsArgs(in *configv1.VolumeRestrictionsArgs, out *config.VolumeRestrictionsArgs, s conversion.Scope) error {
+	out.DiskConflictMode = config.DiskConflictMode(in.DiskConflictMode)
+	out.EnableConflictReasonAggregation = in.EnableConflictReasonAggregation
+	out.RejectionHistoryLength = in.RejectionHistoryLength
+	out.FailOnMissingAccessModes = in.FailOnMissingAccessModes
+	out.ReadWriteOncePodLabelSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.ReadWriteOncePodLabelSelector))
+	out.ConflictStatusCodes = *(*map[string]string)(unsafe.Pointer(&in.ConflictStatusCodes))
+	out.InformerSyncCheckSeconds = in.InformerSyncCheckSeconds
+	out.AllowSharedWritersAnnotationKey = in.AllowSharedWritersAnnotationKey
+	out.UnknownCSIDriverPolicy = config.UnknownCSIDriverPolicy(in.UnknownCSIDriverPolicy)
+	out.StrictVolumeIdentity = in.StrictVolumeIdentity
+	if err := metav1.Convert_Pointer_int32_To_int32(&in.ScoreWeight, &out.ScoreWeight, s); err != nil {
+		return err
+	}
+	out.EnabledDiskConflictCategories = *(*[]string)(unsafe.Pointer(&in.EnabledDiskConflictCategories))
+	out.EmitConflictEvents = in.EmitConflictEvents
+	out.IgnoreTerminatingPods = in.IgnoreTerminatingPods
+	out.IgnoreMirrorPods = in.IgnoreMirrorPods
+	out.EnforceReadWriteOncePerNode = in.EnforceReadWriteOncePerNode
+	out.ExemptVolumeIdentities = *(*[]string)(unsafe.Pointer(&in.ExemptVolumeIdentities))
+	out.VerifyReadWriteOncePodHolders = in.VerifyReadWriteOncePodHolders
+	out.FlexVolumeIdentityKeys = *(*[]string)(unsafe.Pointer(&in.FlexVolumeIdentityKeys))
+	out.MultiWriterVolumeSources = *(*[]string)(unsafe.Pointer(&in.MultiWriterVolumeSources))
+	out.LegacyVolumeSources = in.LegacyVolumeSources
+	return nil
+}
+
+// Convert_v1_VolumeRestrictionsArgs_To_config_VolumeRestrictionsArgs is an autogenerated conversion function.
+func Convert_v1_VolumeRestrictionsArgs_To_config_VolumeRestrictionsArgs(in *configv1.VolumeRestrictionsArgs, out *config.VolumeRestrictionsArgs, s conversion.Scope) error {
+	return autoConvert_v1_VolumeRestrictionsArgs_To_config_VolumeRestrictionsArgs(in, out, s)
+}
+
+func autoConvert_config_VolumeRestrictionsArgs_To_v1_VolumeRestrictionsArgs(in *config.VolumeRestrictionsArgs, out *configv1.VolumeRestrictionsArgs, s conversion.Scope) error {
+	out.DiskConflictMode = configv1.DiskConflictMode(in.DiskConflictMode)
+	out.EnableConflictReasonAggregation = in.EnableConflictReasonAggregation
+	out.RejectionHistoryLength = in.RejectionHistoryLength
+	out.FailOnMissingAccessModes = in.FailOnMissingAccessModes
+	out.ReadWriteOncePodLabelSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.ReadWriteOncePodLabelSelector))
+	out.ConflictStatusCodes = *(*map[string]string)(unsafe.Pointer(&in.ConflictStatusCodes))
+	out.InformerSyncCheckSeconds = in.InformerSyncCheckSeconds
+	out.AllowSharedWritersAnnotationKey = in.AllowSharedWritersAnnotationKey
+	out.UnknownCSIDriverPolicy = configv1.UnknownCSIDriverPolicy(in.UnknownCSIDriverPolicy)
+	out.StrictVolumeIdentity = in.StrictVolumeIdentity
+	if err := metav1.Convert_int32_To_Pointer_int32(&in.ScoreWeight, &out.ScoreWeight, s); err != nil {
+		return err
+	}
+	out.EnabledDiskConflictCategories = *(*[]string)(unsafe.Pointer(&in.EnabledDiskConflictCategories))
+	out.EmitConflictEvents = in.EmitConflictEvents
+	out.IgnoreTerminatingPods = in.IgnoreTerminatingPods
+	out.IgnoreMirrorPods = in.IgnoreMirrorPods
+	out.EnforceReadWriteOncePerNode = in.EnforceReadWriteOncePerNode
+	out.ExemptVolumeIdentities = *(*[]string)(unsafe.Pointer(&in.ExemptVolumeIdentities))
+	out.VerifyReadWriteOncePodHolders = in.VerifyReadWriteOncePodHolders
+	out.FlexVolumeIdentityKeys = *(*[]string)(unsafe.Pointer(&in.FlexVolumeIdentityKeys))
+	out.MultiWriterVolumeSources = *(*[]string)(unsafe.Pointer(&in.MultiWriterVolumeSources))
+	out.LegacyVolumeSources = in.LegacyVolumeSources
+	return nil
+}
+
+// Convert_config_VolumeRestrictionsArgs_To_v1_VolumeRestrictionsArgs is an autogenerated conversion function.
+func Convert_config_VolumeRestrictionsArgs_To_v1_VolumeRestrictionsArgs(in *config.VolumeRestrictionsArgs, out *configv1.VolumeRestrictionsArgs, s conversion.Scope) error {
+	return autoConvert_config_VolumeRestrictionsArgs_To_v1_VolumeRestrictionsArgs(in, out, s)
+}
@@ -41,6 +41,9 @@ func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&configv1.NodeResourcesFitArgs{}, func(obj interface{}) { SetObjectDefaults_NodeResourcesFitArgs(obj.(*configv1.NodeResourcesFitArgs)) })
 	scheme.AddTypeDefaultingFunc(&configv1.PodTopologySpreadArgs{}, func(obj interface{}) { SetObjectDefaults_PodTopologySpreadArgs(obj.(*configv1.PodTopologySpreadArgs)) })
 	scheme.AddTypeDefaultingFunc(&configv1.VolumeBindingArgs{}, func(obj interface{}) { SetObjectDefaults_VolumeBindingArgs(obj.(*configv1.VolumeBindingArgs)) })
+	scheme.AddTypeDefaultingFunc(&configv1.VolumeRestrictionsArgs{}, func(obj interface{}) {
+		SetObjectDefaults_VolumeRestrictionsArgs(obj.(*configv1.VolumeRestrictionsArgs))
+	})
 	return nil
 }
 
@@ -71,3 +74,7 @@ func SetObjectDefaults_PodTopologySpreadArgs(in *configv1.PodTopologySpreadArgs)
 func SetObjectDefaults_VolumeBindingArgs(in *configv1.VolumeBindingArgs) {
 	SetDefaults_VolumeBindingArgs(in)
 }
+
+func SetObjectDefaults_VolumeRestrictionsArgs(in *configv1.VolumeRestrictionsArgs) {
+	SetDefaults_VolumeRestrictionsArgs(in)
+}
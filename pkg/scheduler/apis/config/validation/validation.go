@@ -156,6 +156,7 @@ func validatePluginConfig(path *field.Path, apiVersion string, profile *config.K
 		"NodeResourcesFitArgs":            ValidateNodeResourcesFitArgs,
 		"PodTopologySpread":               ValidatePodTopologySpreadArgs,
 		"VolumeBinding":                   ValidateVolumeBindingArgs,
+		"VolumeRestrictions":              ValidateVolumeRestrictionsArgs,
 	}
 
 	if profile.Plugins != nil {
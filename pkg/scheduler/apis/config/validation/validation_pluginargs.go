@@ -24,6 +24,7 @@ import (
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
@@ -290,6 +291,142 @@ func ValidateVolumeBindingArgsWithOptions(path *field.Path, args *config.VolumeB
 	return allErrs.ToAggregate()
 }
 
+// supportedDiskConflictModes has to be a set of strings for use with field.Unsupported
+var supportedDiskConflictModes = sets.New(
+	string(config.DiskConflictEnforce),
+	string(config.DiskConflictWarn),
+	string(config.DiskConflictOff),
+)
+
+// supportedUnknownCSIDriverPolicies has to be a set of strings for use with field.Unsupported
+var supportedUnknownCSIDriverPolicies = sets.New(
+	string(config.UnknownCSIDriverStrict),
+	string(config.UnknownCSIDriverPermissive),
+)
+
+// supportedConflictCategories are the conflict category names accepted as keys
+// of VolumeRestrictionsArgs.ConflictStatusCodes. ScaleIO and StorageOS only
+// take effect when LegacyVolumeSources is set, but their status codes may be
+// configured regardless.
+var supportedConflictCategories = sets.New(
+	"GCEPersistentDisk",
+	"AWSElasticBlockStore",
+	"ISCSI",
+	"RBD",
+	"FibreChannel",
+	"FlexVolume",
+	"VsphereVolume",
+	"Cinder",
+	"AzureDisk",
+	"ScaleIO",
+	"StorageOS",
+	"ReadWriteOncePod",
+)
+
+// supportedConflictStatusCodes are the status code names accepted as values
+// of VolumeRestrictionsArgs.ConflictStatusCodes.
+var supportedConflictStatusCodes = sets.New(
+	"Unschedulable",
+	"UnschedulableAndUnresolvable",
+)
+
+// supportedDiskConflictCategories are the category names accepted as entries
+// of VolumeRestrictionsArgs.EnabledDiskConflictCategories. ReadWriteOncePod
+// is not among them; it is not a disk conflict category.
+var supportedDiskConflictCategories = sets.New(
+	"GCEPersistentDisk",
+	"AWSElasticBlockStore",
+	"ISCSI",
+	"RBD",
+	"FibreChannel",
+	"FlexVolume",
+	"VsphereVolume",
+	"Cinder",
+	"AzureDisk",
+)
+
+// supportedVolumeIdentitySchemes are the identity key schemes accepted in
+// VolumeRestrictionsArgs.ExemptVolumeIdentities, matching the schemes the
+// plugin uses when computing volume identity keys.
+var supportedVolumeIdentitySchemes = sets.New(
+	"gce",
+	"aws",
+	"iscsi",
+	"rbd",
+	"fc",
+	"flex",
+	"vsphere",
+	"cinder",
+	"azuredisk",
+	"scaleio",
+	"storageos",
+)
+
+// ValidateVolumeRestrictionsArgs validates that VolumeRestrictionsArgs are correct.
+func ValidateVolumeRestrictionsArgs(path *field.Path, args *config.VolumeRestrictionsArgs) error {
+	var allErrs field.ErrorList
+
+	if !supportedDiskConflictModes.Has(string(args.DiskConflictMode)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("diskConflictMode"), args.DiskConflictMode, sets.List(supportedDiskConflictModes)))
+	}
+	if !supportedUnknownCSIDriverPolicies.Has(string(args.UnknownCSIDriverPolicy)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("unknownCSIDriverPolicy"), args.UnknownCSIDriverPolicy, sets.List(supportedUnknownCSIDriverPolicies)))
+	}
+	if args.RejectionHistoryLength < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("rejectionHistoryLength"), args.RejectionHistoryLength, "not in valid range [0, inf)"))
+	}
+	if args.InformerSyncCheckSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("informerSyncCheckSeconds"), args.InformerSyncCheckSeconds, "not in valid range [0, inf)"))
+	}
+	if args.ScoreWeight < 0 || args.ScoreWeight > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("scoreWeight"), args.ScoreWeight, "not in valid range [0, 100]"))
+	}
+	if args.ReadWriteOncePodLabelSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(args.ReadWriteOncePodLabelSelector, metav1validation.LabelSelectorValidationOptions{AllowInvalidLabelValueInSelector: false}, path.Child("readWriteOncePodLabelSelector"))...)
+	}
+	if args.AllowSharedWritersAnnotationKey != "" {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(args.AllowSharedWritersAnnotationKey)) {
+			allErrs = append(allErrs, field.Invalid(path.Child("allowSharedWritersAnnotationKey"), args.AllowSharedWritersAnnotationKey, msg))
+		}
+	}
+	conflictStatusCodesPath := path.Child("conflictStatusCodes")
+	for category, code := range args.ConflictStatusCodes {
+		if !supportedConflictCategories.Has(category) {
+			allErrs = append(allErrs, field.NotSupported(conflictStatusCodesPath, category, sets.List(supportedConflictCategories)))
+		}
+		if !supportedConflictStatusCodes.Has(code) {
+			allErrs = append(allErrs, field.NotSupported(conflictStatusCodesPath.Key(category), code, sets.List(supportedConflictStatusCodes)))
+		}
+	}
+	for i, category := range args.EnabledDiskConflictCategories {
+		if !supportedDiskConflictCategories.Has(category) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("enabledDiskConflictCategories").Index(i), category, sets.List(supportedDiskConflictCategories)))
+		}
+	}
+	for i, identity := range args.ExemptVolumeIdentities {
+		identityPath := path.Child("exemptVolumeIdentities").Index(i)
+		scheme, id, found := strings.Cut(identity, "://")
+		if !found || id == "" {
+			allErrs = append(allErrs, field.Invalid(identityPath, identity, "must be of the form scheme://identifier"))
+			continue
+		}
+		if !supportedVolumeIdentitySchemes.Has(scheme) {
+			allErrs = append(allErrs, field.NotSupported(identityPath, scheme, sets.List(supportedVolumeIdentitySchemes)))
+		}
+	}
+	for i, key := range args.FlexVolumeIdentityKeys {
+		if key == "" {
+			allErrs = append(allErrs, field.Required(path.Child("flexVolumeIdentityKeys").Index(i), "option key must not be empty"))
+		}
+	}
+	for i, category := range args.MultiWriterVolumeSources {
+		if !supportedDiskConflictCategories.Has(category) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("multiWriterVolumeSources").Index(i), category, sets.List(supportedDiskConflictCategories)))
+		}
+	}
+	return allErrs.ToAggregate()
+}
+
 func ValidateNodeResourcesFitArgs(path *field.Path, args *config.NodeResourcesFitArgs) error {
 	var allErrs field.ErrorList
 	resPath := path.Child("ignoredResources")
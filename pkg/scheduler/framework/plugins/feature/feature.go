@@ -30,4 +30,5 @@ type Features struct {
 	EnableSchedulingQueueHint                    bool
 	EnableAsyncPreemption                        bool
 	EnablePodLevelResources                      bool
+	EnableCrossNamespaceVolumeDataSource         bool
 }
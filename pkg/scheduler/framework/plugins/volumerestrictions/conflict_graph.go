@@ -0,0 +1,255 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// ConflictEdge describes a conflict between two pods on a node over one volume.
+type ConflictEdge struct {
+	// OtherPod is the namespaced name of the conflicting pod.
+	OtherPod string
+	// VolumeKey is the identity of the conflicting volume, as produced by
+	// volumeIdentityKey, e.g. "gce://disk-name".
+	VolumeKey string
+}
+
+// ConflictDetail describes one volume over which two pods conflict.
+type ConflictDetail struct {
+	// VolumeKey is the identity of the conflicting volume, as produced by
+	// volumeIdentityKey, e.g. "gce://disk-name".
+	VolumeKey string
+	// Category is the conflict category name, e.g. "GCEPersistentDisk".
+	Category string
+}
+
+// PodsConflict reports whether two arbitrary pods conflict on any inline volume,
+// using the same identity and matcher logic as Filter but without node context.
+// It returns one detail per conflicting volume identity, for use by external
+// dedup and placement tooling.
+func PodsConflict(a, b *v1.Pod) (bool, []ConflictDetail) {
+	bIdentities := &nodeVolumeIdentities{identities: make(map[string]*volumeIdentityEntry)}
+	bIdentities.addPod(b)
+
+	var details []ConflictDetail
+	seen := sets.New[string]()
+	for i := range a.Spec.Volumes {
+		volume := &a.Spec.Volumes[i]
+		if !needsRestrictionsCheck(*volume, false) {
+			continue
+		}
+		if !bIdentities.conflicts(volume) {
+			continue
+		}
+		key, _ := volumeIdentityKey(volume)
+		if seen.Has(key) {
+			continue
+		}
+		seen.Insert(key)
+		details = append(details, ConflictDetail{
+			VolumeKey: key,
+			Category:  conflictCategoryNames[conflictCategoryForVolume(volume)],
+		})
+	}
+	return len(details) > 0, details
+}
+
+// conflictDetailsForNode returns one ConflictDetail per restriction-relevant
+// volume of the pod conflicting on the node, with the plugin configuration
+// applied the same way Filter applies it. It returns nil when disk conflict
+// checking is disabled.
+func (pl *VolumeRestrictions) conflictDetailsForNode(pod *v1.Pod, nodeInfo *framework.NodeInfo) []ConflictDetail {
+	if pl.diskConflictMode == config.DiskConflictOff {
+		return nil
+	}
+	volumes := volumeIdentitiesForNode(nodeInfo, pl.ignoreTerminatingPods, pl.ignoreMirrorPods, pl.flexVolumeIdentityKeys)
+	return pl.conflictDetailsAgainstIdentities(pod, volumes, nodeInfo)
+}
+
+// conflictDetailsAgainstIdentities returns one ConflictDetail per
+// restriction-relevant volume of the pod conflicting with the given distinct
+// volume identities, with the plugin configuration applied the same way Filter
+// applies it.
+func (pl *VolumeRestrictions) conflictDetailsAgainstIdentities(pod *v1.Pod, volumes *nodeVolumeIdentities, nodeInfo *framework.NodeInfo) []ConflictDetail {
+	var details []ConflictDetail
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		if !needsRestrictionsCheck(*volume, pl.legacyVolumeSources) || !pl.volumeCheckEnabled(volume) {
+			continue
+		}
+		conflicting := false
+		if pl.conflictPolicy != nil {
+			switch pl.conflictPolicy(pod, volume, nodeInfo) {
+			case ConflictPolicyAllow:
+				continue
+			case ConflictPolicyDeny:
+				conflicting = true
+			}
+		}
+		if !conflicting && !volumes.conflicts(volume) {
+			continue
+		}
+		key, _ := volumes.volumeKey(volume)
+		details = append(details, ConflictDetail{
+			VolumeKey: key,
+			Category:  conflictCategoryNames[conflictCategoryForVolume(volume)],
+		})
+	}
+	return details
+}
+
+// ConflictDelta reports how a node change altered the pod's conflicts: added
+// holds the conflicts present on the new node state but not the old one, and
+// removed the conflicts the change resolved. It is meant for layers reasoning
+// about requeueing, which only benefits the pod when conflicts were removed,
+// and for hint correctness debugging.
+func (pl *VolumeRestrictions) ConflictDelta(pod *v1.Pod, oldNodeInfo, newNodeInfo *framework.NodeInfo) (added, removed []ConflictDetail) {
+	oldDetails := pl.conflictDetailsForNode(pod, oldNodeInfo)
+	newDetails := pl.conflictDetailsForNode(pod, newNodeInfo)
+	return diffConflictDetails(newDetails, oldDetails), diffConflictDetails(oldDetails, newDetails)
+}
+
+// diffConflictDetails returns the details in a that are not in b.
+func diffConflictDetails(a, b []ConflictDetail) []ConflictDetail {
+	var diff []ConflictDetail
+	for _, detail := range a {
+		found := false
+		for _, other := range b {
+			if detail == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, detail)
+		}
+	}
+	return diff
+}
+
+// PreemptionExplanation returns a human-readable explanation of what evicting
+// the victims from the node would free for the pod: the contended volumes and
+// ReadWriteOncePod claims each victim holds. It is meant to accompany the
+// scheduler's preemption message so users can see why those pods were chosen.
+// The returned string is empty when no victim holds anything the pod is
+// waiting on.
+func (pl *VolumeRestrictions) PreemptionExplanation(pod *v1.Pod, nodeInfo *framework.NodeInfo, victims []*v1.Pod) string {
+	var parts []string
+	for _, victim := range victims {
+		var freed []string
+		if pl.diskConflictMode != config.DiskConflictOff {
+			victimIdentities := &nodeVolumeIdentities{identities: make(map[string]*volumeIdentityEntry)}
+			victimIdentities.addPod(victim)
+			for _, detail := range pl.conflictDetailsAgainstIdentities(pod, victimIdentities, nodeInfo) {
+				freed = append(freed, fmt.Sprintf("%s volume %s", detail.Category, detail.VolumeKey))
+			}
+		}
+		for _, key := range pl.sharedReadWriteOncePodClaims(pod, victim) {
+			freed = append(freed, fmt.Sprintf("ReadWriteOncePod claim %s", key))
+		}
+		if len(freed) == 0 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("preempting pod %s frees %s",
+			framework.GetNamespacedName(victim.Namespace, victim.Name), strings.Join(freed, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// sharedReadWriteOncePodClaims returns the namespaced names of the
+// ReadWriteOncePod claims the pod references that the victim also references.
+// Claims that cannot be classified are skipped; the explanation is best
+// effort.
+func (pl *VolumeRestrictions) sharedReadWriteOncePodClaims(pod, victim *v1.Pod) []string {
+	victimClaims := sets.New[string]()
+	for i := range victim.Spec.Volumes {
+		if pvcSource := victim.Spec.Volumes[i].PersistentVolumeClaim; pvcSource != nil {
+			namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, victim.Namespace, pvcSource.ClaimName)
+			victimClaims.Insert(framework.GetNamespacedName(namespace, name))
+		}
+	}
+	if victimClaims.Len() == 0 {
+		return nil
+	}
+	var shared []string
+	seen := sets.New[string]()
+	for i := range pod.Spec.Volumes {
+		pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+		if pvcSource == nil {
+			continue
+		}
+		key, status := pl.readWriteOncePodPVC(pod, podClaimReference{claimName: pvcSource.ClaimName})
+		if !status.IsSuccess() || key == "" || !victimClaims.Has(key) || seen.Has(key) {
+			continue
+		}
+		seen.Insert(key)
+		shared = append(shared, key)
+	}
+	return shared
+}
+
+// ConflictGraph returns, as an adjacency list keyed by namespaced pod name, which
+// pods on the node conflict with which over which volumes. It is read-only, built
+// from nodeInfo.Pods with the same identity logic Filter uses, and is meant as a
+// debugging artifact for support bundles answering "why is this node saturated".
+func (pl *VolumeRestrictions) ConflictGraph(nodeInfo *framework.NodeInfo) map[string][]ConflictEdge {
+	perPodIdentities := make([]*nodeVolumeIdentities, len(nodeInfo.Pods))
+	for i, podInfo := range nodeInfo.Pods {
+		nv := &nodeVolumeIdentities{identities: make(map[string]*volumeIdentityEntry)}
+		nv.addPod(podInfo.Pod)
+		perPodIdentities[i] = nv
+	}
+
+	type pairKey struct {
+		pod, otherPod, volumeKey string
+	}
+	graph := make(map[string][]ConflictEdge)
+	seen := make(map[pairKey]bool)
+	for i, podInfo := range nodeInfo.Pods {
+		pod := podInfo.Pod
+		podName := framework.GetNamespacedName(pod.Namespace, pod.Name)
+		for j := i + 1; j < len(nodeInfo.Pods); j++ {
+			other := nodeInfo.Pods[j].Pod
+			otherName := framework.GetNamespacedName(other.Namespace, other.Name)
+			for v := range pod.Spec.Volumes {
+				volume := &pod.Spec.Volumes[v]
+				if !needsRestrictionsCheck(*volume, pl.legacyVolumeSources) {
+					continue
+				}
+				if !perPodIdentities[j].conflicts(volume) {
+					continue
+				}
+				key, _ := volumeIdentityKey(volume)
+				if seen[pairKey{podName, otherName, key}] {
+					// A pod referencing the same volume twice contributes one edge only.
+					continue
+				}
+				seen[pairKey{podName, otherName, key}] = true
+				graph[podName] = append(graph[podName], ConflictEdge{OtherPod: otherName, VolumeKey: key})
+				graph[otherName] = append(graph[otherName], ConflictEdge{OtherPod: podName, VolumeKey: key})
+			}
+		}
+	}
+	return graph
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2/ktesting"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestPodsConflict(t *testing.T) {
+	gceVolume := func(pdName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: pdName, ReadOnly: readOnly},
+			},
+		}
+	}
+	awsVolume := func(volumeID string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: volumeID},
+			},
+		}
+	}
+	iscsiVolume := func(iqn string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				ISCSI: &v1.ISCSIVolumeSource{IQN: iqn, ReadOnly: readOnly},
+			},
+		}
+	}
+	rbdVolume := func(monitors []string, pool, image string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				RBD: &v1.RBDVolumeSource{CephMonitors: monitors, RBDPool: pool, RBDImage: image},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		a, b         *v1.Pod
+		wantConflict bool
+		wantDetails  []ConflictDetail
+	}{
+		{
+			name:         "same GCE disk read-write",
+			a:            st.MakePod().Volume(gceVolume("foo", false)).Obj(),
+			b:            st.MakePod().Volume(gceVolume("foo", false)).Obj(),
+			wantConflict: true,
+			wantDetails:  []ConflictDetail{{VolumeKey: "gce://foo", Category: "GCEPersistentDisk"}},
+		},
+		{
+			name: "same GCE disk read-only",
+			a:    st.MakePod().Volume(gceVolume("foo", true)).Obj(),
+			b:    st.MakePod().Volume(gceVolume("foo", true)).Obj(),
+		},
+		{
+			name: "different GCE disks",
+			a:    st.MakePod().Volume(gceVolume("foo", false)).Obj(),
+			b:    st.MakePod().Volume(gceVolume("bar", false)).Obj(),
+		},
+		{
+			name:         "same AWS volume ID",
+			a:            st.MakePod().Volume(awsVolume("vol-1")).Obj(),
+			b:            st.MakePod().Volume(awsVolume("vol-1")).Obj(),
+			wantConflict: true,
+			wantDetails:  []ConflictDetail{{VolumeKey: "aws://vol-1", Category: "AWSElasticBlockStore"}},
+		},
+		{
+			name:         "same ISCSI IQN read-write",
+			a:            st.MakePod().Volume(iscsiVolume("iqn.2016-12.world.srv:storage.target01", false)).Obj(),
+			b:            st.MakePod().Volume(iscsiVolume("iqn.2016-12.world.srv:storage.target01", false)).Obj(),
+			wantConflict: true,
+			wantDetails:  []ConflictDetail{{VolumeKey: "iscsi://iqn.2016-12.world.srv:storage.target01", Category: "ISCSI"}},
+		},
+		{
+			name:         "RBD images sharing a monitor, pool and image",
+			a:            st.MakePod().Volume(rbdVolume([]string{"a", "b"}, "pool", "image")).Obj(),
+			b:            st.MakePod().Volume(rbdVolume([]string{"b", "c"}, "pool", "image")).Obj(),
+			wantConflict: true,
+			wantDetails:  []ConflictDetail{{VolumeKey: "rbd://a,b/pool/image", Category: "RBD"}},
+		},
+		{
+			name: "RBD images without a common monitor",
+			a:    st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image")).Obj(),
+			b:    st.MakePod().Volume(rbdVolume([]string{"b"}, "pool", "image")).Obj(),
+		},
+		{
+			name: "RBD snapshot-qualified image does not conflict with its base image",
+			a:    st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image@snap-1")).Obj(),
+			b:    st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image")).Obj(),
+		},
+		{
+			name: "RBD snapshot-qualified images with different snapshots",
+			a:    st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image@snap-1")).Obj(),
+			b:    st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image@snap-2")).Obj(),
+		},
+		{
+			name:         "RBD same snapshot-qualified image",
+			a:            st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image@snap-1")).Obj(),
+			b:            st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image@snap-1")).Obj(),
+			wantConflict: true,
+			wantDetails:  []ConflictDetail{{VolumeKey: "rbd://a/pool/image@snap-1", Category: "RBD"}},
+		},
+		{
+			name:         "RBD empty snapshot qualifier refers to the base image",
+			a:            st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image@")).Obj(),
+			b:            st.MakePod().Volume(rbdVolume([]string{"a"}, "pool", "image")).Obj(),
+			wantConflict: true,
+			wantDetails:  []ConflictDetail{{VolumeKey: "rbd://a/pool/image", Category: "RBD"}},
+		},
+		{
+			name:         "multiple conflicting volumes",
+			a:            st.MakePod().Volume(gceVolume("foo", false)).Volume(awsVolume("vol-1")).Obj(),
+			b:            st.MakePod().Volume(gceVolume("foo", false)).Volume(awsVolume("vol-1")).Obj(),
+			wantConflict: true,
+			wantDetails: []ConflictDetail{
+				{VolumeKey: "gce://foo", Category: "GCEPersistentDisk"},
+				{VolumeKey: "aws://vol-1", Category: "AWSElasticBlockStore"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotConflict, gotDetails := PodsConflict(test.a, test.b)
+			if gotConflict != test.wantConflict {
+				t.Errorf("Expected conflict: %v, got: %v", test.wantConflict, gotConflict)
+			}
+			if diff := cmp.Diff(test.wantDetails, gotDetails); diff != "" {
+				t.Errorf("Unexpected conflict details (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestConflictGraph(t *testing.T) {
+	gceVolume := func(pdName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
+					PDName:   pdName,
+					ReadOnly: readOnly,
+				},
+			},
+		}
+	}
+
+	podA := st.MakePod().Namespace("ns").Name("pod-a").Volume(gceVolume("foo", false)).Obj()
+	podB := st.MakePod().Namespace("ns").Name("pod-b").Volume(gceVolume("foo", false)).Obj()
+	podC := st.MakePod().Namespace("ns").Name("pod-c").Volume(gceVolume("bar", false)).Obj()
+	podReadOnly1 := st.MakePod().Namespace("ns").Name("pod-ro-1").Volume(gceVolume("baz", true)).Obj()
+	podReadOnly2 := st.MakePod().Namespace("ns").Name("pod-ro-2").Volume(gceVolume("baz", true)).Obj()
+
+	tests := []struct {
+		name string
+		pods []*v1.Pod
+		want map[string][]ConflictEdge
+	}{
+		{
+			name: "no pods",
+			pods: nil,
+			want: map[string][]ConflictEdge{},
+		},
+		{
+			name: "two pods sharing a read-write disk conflict, an unrelated pod does not",
+			pods: []*v1.Pod{podA, podB, podC},
+			want: map[string][]ConflictEdge{
+				"ns/pod-a": {{OtherPod: "ns/pod-b", VolumeKey: "gce://foo"}},
+				"ns/pod-b": {{OtherPod: "ns/pod-a", VolumeKey: "gce://foo"}},
+			},
+		},
+		{
+			name: "read-only sharing produces no edges",
+			pods: []*v1.Pod{podReadOnly1, podReadOnly2},
+			want: map[string][]ConflictEdge{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPlugin(ctx, t)
+
+			got := p.(*VolumeRestrictions).ConflictGraph(framework.NewNodeInfo(test.pods...))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected conflict graph (-want, +got): %s", diff)
+			}
+		})
+	}
+}
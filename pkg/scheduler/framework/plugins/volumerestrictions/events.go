@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// conflictEventReason is the reason of the events emitted on pods rejected by
+// a volume conflict when EmitConflictEvents is set.
+const conflictEventReason = "VolumeConflict"
+
+// conflictEventInterval is the minimum time between two conflict events for
+// the same pod. Filter rejects a pending pod once per node per scheduling
+// cycle, so without a limit a single pod could flood the event stream.
+const conflictEventInterval = time.Minute
+
+// conflictEventLimiter rate-limits conflict events per pod. Filter runs for
+// many nodes in parallel, so access is serialized by a mutex; the critical
+// section is a map lookup and insert.
+type conflictEventLimiter struct {
+	mu sync.Mutex
+	// lastEmitted maps a pod UID to the time its last event was emitted.
+	lastEmitted map[types.UID]time.Time
+	// now returns the current time, injectable for tests.
+	now func() time.Time
+}
+
+func newConflictEventLimiter() *conflictEventLimiter {
+	return &conflictEventLimiter{
+		lastEmitted: map[types.UID]time.Time{},
+		now:         time.Now,
+	}
+}
+
+// allow reports whether an event may be emitted for the pod, recording the
+// emission when it is. Expired entries are pruned along the way, so the map
+// stays bounded by the pods rejected within the last interval.
+func (l *conflictEventLimiter) allow(uid types.UID) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for u, emitted := range l.lastEmitted {
+		if u != uid && now.Sub(emitted) >= conflictEventInterval {
+			delete(l.lastEmitted, u)
+		}
+	}
+	if emitted, ok := l.lastEmitted[uid]; ok && now.Sub(emitted) < conflictEventInterval {
+		return false
+	}
+	l.lastEmitted[uid] = now
+	return true
+}
+
+// recordConflictEvent emits a Warning event on the pod carrying the conflict
+// reasons, when conflict events are enabled and the pod has not had one
+// recently.
+func (pl *VolumeRestrictions) recordConflictEvent(pod *v1.Pod, reasons ...string) {
+	if pl.conflictEvents == nil || !pl.conflictEvents.allow(pod.UID) {
+		return
+	}
+	pl.eventRecorder.Eventf(pod, nil, v1.EventTypeWarning, conflictEventReason, "Scheduling", "%s", strings.Join(reasons, "; "))
+}
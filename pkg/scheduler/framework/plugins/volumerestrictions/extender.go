@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// ExtenderChecker supplies the pods currently assigned to a candidate node.
+// The extender handler combines it with PodsConflict to decide feasibility.
+type ExtenderChecker interface {
+	// PodsOnNode returns the pods assigned to the named node.
+	PodsOnNode(nodeName string) ([]*v1.Pod, error)
+}
+
+// NewExtenderHandler returns an http.Handler implementing the scheduler
+// extender filter API on top of the inline volume conflict logic, so external
+// schedulers can reuse it without linking the plugin. It accepts a POSTed
+// extenderv1.ExtenderArgs and answers with an extenderv1.ExtenderFilterResult
+// holding the nodes where the pod's volumes conflict with no running pod.
+func NewExtenderHandler(checker ExtenderChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var args extenderv1.ExtenderArgs
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			writeExtenderResult(w, &extenderv1.ExtenderFilterResult{Error: fmt.Sprintf("decoding extender args: %v", err)})
+			return
+		}
+		if args.Pod == nil {
+			writeExtenderResult(w, &extenderv1.ExtenderFilterResult{Error: "extender args have no pod"})
+			return
+		}
+		writeExtenderResult(w, filterNodes(checker, &args))
+	})
+}
+
+// filterNodes evaluates the candidate nodes in args and partitions them into
+// feasible nodes and failed nodes, mirroring the shape of the request: node
+// names in, node names out; node objects in, node objects out.
+func filterNodes(checker ExtenderChecker, args *extenderv1.ExtenderArgs) *extenderv1.ExtenderFilterResult {
+	result := &extenderv1.ExtenderFilterResult{FailedNodes: extenderv1.FailedNodesMap{}}
+	feasible := func(nodeName string) bool {
+		pods, err := checker.PodsOnNode(nodeName)
+		if err != nil {
+			result.FailedNodes[nodeName] = fmt.Sprintf("listing pods on node: %v", err)
+			return false
+		}
+		for _, pod := range pods {
+			if conflict, details := PodsConflict(args.Pod, pod); conflict {
+				result.FailedNodes[nodeName] = fmt.Sprintf("conflicts with pod %s on volume %s",
+					framework.GetNamespacedName(pod.Namespace, pod.Name), details[0].VolumeKey)
+				return false
+			}
+		}
+		return true
+	}
+
+	if args.NodeNames != nil {
+		nodeNames := []string{}
+		for _, nodeName := range *args.NodeNames {
+			if feasible(nodeName) {
+				nodeNames = append(nodeNames, nodeName)
+			}
+		}
+		result.NodeNames = &nodeNames
+		return result
+	}
+	result.Nodes = &v1.NodeList{}
+	if args.Nodes != nil {
+		for _, node := range args.Nodes.Items {
+			if feasible(node.Name) {
+				result.Nodes.Items = append(result.Nodes.Items, node)
+			}
+		}
+	}
+	return result
+}
+
+func writeExtenderResult(w http.ResponseWriter, result *extenderv1.ExtenderFilterResult) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
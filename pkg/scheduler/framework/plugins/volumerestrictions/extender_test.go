@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+type fakeExtenderChecker struct {
+	podsByNode map[string][]*v1.Pod
+}
+
+func (f *fakeExtenderChecker) PodsOnNode(nodeName string) ([]*v1.Pod, error) {
+	return f.podsByNode[nodeName], nil
+}
+
+func TestExtenderHandler(t *testing.T) {
+	gceVolume := func(pdName string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: pdName},
+			},
+		}
+	}
+	pod := st.MakePod().Namespace("ns").Name("pod").Volume(gceVolume("foo")).Obj()
+	conflictingPod := st.MakePod().Namespace("ns").Name("existing").Volume(gceVolume("foo")).Obj()
+	unrelatedPod := st.MakePod().Namespace("ns").Name("unrelated").Volume(gceVolume("bar")).Obj()
+	checker := &fakeExtenderChecker{
+		podsByNode: map[string][]*v1.Pod{
+			"node-busy": {conflictingPod},
+			"node-free": {unrelatedPod},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		args            extenderv1.ExtenderArgs
+		wantNodeNames   *[]string
+		wantNodes       []string
+		wantFailedNodes extenderv1.FailedNodesMap
+	}{
+		{
+			name: "node names: conflicting node filtered out",
+			args: extenderv1.ExtenderArgs{
+				Pod:       pod,
+				NodeNames: &[]string{"node-busy", "node-free", "node-empty"},
+			},
+			wantNodeNames: &[]string{"node-free", "node-empty"},
+			wantFailedNodes: extenderv1.FailedNodesMap{
+				"node-busy": `conflicts with pod ns/existing on volume gce://foo`,
+			},
+		},
+		{
+			name: "node objects: conflicting node filtered out",
+			args: extenderv1.ExtenderArgs{
+				Pod: pod,
+				Nodes: &v1.NodeList{Items: []v1.Node{
+					{ObjectMeta: metav1.ObjectMeta{Name: "node-busy"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "node-free"}},
+				}},
+			},
+			wantNodes: []string{"node-free"},
+			wantFailedNodes: extenderv1.FailedNodesMap{
+				"node-busy": `conflicts with pod ns/existing on volume gce://foo`,
+			},
+		},
+		{
+			name: "no conflicts keeps all nodes",
+			args: extenderv1.ExtenderArgs{
+				Pod:       st.MakePod().Namespace("ns").Name("pod").Volume(gceVolume("baz")).Obj(),
+				NodeNames: &[]string{"node-busy", "node-free"},
+			},
+			wantNodeNames:   &[]string{"node-busy", "node-free"},
+			wantFailedNodes: extenderv1.FailedNodesMap{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := json.Marshal(test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest("POST", "/filter", bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			NewExtenderHandler(checker).ServeHTTP(rec, req)
+
+			var result extenderv1.ExtenderFilterResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Error != "" {
+				t.Fatalf("Unexpected extender error: %s", result.Error)
+			}
+			if diff := cmp.Diff(test.wantNodeNames, result.NodeNames); diff != "" {
+				t.Errorf("Unexpected node names (-want, +got): %s", diff)
+			}
+			var gotNodes []string
+			if result.Nodes != nil {
+				for _, node := range result.Nodes.Items {
+					gotNodes = append(gotNodes, node.Name)
+				}
+			}
+			if diff := cmp.Diff(test.wantNodes, gotNodes); diff != "" {
+				t.Errorf("Unexpected nodes (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantFailedNodes, result.FailedNodes); diff != "" {
+				t.Errorf("Unexpected failed nodes (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestExtenderHandlerBadRequest(t *testing.T) {
+	req := httptest.NewRequest("POST", "/filter", strings.NewReader("not JSON"))
+	rec := httptest.NewRecorder()
+	NewExtenderHandler(&fakeExtenderChecker{}).ServeHTTP(rec, req)
+
+	var result extenderv1.ExtenderFilterResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == "" {
+		t.Error("Expected a decoding error in the extender result, got none")
+	}
+}
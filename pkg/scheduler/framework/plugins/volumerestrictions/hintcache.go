@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hintEventCache shares resolved claim data between the QueueingHint
+// evaluations a single cluster event triggers. When a widely referenced claim
+// or volume changes, every waiting pod runs its hint function, and pods
+// referencing the same claims would otherwise repeat the same lister lookups.
+// The scheduling queue evaluates all waiting pods for one event before moving
+// to the next, so the cache holds the entries of one event at a time; an
+// evaluation for a different event drops them.
+type hintEventCache struct {
+	mu       sync.Mutex
+	eventKey string
+	entries  map[string]string
+}
+
+func newHintEventCache() *hintEventCache {
+	return &hintEventCache{entries: map[string]string{}}
+}
+
+// eventKeyForObject identifies the event by the object it carries; a changed
+// object, even of the same resource, is a different event and does not reuse
+// the previous event's entries.
+func eventKeyForObject(obj metav1.Object) string {
+	return string(obj.GetUID()) + "/" + obj.GetResourceVersion()
+}
+
+// lookup returns the cached value of itemKey for the event, resolving and
+// recording it on the first evaluation.
+func (c *hintEventCache) lookup(eventKey, itemKey string, resolve func() string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.eventKey != eventKey {
+		c.eventKey = eventKey
+		c.entries = map[string]string{}
+	}
+	if value, ok := c.entries[itemKey]; ok {
+		return value
+	}
+	value := resolve()
+	c.entries[itemKey] = value
+	return value
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// VolumeRestrictionsSubsystem - subsystem name used by the VolumeRestrictions plugin.
+const VolumeRestrictionsSubsystem = "scheduler_volume_restrictions"
+
+var (
+	// DiskConflictsAudited tracks the number of pod-node pairs that had an inline
+	// disk volume conflict but were not rejected because DiskConflictMode is Warn.
+	DiskConflictsAudited = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      VolumeRestrictionsSubsystem,
+			Name:           "disk_conflicts_audited_total",
+			Help:           "Number of pod-node pairs with an inline disk volume conflict that were allowed because DiskConflictMode is Warn",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// PVCListerStaleChecks tracks the number of health checks that found the
+	// PersistentVolumeClaim informer out of sync beyond the check interval.
+	PVCListerStaleChecks = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      VolumeRestrictionsSubsystem,
+			Name:           "pvc_lister_stale_checks_total",
+			Help:           "Number of health checks that found the PersistentVolumeClaim informer out of sync beyond the check interval",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// ConflictRejections tracks the number of pod-node pairs Filter rejected
+	// due to a volume conflict, labeled by the conflicting volume source kind.
+	ConflictRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      VolumeRestrictionsSubsystem,
+			Name:           "conflict_rejections_total",
+			Help:           "Number of pod-node pairs rejected by Filter due to a volume conflict, partitioned by the conflicting volume source kind (GCEPersistentDisk, AWSElasticBlockStore, ISCSI, RBD, FibreChannel or ReadWriteOncePod)",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"volume_source"},
+	)
+
+	// ReadWriteOncePodPVCsInUse tracks the number of distinct ReadWriteOncePod
+	// PersistentVolumeClaims currently held by scheduled pods.
+	ReadWriteOncePodPVCsInUse = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      VolumeRestrictionsSubsystem,
+			Name:           "read_write_once_pod_pvcs_in_use",
+			Help:           "Number of distinct ReadWriteOncePod PersistentVolumeClaims currently held by scheduled pods",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// PodsWaitingOnReadWriteOncePodPVCs tracks the number of pods whose last
+	// scheduling attempt found one of their ReadWriteOncePod
+	// PersistentVolumeClaims held by another pod.
+	PodsWaitingOnReadWriteOncePodPVCs = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      VolumeRestrictionsSubsystem,
+			Name:           "pods_waiting_on_read_write_once_pod_pvcs",
+			Help:           "Number of pods whose last scheduling attempt found one of their ReadWriteOncePod PersistentVolumeClaims held by another pod",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+)
+
+// RegisterVolumeRestrictionsMetrics registers the metrics of the VolumeRestrictions
+// plugin with the scheduler's metrics registry.
+func RegisterVolumeRestrictionsMetrics() {
+	legacyregistry.MustRegister(DiskConflictsAudited)
+	legacyregistry.MustRegister(ConflictRejections)
+	legacyregistry.MustRegister(PVCListerStaleChecks)
+	legacyregistry.MustRegister(ReadWriteOncePodPVCsInUse)
+	legacyregistry.MustRegister(PodsWaitingOnReadWriteOncePodPVCs)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// RejectionRecord captures a single Filter rejection for post-incident inspection.
+type RejectionRecord struct {
+	// Pod is the namespaced name of the rejected pod.
+	Pod string
+	// Node is the name of the node the pod was rejected from.
+	Node string
+	// Reason is the unschedulable status message the rejection produced.
+	Reason string
+}
+
+// rejectionRecorder retains the most recent Filter rejections in a bounded ring
+// buffer. Filter runs for many nodes in parallel, so access is serialized by a
+// mutex; the critical sections are a copy and two index updates.
+type rejectionRecorder struct {
+	mu sync.Mutex
+	// records is the ring buffer backing array, filled from index zero.
+	records []RejectionRecord
+	// next is the index the next record is written to.
+	next int
+	// full is true once the buffer has wrapped around at least once.
+	full bool
+}
+
+// newRejectionRecorder returns a recorder retaining the last size rejections,
+// or nil if size is zero, which disables recording.
+func newRejectionRecorder(size int32) *rejectionRecorder {
+	if size <= 0 {
+		return nil
+	}
+	return &rejectionRecorder{records: make([]RejectionRecord, size)}
+}
+
+// record appends a rejection, evicting the oldest one once the buffer is full.
+func (r *rejectionRecorder) record(rec RejectionRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[r.next] = rec
+	r.next++
+	if r.next == len(r.records) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// recent returns a copy of the retained rejections, oldest first.
+func (r *rejectionRecorder) recent() []RejectionRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]RejectionRecord{}, r.records[:r.next]...)
+	}
+	recent := make([]RejectionRecord, 0, len(r.records))
+	recent = append(recent, r.records[r.next:]...)
+	recent = append(recent, r.records[:r.next]...)
+	return recent
+}
+
+// recordRejection retains a Filter rejection of the pod from the node when
+// rejection recording is enabled.
+func (pl *VolumeRestrictions) recordRejection(pod *v1.Pod, nodeInfo *framework.NodeInfo, reason string) {
+	if pl.rejections == nil {
+		return
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return
+	}
+	pl.rejections.record(RejectionRecord{
+		Pod:    framework.GetNamespacedName(pod.Namespace, pod.Name),
+		Node:   node.Name,
+		Reason: reason,
+	})
+}
+
+// RecentRejections returns the retained Filter rejections, oldest first, for
+// post-incident inspection. It returns nil when rejection recording is disabled.
+func (pl *VolumeRestrictions) RecentRejections() []RejectionRecord {
+	if pl.rejections == nil {
+		return nil
+	}
+	return pl.rejections.recent()
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/ktesting"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+)
+
+func TestRecentRejections(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	pod := st.MakePod().Namespace("ns").Name("pod").Volume(gceVolume).Obj()
+	existingPod := st.MakePod().Namespace("ns").Name("existing-pod").Volume(gceVolume).Obj()
+	newNodeInfo := func(name string) *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(existingPod)
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		return nodeInfo
+	}
+
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	args := &config.VolumeRestrictionsArgs{
+		DiskConflictMode:       config.DiskConflictEnforce,
+		UnknownCSIDriverPolicy: config.UnknownCSIDriverStrict,
+		RejectionHistoryLength: 2,
+	}
+	p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+
+	cycleState := framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	for _, nodeName := range []string{"node-a", "node-b", "node-c"} {
+		if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, newNodeInfo(nodeName)); status.Code() != framework.Unschedulable {
+			t.Fatalf("Expected Unschedulable, got: %v", status)
+		}
+	}
+
+	// The buffer retains the last two rejections only, oldest first.
+	wantRecords := []RejectionRecord{
+		{Pod: "ns/pod", Node: "node-b", Reason: ErrReasonDiskConflict},
+		{Pod: "ns/pod", Node: "node-c", Reason: ErrReasonDiskConflict},
+	}
+	gotRecords := p.(*VolumeRestrictions).RecentRejections()
+	if diff := cmp.Diff(wantRecords, gotRecords); diff != "" {
+		t.Errorf("Unexpected rejection records (-want, +got): %s", diff)
+	}
+
+	// With recording disabled no records are retained.
+	p = newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), nil, nil, nil, nil)
+	cycleState = framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, newNodeInfo("node-a")); status.Code() != framework.Unschedulable {
+		t.Fatalf("Expected Unschedulable, got: %v", status)
+	}
+	if gotRecords := p.(*VolumeRestrictions).RecentRejections(); gotRecords != nil {
+		t.Errorf("Expected nil rejection records, got: %v", gotRecords)
+	}
+}
+
+func TestRejectionRecorderConcurrency(t *testing.T) {
+	const (
+		size       = 8
+		goroutines = 4
+		records    = 100
+	)
+	recorder := newRejectionRecorder(size)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < records; j++ {
+				recorder.record(RejectionRecord{
+					Pod:    fmt.Sprintf("ns/pod-%d-%d", i, j),
+					Node:   "node-a",
+					Reason: ErrReasonDiskConflict,
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := recorder.recent(); len(got) != size {
+		t.Errorf("Expected %d retained records, got %d", size, len(got))
+	}
+}
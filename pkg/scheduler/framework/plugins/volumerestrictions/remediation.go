@@ -0,0 +1,55 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"fmt"
+)
+
+// Reason is one of the plugin's rejection reasons, i.e. one of the ErrReason
+// constants.
+type Reason string
+
+// RemediationFor translates a rejection reason into actionable advice suitable
+// for scheduler events and support tooling. detail, when available, names the
+// conflicting volume the advice refers to; a zero ConflictDetail yields generic
+// advice. An unknown reason yields the empty string.
+func RemediationFor(reason Reason, detail ConflictDetail) string {
+	volume := "the conflicting volume"
+	if detail.VolumeKey != "" {
+		volume = fmt.Sprintf("volume %s", detail.VolumeKey)
+	}
+	switch string(reason) {
+	case ErrReasonDiskConflict:
+		return fmt.Sprintf("delete or reschedule a pod holding %s, or make all mounts of it read-only if the volume type supports concurrent readers", volume)
+	case ErrReasonReadWriteOncePodConflict:
+		return "delete or reschedule the pod holding the ReadWriteOncePod PersistentVolumeClaim, or change the claim's access mode if concurrent writers are acceptable"
+	case ErrReasonPVNodeAffinityConflict:
+		return "add schedulable nodes satisfying the node affinity of the pod's bound PersistentVolumes, or reprovision the volumes in a zone with schedulable nodes"
+	case ErrReasonVolumeModeConflict:
+		return fmt.Sprintf("consume %s with the same volume mode as the pod already using it, or move one of the pods to another volume", volume)
+	case ErrReasonBlockVolumeConflict:
+		return fmt.Sprintf("mark all but one mount of %s read-only, or move one of the pods to another volume", volume)
+	case ErrReasonEBSVolumeAttachedElsewhere:
+		return fmt.Sprintf("delete or reschedule the pod using %s on the node it is attached to, or wait for the volume to detach", volume)
+	case ErrReasonEBSVolumeAttachedToUnavailableNode:
+		return fmt.Sprintf("bring the node holding %s back to a ready and schedulable state, or detach the volume from it", volume)
+	case ErrReasonPVCMissingAccessModes:
+		return "set spec.accessModes on the referenced PersistentVolumeClaim"
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"testing"
+)
+
+func TestRemediationFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		reason Reason
+		detail ConflictDetail
+		want   string
+	}{
+		{
+			name:   "disk conflict with volume detail",
+			reason: Reason(ErrReasonDiskConflict),
+			detail: ConflictDetail{VolumeKey: "gce://disk-1", Category: "GCEPersistentDisk"},
+			want:   "delete or reschedule a pod holding volume gce://disk-1, or make all mounts of it read-only if the volume type supports concurrent readers",
+		},
+		{
+			name:   "disk conflict without detail",
+			reason: Reason(ErrReasonDiskConflict),
+			want:   "delete or reschedule a pod holding the conflicting volume, or make all mounts of it read-only if the volume type supports concurrent readers",
+		},
+		{
+			name:   "read write once pod conflict",
+			reason: Reason(ErrReasonReadWriteOncePodConflict),
+			want:   "delete or reschedule the pod holding the ReadWriteOncePod PersistentVolumeClaim, or change the claim's access mode if concurrent writers are acceptable",
+		},
+		{
+			name:   "pv node affinity conflict",
+			reason: Reason(ErrReasonPVNodeAffinityConflict),
+			want:   "add schedulable nodes satisfying the node affinity of the pod's bound PersistentVolumes, or reprovision the volumes in a zone with schedulable nodes",
+		},
+		{
+			name:   "volume mode conflict",
+			reason: Reason(ErrReasonVolumeModeConflict),
+			detail: ConflictDetail{VolumeKey: "pv://pv-1"},
+			want:   "consume volume pv://pv-1 with the same volume mode as the pod already using it, or move one of the pods to another volume",
+		},
+		{
+			name:   "block volume conflict",
+			reason: Reason(ErrReasonBlockVolumeConflict),
+			detail: ConflictDetail{VolumeKey: "pv://pv-1"},
+			want:   "mark all but one mount of volume pv://pv-1 read-only, or move one of the pods to another volume",
+		},
+		{
+			name:   "ebs volume attached elsewhere",
+			reason: Reason(ErrReasonEBSVolumeAttachedElsewhere),
+			detail: ConflictDetail{VolumeKey: "aws://vol-1"},
+			want:   "delete or reschedule the pod using volume aws://vol-1 on the node it is attached to, or wait for the volume to detach",
+		},
+		{
+			name:   "ebs volume attached to unavailable node",
+			reason: Reason(ErrReasonEBSVolumeAttachedToUnavailableNode),
+			detail: ConflictDetail{VolumeKey: "aws://vol-1"},
+			want:   "bring the node holding volume aws://vol-1 back to a ready and schedulable state, or detach the volume from it",
+		},
+		{
+			name:   "pvc missing access modes",
+			reason: Reason(ErrReasonPVCMissingAccessModes),
+			want:   "set spec.accessModes on the referenced PersistentVolumeClaim",
+		},
+		{
+			name:   "unknown reason",
+			reason: Reason("some other reason"),
+			want:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := RemediationFor(test.reason, test.detail); got != test.want {
+				t.Errorf("RemediationFor(%q) = %q, want %q", test.reason, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumerestrictions
+
+import (
+	"sync"
+)
+
+// rwopClaimInfo is the cached ReadWriteOncePod classification of one claim.
+type rwopClaimInfo struct {
+	// hasReadWriteOncePod is true if the claim's access modes include
+	// ReadWriteOncePod.
+	hasReadWriteOncePod bool
+	// missingAccessModes is true if the claim has no access modes at all.
+	missingAccessModes bool
+}
+
+// readWriteOncePodCache caches per-claim ReadWriteOncePod classifications
+// across scheduling cycles, keyed by namespaced claim name. Access modes on a
+// bound claim are effectively immutable, so the cache spares one lister
+// lookup per claim per scheduling cycle under pod churn; entries are still
+// invalidated on claim update and delete events so a cached classification
+// never outlives the object it describes. Those events arrive through the
+// plugin's PersistentVolumeClaim queueing hint, so the cache must not be used
+// when the SchedulingQueueHints feature gate is disabled.
+type readWriteOncePodCache struct {
+	mu     sync.RWMutex
+	claims map[string]rwopClaimInfo
+}
+
+func newReadWriteOncePodCache() *readWriteOncePodCache {
+	return &readWriteOncePodCache{claims: make(map[string]rwopClaimInfo)}
+}
+
+// get returns the cached classification of the claim, if any.
+func (c *readWriteOncePodCache) get(key string) (rwopClaimInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info, ok := c.claims[key]
+	return info, ok
+}
+
+// set records the classification of the claim.
+func (c *readWriteOncePodCache) set(key string, info rwopClaimInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claims[key] = info
+}
+
+// invalidate drops the cached classification of the claim.
+func (c *readWriteOncePodCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.claims, key)
+}
@@ -19,29 +19,180 @@ package volumerestrictions
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/events"
+	volumehelpers "k8s.io/cloud-provider/volume/helpers"
+	ephemeral "k8s.io/component-helpers/storage/ephemeral"
+	storagehelpers "k8s.io/component-helpers/storage/volume"
 	"k8s.io/klog/v2"
 	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/parallelize"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/volumerestrictions/metrics"
 	"k8s.io/kubernetes/pkg/scheduler/util"
+	"k8s.io/utils/ptr"
 )
 
 // VolumeRestrictions is a plugin that checks volume restrictions.
 type VolumeRestrictions struct {
-	pvcLister                 corelisters.PersistentVolumeClaimLister
-	sharedLister              framework.SharedLister
+	pvcLister corelisters.PersistentVolumeClaimLister
+	pvLister  corelisters.PersistentVolumeLister
+	// podLister verifies that the pods the snapshot shows holding a
+	// ReadWriteOncePod claim still exist, when verifyReadWriteOncePodHolders
+	// is set, and feeds the periodic in-use gauge refresh.
+	podLister                       corelisters.PodLister
+	sharedLister                    framework.SharedLister
+	diskConflictMode                config.DiskConflictMode
+	enableConflictReasonAggregation bool
+	failOnMissingAccessModes        bool
+	// readWriteOncePodSelector limits ReadWriteOncePod enforcement to claims
+	// matching the selector. Nil enforces on all claims.
+	readWriteOncePodSelector labels.Selector
+	// allowSharedWritersAnnotation is the annotation key that opts a claim out
+	// of single-writer enforcement when set to "true". Empty disables the
+	// allowance.
+	allowSharedWritersAnnotation string
+	// unknownCSIDriverPolicy controls whether CSI volumes whose driver has no
+	// CSIDriver object are treated as single-attach or skipped.
+	unknownCSIDriverPolicy config.UnknownCSIDriverPolicy
+	// strictVolumeIdentity rejects pods whose restriction-relevant volumes
+	// leave identity fields empty instead of silently never conflicting.
+	strictVolumeIdentity bool
+	// ignoreTerminatingPods excludes pods that are already terminating from
+	// disk conflict checking, scheduling the incoming pod optimistically.
+	ignoreTerminatingPods bool
+	// ignoreMirrorPods excludes mirror pods from disk conflict checking; they
+	// represent node-level components whose volumes are not managed through
+	// scheduling.
+	ignoreMirrorPods bool
+	// verifyReadWriteOncePodHolders checks that a ReadWriteOncePod claim
+	// reported in use is still held by an existing pod before counting it,
+	// so a stale usage report does not park waiting pods indefinitely.
+	verifyReadWriteOncePodHolders bool
+	// enforceReadWriteOncePerNode restricts a bound ReadWriteOnce claim in use
+	// by a pod on some node to that node, opt-in per the access mode's formal
+	// one-node semantics.
+	enforceReadWriteOncePerNode bool
+	csiDriverLister             storagelisters.CSIDriverLister
+	// rejections retains recent Filter rejections for post-incident inspection.
+	// Nil unless RejectionHistoryLength is set.
+	rejections *rejectionRecorder
+	// conflictStatusCodes holds the status code Filter returns per conflict
+	// category. UnschedulableAndUnresolvable excludes the node from preemption.
+	conflictStatusCodes       [numConflictCategories]framework.Code
+	parallelizer              parallelize.Parallelizer
 	enableSchedulingQueueHint bool
+	// enableCrossNamespaceVolumeReferences resolves claim references of the
+	// form "namespace/name" against the named namespace instead of the pod's.
+	enableCrossNamespaceVolumeReferences bool
+	// warmNodeVolumes holds per-node volume identity indexes prebuilt by
+	// Prewarm. The first scheduling cycle checking disk conflicts consumes
+	// them; nil before Prewarm and afterwards.
+	warmNodeVolumes     map[string]*nodeVolumeIdentities
+	warmNodeVolumesLock sync.Mutex
+	// contendingPods tracks the pods whose last scheduling attempt found one
+	// of their ReadWriteOncePod claims held by another pod, backing the
+	// PodsWaitingOnReadWriteOncePodPVCs gauge.
+	contendingPods     sets.Set[string]
+	contendingPodsLock sync.Mutex
+	// conflictPolicy, when non-nil, is consulted before the built-in disk
+	// conflict rules and can override their decision per volume.
+	conflictPolicy ConflictPolicy
+	// scoreWeight scales the volume-locality scores. Zero disables the Score
+	// extension.
+	scoreWeight int32
+	// enabledConflictCategories gates the disk conflict checks per volume
+	// category, derived from EnabledDiskConflictCategories. ReadWriteOncePod
+	// enforcement is not gated by it.
+	enabledConflictCategories [numConflictCategories]bool
+	// multiWriterCategories marks volume categories the operator declared
+	// safely shared by multiple writers through MultiWriterVolumeSources;
+	// volumes of a marked category are never considered conflicting.
+	multiWriterCategories [numConflictCategories]bool
+	// legacyVolumeSources extends disk conflict checking to the legacy ScaleIO
+	// and StorageOS volume sources, which take no part in the checks otherwise.
+	legacyVolumeSources bool
+	// exemptVolumeIdentities holds the identity keys from
+	// ExemptVolumeIdentities; volumes resolving to one of them skip the disk
+	// conflict checks entirely.
+	exemptVolumeIdentities sets.Set[string]
+	// flexVolumeIdentityKeys are the FlexVolume option keys consulted for the
+	// device identity during conflict checking; empty falls back to
+	// defaultFlexVolumeIdentityKeys.
+	flexVolumeIdentityKeys []string
+	// assumedPodLister, when the handle provides one, lists the pods the
+	// scheduler has assumed onto nodes but not yet bound, so their
+	// ReadWriteOncePod claims count as held before the snapshot reflects
+	// them. Nil when the handle doesn't implement AssumedPodLister.
+	assumedPodLister AssumedPodLister
+	// eventRecorder emits conflict events on rejected pods. Only set when
+	// conflictEvents is.
+	eventRecorder events.EventRecorder
+	// conflictEvents rate-limits the conflict events per pod. Nil unless
+	// EmitConflictEvents is set and the handle has an event recorder.
+	conflictEvents *conflictEventLimiter
+	// rwopCache caches per-claim ReadWriteOncePod classifications across
+	// scheduling cycles, invalidated on claim update and delete events. Nil
+	// when SchedulingQueueHints is disabled, since those events only reach the
+	// plugin through the queueing hints.
+	rwopCache *readWriteOncePodCache
+	// hintCache shares resolved claim data between the QueueingHint
+	// evaluations a single cluster event triggers across many waiting pods.
+	hintCache *hintEventCache
+}
+
+// ConflictPolicyDecision is the verdict of a ConflictPolicy for one volume.
+type ConflictPolicyDecision int
+
+const (
+	// ConflictPolicyDefer leaves the decision to the built-in conflict rules.
+	// It is the zero value, so a policy without an opinion defers by default.
+	ConflictPolicyDefer ConflictPolicyDecision = iota
+	// ConflictPolicyAllow accepts the volume on the node regardless of the
+	// built-in conflict rules.
+	ConflictPolicyAllow
+	// ConflictPolicyDeny rejects the volume on the node regardless of the
+	// built-in conflict rules.
+	ConflictPolicyDeny
+)
+
+// ConflictPolicy decides whether a restriction-relevant volume of the pod may
+// be placed on the node, overriding the built-in conflict rules. It is
+// consulted once per volume during Filter and must be thread-safe and fast,
+// as Filter may run for many nodes in parallel.
+type ConflictPolicy func(pod *v1.Pod, volume *v1.Volume, nodeInfo *framework.NodeInfo) ConflictPolicyDecision
+
+// AssumedPodLister lists the pods the scheduler has assumed onto nodes but not
+// yet bound. The snapshot's storage infos only reflect bound pods, so two pods
+// contending for the same ReadWriteOncePod claim could otherwise both pass
+// PreFilter while the first one's binding is in flight. A handle implementing
+// this interface opts the plugin into counting assumed pods' claims as held.
+type AssumedPodLister interface {
+	AssumedPods() []*v1.Pod
 }
 
 var _ framework.PreFilterPlugin = &VolumeRestrictions{}
 var _ framework.FilterPlugin = &VolumeRestrictions{}
+var _ framework.ScorePlugin = &VolumeRestrictions{}
 var _ framework.EnqueueExtensions = &VolumeRestrictions{}
 var _ framework.StateData = &preFilterState{}
 
@@ -56,29 +207,353 @@ const (
 	ErrReasonDiskConflict = "node(s) had no available disk"
 	// ErrReasonReadWriteOncePodConflict is used when a pod is found using the same PVC with the ReadWriteOncePod access mode.
 	ErrReasonReadWriteOncePodConflict = "node has pod using PersistentVolumeClaim with the same name and ReadWriteOncePod access mode"
+	// ErrReasonPVNodeAffinityConflict is used when no node can satisfy the node affinity
+	// of all PersistentVolumes bound to the pod's claims.
+	ErrReasonPVNodeAffinityConflict = "no node(s) satisfy the node affinity of all the pod's bound PersistentVolumes"
+	// ErrReasonVolumeModeConflict is used when a pod consumes a PersistentVolume with a
+	// volume mode different from that of a pod already on the node.
+	ErrReasonVolumeModeConflict = "node has a pod using the same PersistentVolume with a conflicting volume mode"
+	// ErrReasonBlockVolumeConflict is used when two pods would consume the same
+	// block-mode PersistentVolume and not all mounts are read-only.
+	ErrReasonBlockVolumeConflict = "node has a pod using the same block-mode PersistentVolume"
+	// ErrReasonCSIBlockVolumeConflict is used when two pods would block-map the
+	// same CSI volume handle and not all mappings are read-only.
+	ErrReasonCSIBlockVolumeConflict = "node has a pod block-mapping the same CSI volume"
+	// ErrReasonEBSVolumeAttachedElsewhere is used when an AWS EBS volume the pod
+	// mounts is attached to a node the pod cannot otherwise run on. EBS volumes
+	// are single-attach cluster-wide.
+	ErrReasonEBSVolumeAttachedElsewhere = "an AWS EBS volume used by the pod is attached to a node the pod cannot run on"
+	// ErrReasonEBSVolumeAttachedToUnavailableNode is used when an AWS EBS volume
+	// the pod mounts is stuck attached to an unavailable node.
+	ErrReasonEBSVolumeAttachedToUnavailableNode = "an AWS EBS volume used by the pod is attached to an unavailable node"
+	// ErrReasonGCEPDZoneConflict is used when no node is in the zones of a GCE
+	// PersistentDisk bound to the pod's claims. A zonal disk attaches in one
+	// zone, a regional disk in either of its two zones.
+	ErrReasonGCEPDZoneConflict = "no node(s) are in the zones of the pod's bound GCE PersistentDisks"
+	// ErrReasonPVCMissingAccessModes is used when the pod references a PersistentVolumeClaim
+	// with empty access modes and FailOnMissingAccessModes is enabled.
+	ErrReasonPVCMissingAccessModes = "the pod references a PersistentVolumeClaim with no access modes"
+	// ErrReasonAttachLayerConflict supplements ErrReasonDiskConflict when the
+	// conflict is at the attach layer: the device is single-attach, so no
+	// second pod can use it regardless of access mode.
+	ErrReasonAttachLayerConflict = "the volume conflicts at the attach layer: the disk is single-attach"
+	// ErrReasonMountLayerConflict supplements ErrReasonDiskConflict when the
+	// conflict is at the mount layer: the volume could be shared read-only,
+	// but not every mount of it is.
+	ErrReasonMountLayerConflict = "the volume conflicts at the mount layer: not every mount of the volume is read-only"
+	// ErrReasonEmptyVolumeIdentity is used when the pod has a restriction-relevant
+	// volume with empty identity fields and StrictVolumeIdentity is enabled.
+	ErrReasonEmptyVolumeIdentity = "the pod has a volume with empty identity fields"
+	// ErrReasonReadWriteOnceNodeConflict is used when the pod references a
+	// ReadWriteOnce PersistentVolumeClaim already in use on another node and
+	// EnforceReadWriteOncePerNode is enabled.
+	ErrReasonReadWriteOnceNodeConflict = "a ReadWriteOnce PersistentVolumeClaim used by the pod is in use by a pod on another node"
+
+	// parallelPVCLookupThreshold is the number of claims a pod must reference
+	// before PreFilter parallelizes the lister lookups. Pods below it stay
+	// sequential since the goroutine overhead outweighs the lookup cost.
+	parallelPVCLookupThreshold = 8
 )
 
 // preFilterState computed at PreFilter and used at Filter.
 type preFilterState struct {
-	// Names of the pod's volumes using the ReadWriteOncePod access mode.
+	// Namespaced names of the claims the pod references with the
+	// ReadWriteOncePod access mode.
 	readWriteOncePodPVCs sets.Set[string]
+	// crossNamespaceRefs mirrors the plugin's cross-namespace volume reference
+	// gate so that state methods resolve claim references the same way.
+	crossNamespaceRefs bool
+	// ignoreTerminatingPods mirrors the plugin's terminating pod gate so that
+	// state methods build node volume identities the same way.
+	ignoreTerminatingPods bool
+	// ignoreMirrorPods mirrors the plugin's mirror pod gate likewise.
+	ignoreMirrorPods bool
+	// flexIdentityKeys mirrors the plugin's FlexVolume identity keys likewise.
+	flexIdentityKeys []string
 	// The number of references to these ReadWriteOncePod volumes by scheduled pods.
 	conflictingPVCRefCount int
+	// The node on which each bound ReadWriteOnce claim the pod references is
+	// already in use, keyed by namespaced claim name. Filter restricts the pod
+	// to those nodes. Nil unless EnforceReadWriteOncePerNode is set; immutable
+	// after PreFilter and therefore shared by state clones.
+	readWriteOncePVCNodes map[string]string
+	// The distinct restriction-relevant volume identities per node, built lazily
+	// while simulating pod additions and removals so that repeated conflict checks
+	// against the same node compare distinct identities only.
+	nodeVolumes map[string]*nodeVolumeIdentities
+	// sharedNodeVolumes memoizes the volume identity indexes Filter builds for
+	// nodes untouched by pod simulation, so repeated evaluations of a dense node
+	// don't rebuild them. Filter runs for many nodes in parallel with a shared
+	// state, hence a sync.Map, shared by state clones. Entries in nodeVolumes
+	// take precedence since they reflect simulated pod changes.
+	sharedNodeVolumes *sync.Map
+	// How the pod consumes each PersistentVolume bound to its claims, keyed by PV
+	// name. Immutable after PreFilter and therefore shared by state clones.
+	boundPVModes map[string]pvModeUsage
+	// The raw block mappings of CSI-backed PersistentVolumes among the pod's
+	// bound claims. Immutable after PreFilter and therefore shared by state
+	// clones.
+	csiBlockVolumes []csiBlockUsage
+	// Counts the nodes the pod failed the Filter on, per conflict category.
+	// Nil unless conflict reason aggregation is enabled.
+	conflictReasons *conflictReasonAggregator
+	// The ResourceVersion of each claim the pod references, keyed by namespaced
+	// claim name, as observed at PreFilter. Filter compares them against the
+	// lister to detect claims mutated mid-cycle.
+	pvcResourceVersions map[string]string
+	// reconcileOnce makes the mid-cycle claim mutation check run at most once
+	// per scheduling cycle even though Filter runs for many nodes in parallel.
+	// Shared by state clones, like the claims it guards.
+	reconcileOnce *sync.Once
+}
+
+// pvModeUsage describes how a pod consumes a bound PersistentVolume.
+type pvModeUsage struct {
+	// mode is the volume mode the pod's claim provides the volume with.
+	mode v1.PersistentVolumeMode
+	// readOnly is true only if every reference the pod holds to the volume is read-only.
+	readOnly bool
+}
+
+// csiBlockUsage describes one raw block mapping of a CSI-backed
+// PersistentVolume by a pod, resolved from a block-mode claim through its
+// bound PersistentVolume.
+type csiBlockUsage struct {
+	// csi is the CSI source of the bound PersistentVolume. The driver and
+	// volume handle identify the underlying device across PersistentVolumes.
+	csi *v1.CSIPersistentVolumeSource
+	// readOnly is true if the pod's claim reference is read-only.
+	readOnly bool
+}
+
+// conflictCategory labels one category of volume restriction failure for aggregation.
+type conflictCategory int
+
+const (
+	conflictCategoryGCEPersistentDisk conflictCategory = iota
+	conflictCategoryAWSElasticBlockStore
+	conflictCategoryISCSI
+	conflictCategoryRBD
+	conflictCategoryFibreChannel
+	conflictCategoryFlexVolume
+	conflictCategoryVsphereVolume
+	conflictCategoryCinder
+	conflictCategoryAzureDisk
+	conflictCategoryScaleIO
+	conflictCategoryStorageOS
+	conflictCategoryReadWriteOncePod
+	numConflictCategories
+)
+
+var conflictCategoryNames = [numConflictCategories]string{
+	"GCEPersistentDisk",
+	"AWSElasticBlockStore",
+	"ISCSI",
+	"RBD",
+	"FibreChannel",
+	"FlexVolume",
+	"VsphereVolume",
+	"Cinder",
+	"AzureDisk",
+	"ScaleIO",
+	"StorageOS",
+	"ReadWriteOncePod",
+}
+
+// defaultConflictStatusCodes returns the status code Filter uses per conflict
+// category when the args don't override it. Every category defaults to
+// Unschedulable: a disk conflict is held by a pod running on the candidate
+// node, so preempting that pod frees the volume and the node stays a
+// preemption candidate. Operators who know preemption can never help in their
+// environment can opt a category into UnschedulableAndUnresolvable via
+// ConflictStatusCodes.
+func defaultConflictStatusCodes() [numConflictCategories]framework.Code {
+	var codes [numConflictCategories]framework.Code
+	for i := range codes {
+		codes[i] = framework.Unschedulable
+	}
+	return codes
+}
+
+// conflictStatusCodesFromArgs applies the ConflictStatusCodes overrides from
+// the plugin args on top of the defaults.
+func conflictStatusCodesFromArgs(overrides map[string]string) ([numConflictCategories]framework.Code, error) {
+	codes := defaultConflictStatusCodes()
+	for categoryName, codeName := range overrides {
+		category := -1
+		for i, name := range conflictCategoryNames {
+			if name == categoryName {
+				category = i
+				break
+			}
+		}
+		if category < 0 {
+			return codes, fmt.Errorf("unknown conflict category %q", categoryName)
+		}
+		switch codeName {
+		case framework.Unschedulable.String():
+			codes[category] = framework.Unschedulable
+		case framework.UnschedulableAndUnresolvable.String():
+			codes[category] = framework.UnschedulableAndUnresolvable
+		default:
+			return codes, fmt.Errorf("unsupported status code %q for conflict category %q", codeName, categoryName)
+		}
+	}
+	return codes, nil
+}
+
+// enabledConflictCategoriesFromArgs translates the category names from
+// EnabledDiskConflictCategories into a per-category lookup. Nil keeps every
+// disk category enabled so existing configs are unaffected; validation has
+// already rejected unknown names. ReadWriteOncePod is always enabled, as it
+// is not a disk conflict category.
+func enabledConflictCategoriesFromArgs(names []string) [numConflictCategories]bool {
+	var enabled [numConflictCategories]bool
+	enabled[conflictCategoryReadWriteOncePod] = true
+	// The legacy ScaleIO and StorageOS categories cannot be named in
+	// EnabledDiskConflictCategories; they are gated by LegacyVolumeSources
+	// instead.
+	enabled[conflictCategoryScaleIO] = true
+	enabled[conflictCategoryStorageOS] = true
+	if names == nil {
+		for i := range enabled {
+			enabled[i] = true
+		}
+		return enabled
+	}
+	for _, name := range names {
+		for category, categoryName := range conflictCategoryNames {
+			if name == categoryName {
+				enabled[category] = true
+			}
+		}
+	}
+	return enabled
+}
+
+// multiWriterCategoriesFromArgs translates the category names from
+// MultiWriterVolumeSources into a per-category lookup. Listed categories are
+// never considered conflicting; validation has already rejected unknown names.
+func multiWriterCategoriesFromArgs(names []string) [numConflictCategories]bool {
+	var multiWriter [numConflictCategories]bool
+	for _, name := range names {
+		for category, categoryName := range conflictCategoryNames {
+			if name == categoryName {
+				multiWriter[category] = true
+			}
+		}
+	}
+	return multiWriter
+}
+
+// conflictReasonAggregator counts the nodes a pod failed the Filter on, per
+// conflict category, during one scheduling cycle. State clones share the
+// aggregator, and Filter may run for many nodes in parallel, so the counters
+// are atomic.
+type conflictReasonAggregator struct {
+	counts [numConflictCategories]atomic.Int64
+}
+
+func (a *conflictReasonAggregator) record(category conflictCategory) {
+	a.counts[category].Add(1)
+}
+
+// countsByCategory returns the non-zero counts keyed by category name.
+func (a *conflictReasonAggregator) countsByCategory() map[string]int64 {
+	counts := map[string]int64{}
+	for i := range a.counts {
+		if count := a.counts[i].Load(); count > 0 {
+			counts[conflictCategoryNames[i]] = count
+		}
+	}
+	return counts
+}
+
+// recordConflict counts a Filter failure for the given category when conflict
+// reason aggregation is enabled.
+func (s *preFilterState) recordConflict(category conflictCategory) {
+	if s.conflictReasons != nil {
+		s.conflictReasons.record(category)
+	}
+}
+
+// conflictCategoryForVolume returns the aggregation category of a
+// restriction-relevant volume.
+func conflictCategoryForVolume(volume *v1.Volume) conflictCategory {
+	switch {
+	case volume.AWSElasticBlockStore != nil:
+		return conflictCategoryAWSElasticBlockStore
+	case volume.ISCSI != nil:
+		return conflictCategoryISCSI
+	case volume.RBD != nil:
+		return conflictCategoryRBD
+	case volume.FC != nil:
+		return conflictCategoryFibreChannel
+	case volume.FlexVolume != nil:
+		return conflictCategoryFlexVolume
+	case volume.VsphereVolume != nil:
+		return conflictCategoryVsphereVolume
+	case volume.Cinder != nil:
+		return conflictCategoryCinder
+	case volume.AzureDisk != nil:
+		return conflictCategoryAzureDisk
+	case volume.ScaleIO != nil:
+		return conflictCategoryScaleIO
+	case volume.StorageOS != nil:
+		return conflictCategoryStorageOS
+	}
+	return conflictCategoryGCEPersistentDisk
 }
 
 func (s *preFilterState) updateWithPod(podInfo *framework.PodInfo, multiplier int) {
 	s.conflictingPVCRefCount += multiplier * s.conflictingPVCRefCountForPod(podInfo)
 }
 
+// updateNodeVolumes keeps the node's distinct volume identities in sync with a
+// simulated pod addition or removal. The identities are built from nodeInfo on
+// first use, which at this point already reflects the change; afterwards they
+// are maintained incrementally.
+func (s *preFilterState) updateNodeVolumes(nodeInfo *framework.NodeInfo, pod *v1.Pod, multiplier int) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return
+	}
+	if s.nodeVolumes == nil {
+		s.nodeVolumes = make(map[string]*nodeVolumeIdentities)
+	}
+	volumes, ok := s.nodeVolumes[node.Name]
+	if !ok {
+		s.nodeVolumes[node.Name] = volumeIdentitiesForNode(nodeInfo, s.ignoreTerminatingPods, s.ignoreMirrorPods, s.flexIdentityKeys)
+		return
+	}
+	if multiplier >= 0 {
+		volumes.addPod(pod)
+	} else {
+		volumes.removePod(pod)
+	}
+}
+
 func (s *preFilterState) conflictingPVCRefCountForPod(podInfo *framework.PodInfo) int {
+	// A pod may mount the same claim under several volume entries; the count
+	// reflects distinct pod-to-claim usage, so duplicates within the pod are
+	// counted once. The set is only allocated once a conflicting claim is
+	// found, keeping the common conflict-free pod allocation-free.
+	var seen sets.Set[string]
 	conflicts := 0
 	for _, volume := range podInfo.Pod.Spec.Volumes {
 		if volume.PersistentVolumeClaim == nil {
 			continue
 		}
-		if s.readWriteOncePodPVCs.Has(volume.PersistentVolumeClaim.ClaimName) {
-			conflicts += 1
+		namespace, name := resolveClaimReference(s.crossNamespaceRefs, podInfo.Pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		key := framework.GetNamespacedName(namespace, name)
+		if !s.readWriteOncePodPVCs.Has(key) || seen.Has(key) {
+			continue
+		}
+		if seen == nil {
+			seen = sets.New[string]()
 		}
+		seen.Insert(key)
+		conflicts += 1
 	}
 	return conflicts
 }
@@ -88,10 +563,30 @@ func (s *preFilterState) Clone() framework.StateData {
 	if s == nil {
 		return nil
 	}
-	return &preFilterState{
+	c := &preFilterState{
 		readWriteOncePodPVCs:   s.readWriteOncePodPVCs,
+		crossNamespaceRefs:     s.crossNamespaceRefs,
+		ignoreTerminatingPods:  s.ignoreTerminatingPods,
+		ignoreMirrorPods:       s.ignoreMirrorPods,
+		flexIdentityKeys:       s.flexIdentityKeys,
 		conflictingPVCRefCount: s.conflictingPVCRefCount,
+		readWriteOncePVCNodes:  s.readWriteOncePVCNodes,
+		boundPVModes:           s.boundPVModes,
+		csiBlockVolumes:        s.csiBlockVolumes,
+		// The aggregator is intentionally shared so that clones contribute to the
+		// same per-cycle counts.
+		conflictReasons:     s.conflictReasons,
+		pvcResourceVersions: s.pvcResourceVersions,
+		reconcileOnce:       s.reconcileOnce,
+		sharedNodeVolumes:   s.sharedNodeVolumes,
 	}
+	if s.nodeVolumes != nil {
+		c.nodeVolumes = make(map[string]*nodeVolumeIdentities, len(s.nodeVolumes))
+		for name, volumes := range s.nodeVolumes {
+			c.nodeVolumes[name] = volumes.clone()
+		}
+	}
+	return c
 }
 
 // Name returns name of the plugin. It is used in logs, etc.
@@ -99,53 +594,555 @@ func (pl *VolumeRestrictions) Name() string {
 	return Name
 }
 
-func isVolumeConflict(volume *v1.Volume, pod *v1.Pod) bool {
-	for _, existingVolume := range pod.Spec.Volumes {
-		// Same GCE disk mounted by multiple pods conflicts unless all pods mount it read-only.
-		if volume.GCEPersistentDisk != nil && existingVolume.GCEPersistentDisk != nil {
-			disk, existingDisk := volume.GCEPersistentDisk, existingVolume.GCEPersistentDisk
-			if disk.PDName == existingDisk.PDName && !(disk.ReadOnly && existingDisk.ReadOnly) {
-				return true
-			}
+// FeatureStatus reports which of the plugin's optional behaviors are active,
+// as configured through the args and feature gates, for runtime introspection
+// by operators and support tooling. The keys are stable behavior names, not
+// arg names.
+func (pl *VolumeRestrictions) FeatureStatus() map[string]bool {
+	return map[string]bool{
+		"DiskConflictChecks":             pl.diskConflictMode != config.DiskConflictOff,
+		"DiskConflictWarnOnly":           pl.diskConflictMode == config.DiskConflictWarn,
+		"ConflictReasonAggregation":      pl.enableConflictReasonAggregation,
+		"RejectionHistory":               pl.rejections != nil,
+		"FailOnMissingAccessModes":       pl.failOnMissingAccessModes,
+		"ReadWriteOncePodLabelSelector":  pl.readWriteOncePodSelector != nil,
+		"AllowSharedWritersAnnotation":   pl.allowSharedWritersAnnotation != "",
+		"StrictUnknownCSIDriverPolicy":   pl.unknownCSIDriverPolicy != config.UnknownCSIDriverPermissive,
+		"StrictVolumeIdentity":           pl.strictVolumeIdentity,
+		"ConflictPolicy":                 pl.conflictPolicy != nil,
+		"CrossNamespaceVolumeReferences": pl.enableCrossNamespaceVolumeReferences,
+		"SchedulingQueueHints":           pl.enableSchedulingQueueHint,
+		"VolumeLocalityScoring":          pl.scoreWeight > 0,
+		"ReadWriteOncePerNode":           pl.enforceReadWriteOncePerNode,
+		"ExemptVolumeIdentities":         pl.exemptVolumeIdentities.Len() > 0,
+		"AssumedPodConflicts":            pl.assumedPodLister != nil,
+		"MultiWriterVolumeSources":       pl.multiWriterCategories != [numConflictCategories]bool{},
+		"LegacyVolumeSources":            pl.legacyVolumeSources,
+	}
+}
+
+// Prewarm builds the per-node volume identity indexes from the current
+// snapshot so the first scheduling cycles don't pay for building them lazily.
+// It is optional and meant to be called once after the informers have synced
+// and before scheduling starts; the indexes are discarded after one cycle
+// consumes them. Prewarming is a no-op when disk conflict checking is
+// disabled, since nothing consumes the indexes then. The ReadWriteOncePod
+// usage map lives in the snapshot's storage infos and is already built by the
+// time the informers have synced, so there is nothing to warm there.
+func (pl *VolumeRestrictions) Prewarm(ctx context.Context) error {
+	if pl.diskConflictMode == config.DiskConflictOff {
+		return nil
+	}
+	nodeInfos, err := pl.sharedLister.NodeInfos().List()
+	if err != nil {
+		return fmt.Errorf("listing NodeInfos: %w", err)
+	}
+	warm := make(map[string]*nodeVolumeIdentities, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		warm[node.Name] = volumeIdentitiesForNode(nodeInfo, pl.ignoreTerminatingPods, pl.ignoreMirrorPods, pl.flexVolumeIdentityKeys)
+	}
+	pl.warmNodeVolumesLock.Lock()
+	defer pl.warmNodeVolumesLock.Unlock()
+	pl.warmNodeVolumes = warm
+	return nil
+}
+
+// takeWarmNodeVolumes hands the prewarmed indexes to the caller and clears
+// them, so that at most one scheduling cycle seeds from a given Prewarm run;
+// later cycles rebuild lazily from the then-current snapshot.
+func (pl *VolumeRestrictions) takeWarmNodeVolumes() map[string]*nodeVolumeIdentities {
+	pl.warmNodeVolumesLock.Lock()
+	defer pl.warmNodeVolumesLock.Unlock()
+	warm := pl.warmNodeVolumes
+	pl.warmNodeVolumes = nil
+	return warm
+}
+
+// volumeIdentityKey returns a stable key identifying the underlying volume of a
+// restriction-relevant volume source. It returns false for volume sources that
+// don't need conflict checking.
+func volumeIdentityKey(volume *v1.Volume) (string, bool) {
+	switch {
+	case volume.GCEPersistentDisk != nil:
+		return "gce://" + volume.GCEPersistentDisk.PDName, true
+	case volume.AWSElasticBlockStore != nil:
+		return "aws://" + volume.AWSElasticBlockStore.VolumeID, true
+	case volume.ISCSI != nil:
+		return "iscsi://" + volume.ISCSI.IQN, true
+	case volume.RBD != nil:
+		monitors := make([]string, len(volume.RBD.CephMonitors))
+		copy(monitors, volume.RBD.CephMonitors)
+		sort.Strings(monitors)
+		return "rbd://" + strings.Join(monitors, ",") + "/" + rbdImageIdentity(volume.RBD), true
+	case volume.FC != nil:
+		return "fc://" + fcDeviceIdentity(volume.FC), true
+	case volume.FlexVolume != nil:
+		if id, ok := flexVolumeIdentity(volume.FlexVolume, defaultFlexVolumeIdentityKeys); ok {
+			return "flex://" + id, true
+		}
+	case volume.VsphereVolume != nil:
+		return "vsphere://" + volume.VsphereVolume.VolumePath, true
+	case volume.Cinder != nil:
+		return "cinder://" + volume.Cinder.VolumeID, true
+	case volume.AzureDisk != nil:
+		return "azuredisk://" + azureDiskIdentity(volume.AzureDisk), true
+	case volume.ScaleIO != nil:
+		return "scaleio://" + volume.ScaleIO.System + "/" + volume.ScaleIO.VolumeName, true
+	case volume.StorageOS != nil:
+		return "storageos://" + volume.StorageOS.VolumeName, true
+	}
+	return "", false
+}
+
+// emptyVolumeIdentityField returns the identity field a restriction-relevant
+// volume leaves empty, if any. A volume with empty identity fields can never
+// be matched against other volumes during conflict checking, which usually
+// indicates a misconfiguration rather than intent.
+func emptyVolumeIdentityField(volume *v1.Volume) (string, bool) {
+	switch {
+	case volume.ISCSI != nil && volume.ISCSI.IQN == "":
+		return "IQN", true
+	case volume.RBD != nil:
+		switch {
+		case len(volume.RBD.CephMonitors) == 0:
+			return "monitor list", true
+		case volume.RBD.RBDPool == "":
+			return "pool", true
+		case strings.TrimSuffix(volume.RBD.RBDImage, "@") == "":
+			return "image", true
+		}
+	case volume.FC != nil && len(volume.FC.TargetWWNs) == 0 && len(volume.FC.WWIDs) == 0:
+		return "target WWN and WWID lists", true
+	case volume.VsphereVolume != nil && volume.VsphereVolume.VolumePath == "":
+		return "volume path", true
+	case volume.Cinder != nil && volume.Cinder.VolumeID == "":
+		return "volume ID", true
+	case volume.AzureDisk != nil && volume.AzureDisk.DiskName == "" && volume.AzureDisk.DataDiskURI == "":
+		return "disk name and data disk URI", true
+	case volume.ScaleIO != nil:
+		switch {
+		case volume.ScaleIO.System == "":
+			return "system", true
+		case volume.ScaleIO.VolumeName == "":
+			return "volume name", true
+		}
+	case volume.StorageOS != nil && volume.StorageOS.VolumeName == "":
+		return "volume name", true
+	}
+	return "", false
+}
+
+// azureDiskIdentity returns the identity of an AzureDisk volume: the data disk
+// URI, which is also the volume handle the CSI-migrated disk.csi.azure.com
+// driver addresses the disk by, falling back to the disk name when the URI is
+// not set. Azure resource URIs are case-insensitive, so the identity is
+// lowercased.
+func azureDiskIdentity(azure *v1.AzureDiskVolumeSource) string {
+	if azure.DataDiskURI != "" {
+		return strings.ToLower(azure.DataDiskURI)
+	}
+	return strings.ToLower(azure.DiskName)
+}
+
+// azureDiskShared returns whether an AzureDisk volume refers to a disk in
+// shared mode, which supports multiple simultaneous read-write attachments.
+func azureDiskShared(azure *v1.AzureDiskVolumeSource) bool {
+	return azure.Kind != nil && *azure.Kind == v1.AzureSharedBlobDisk
+}
+
+// azureDiskReadOnly returns whether an AzureDisk volume is mounted read-only.
+func azureDiskReadOnly(azure *v1.AzureDiskVolumeSource) bool {
+	return azure.ReadOnly != nil && *azure.ReadOnly
+}
+
+// rbdImageIdentity returns the identity of an RBD volume's image within its
+// pool. The identity is pool and image name only: independent clones of the
+// same parent are distinct images and must not conflict, so parent lineage
+// plays no part. Image names may carry an "image@snapshot" qualifier; a
+// snapshot-qualified image is a distinct device from its base image, so the
+// qualifier stays part of the identity. An empty qualifier ("image@") refers
+// to the base image itself.
+func rbdImageIdentity(rbd *v1.RBDVolumeSource) string {
+	image := strings.TrimSuffix(rbd.RBDImage, "@")
+	return rbd.RBDPool + "/" + image
+}
+
+// fcDeviceIdentity returns a stable identity of the device paths of an FC
+// volume: the target WWNs qualified by the LUN, and the WWIDs. The identity is
+// independent of the order the paths are listed in.
+func fcDeviceIdentity(fc *v1.FCVolumeSource) string {
+	targets := make([]string, 0, len(fc.TargetWWNs))
+	if fc.Lun != nil {
+		for _, wwn := range fc.TargetWWNs {
+			targets = append(targets, fmt.Sprintf("%s:%d", wwn, *fc.Lun))
+		}
+	}
+	sort.Strings(targets)
+	wwids := make([]string, len(fc.WWIDs))
+	copy(wwids, fc.WWIDs)
+	sort.Strings(wwids)
+	return strings.Join(targets, ",") + "/" + strings.Join(wwids, ",")
+}
+
+// defaultFlexVolumeIdentityKeys is the list of FlexVolume option keys
+// consulted for the device identity when the args don't name others.
+var defaultFlexVolumeIdentityKeys = []string{"volumeID"}
+
+// flexVolumeIdentity returns the identity of a FlexVolume's underlying device:
+// the driver name qualified by the value of the first identifying option key
+// present. Drivers name the identifying option differently, so the keys are
+// configurable through FlexVolumeIdentityKeys. A volume carrying none of the
+// keys cannot be matched against other volumes and takes no part in conflict
+// checking.
+func flexVolumeIdentity(flex *v1.FlexVolumeSource, keys []string) (string, bool) {
+	for _, key := range keys {
+		if value := flex.Options[key]; value != "" {
+			return flex.Driver + "/" + value, true
+		}
+	}
+	return "", false
+}
+
+// fcVolumesOverlap returns true if two FC volumes address the same device:
+// they share a target WWN addressing the same LUN, or they share a WWID.
+func fcVolumesOverlap(a, b *v1.FCVolumeSource) bool {
+	if a.Lun != nil && b.Lun != nil && *a.Lun == *b.Lun && haveOverlap(a.TargetWWNs, b.TargetWWNs) {
+		return true
+	}
+	return haveOverlap(a.WWIDs, b.WWIDs)
+}
+
+// VolumeIdentityHash returns a stable hash of the pod's restriction-relevant
+// volume identities, for use as a cache key by layers caching conflict decisions.
+// The hash is independent of the order in which volumes appear in the pod spec
+// and stable across runs.
+func VolumeIdentityHash(pod *v1.Pod) uint64 {
+	keys := make([]string, 0, len(pod.Spec.Volumes))
+	for i := range pod.Spec.Volumes {
+		if key, ok := volumeIdentityKey(&pod.Spec.Volumes[i]); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	hasher := fnv.New64a()
+	for _, key := range keys {
+		hasher.Write([]byte(key))
+		// Separate the keys so that their concatenation is unambiguous.
+		hasher.Write([]byte{0})
+	}
+	return hasher.Sum64()
+}
+
+// volumeReadOnly returns whether a restriction-relevant volume is mounted read-only.
+func volumeReadOnly(volume *v1.Volume) bool {
+	switch {
+	case volume.GCEPersistentDisk != nil:
+		return volume.GCEPersistentDisk.ReadOnly
+	case volume.AWSElasticBlockStore != nil:
+		return volume.AWSElasticBlockStore.ReadOnly
+	case volume.ISCSI != nil:
+		return volume.ISCSI.ReadOnly
+	case volume.RBD != nil:
+		return volume.RBD.ReadOnly
+	case volume.FC != nil:
+		return volume.FC.ReadOnly
+	case volume.FlexVolume != nil:
+		return volume.FlexVolume.ReadOnly
+	case volume.Cinder != nil:
+		return volume.Cinder.ReadOnly
+	case volume.AzureDisk != nil:
+		return azureDiskReadOnly(volume.AzureDisk)
+	case volume.ScaleIO != nil:
+		return volume.ScaleIO.ReadOnly
+	case volume.StorageOS != nil:
+		return volume.StorageOS.ReadOnly
+	}
+	return false
+}
+
+// volumeIdentityEntry aggregates all references on a node to one distinct volume identity.
+type volumeIdentityEntry struct {
+	// volume is a representative of the identity; all references on the node point
+	// to the same underlying volume.
+	volume v1.Volume
+	// refCount is the number of references to the identity by the node's pods.
+	refCount int
+	// readOnlyCount is the number of those references that are read-only.
+	readOnlyCount int
+}
+
+// allReadOnly returns whether every reference to the identity is read-only.
+func (e *volumeIdentityEntry) allReadOnly() bool {
+	return e.readOnlyCount == e.refCount
+}
+
+// nodeVolumeIdentities indexes the distinct restriction-relevant volume identities
+// referenced by the pods on a node. When many pods share the same volume, conflict
+// checks compare an incoming volume against each distinct identity once instead of
+// against every referencing pod.
+type nodeVolumeIdentities struct {
+	identities map[string]*volumeIdentityEntry
+	// ignoreTerminating excludes pods with a deletionTimestamp from the index,
+	// on the assumption that their volumes are about to be released.
+	ignoreTerminating bool
+	// ignoreMirror excludes mirror pods from the index.
+	ignoreMirror bool
+	// flexIdentityKeys are the FlexVolume option keys consulted for the device
+	// identity; nil falls back to defaultFlexVolumeIdentityKeys.
+	flexIdentityKeys []string
+}
+
+func volumeIdentitiesForNode(nodeInfo *framework.NodeInfo, ignoreTerminating, ignoreMirror bool, flexIdentityKeys []string) *nodeVolumeIdentities {
+	nv := &nodeVolumeIdentities{identities: make(map[string]*volumeIdentityEntry), ignoreTerminating: ignoreTerminating, ignoreMirror: ignoreMirror, flexIdentityKeys: flexIdentityKeys}
+	for _, podInfo := range nodeInfo.Pods {
+		nv.addPod(podInfo.Pod)
+	}
+	return nv
+}
+
+// volumeKey returns the identity key of a restriction-relevant volume under
+// this index's configuration. It differs from volumeIdentityKey only for
+// FlexVolumes, whose identifying option keys are configurable.
+func (nv *nodeVolumeIdentities) volumeKey(volume *v1.Volume) (string, bool) {
+	if volume.FlexVolume != nil {
+		keys := nv.flexIdentityKeys
+		if len(keys) == 0 {
+			keys = defaultFlexVolumeIdentityKeys
+		}
+		if id, ok := flexVolumeIdentity(volume.FlexVolume, keys); ok {
+			return "flex://" + id, true
+		}
+		return "", false
+	}
+	return volumeIdentityKey(volume)
+}
+
+func (nv *nodeVolumeIdentities) addPod(pod *v1.Pod) {
+	if nv.excludesPod(pod) {
+		return
+	}
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		key, ok := nv.volumeKey(volume)
+		if !ok {
+			continue
+		}
+		entry := nv.identities[key]
+		if entry == nil {
+			entry = &volumeIdentityEntry{volume: *volume}
+			nv.identities[key] = entry
+		}
+		entry.refCount++
+		if volumeReadOnly(volume) {
+			entry.readOnlyCount++
+		}
+	}
+}
+
+// excludesPod reports whether the pod is excluded from the index by the
+// configured gates.
+func (nv *nodeVolumeIdentities) excludesPod(pod *v1.Pod) bool {
+	if nv.ignoreTerminating && pod.DeletionTimestamp != nil {
+		return true
+	}
+	return nv.ignoreMirror && isMirrorPod(pod)
+}
+
+// isMirrorPod reports whether the pod is a mirror pod, i.e. the API
+// representation of a static pod, identified by the annotation kubelets set
+// when creating one.
+func isMirrorPod(pod *v1.Pod) bool {
+	_, ok := pod.Annotations[v1.MirrorPodAnnotationKey]
+	return ok
+}
+
+func (nv *nodeVolumeIdentities) removePod(pod *v1.Pod) {
+	if nv.excludesPod(pod) {
+		// The pod was never added to the index.
+		return
+	}
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		key, ok := nv.volumeKey(volume)
+		if !ok {
+			continue
+		}
+		entry := nv.identities[key]
+		if entry == nil {
+			continue
+		}
+		entry.refCount--
+		if volumeReadOnly(volume) {
+			entry.readOnlyCount--
 		}
+		if entry.refCount <= 0 {
+			delete(nv.identities, key)
+		}
+	}
+}
+
+func (nv *nodeVolumeIdentities) clone() *nodeVolumeIdentities {
+	c := &nodeVolumeIdentities{identities: make(map[string]*volumeIdentityEntry, len(nv.identities)), ignoreTerminating: nv.ignoreTerminating, ignoreMirror: nv.ignoreMirror, flexIdentityKeys: nv.flexIdentityKeys}
+	for key, entry := range nv.identities {
+		entryCopy := *entry
+		c.identities[key] = &entryCopy
+	}
+	return c
+}
 
-		if volume.AWSElasticBlockStore != nil && existingVolume.AWSElasticBlockStore != nil {
-			if volume.AWSElasticBlockStore.VolumeID == existingVolume.AWSElasticBlockStore.VolumeID {
+// conflicts returns true if the incoming volume conflicts with any of the node's
+// distinct volume identities:
+//   - Same GCE disk mounted by multiple pods conflicts unless all pods mount it read-only.
+//   - Same AWS EBS volume mounted by multiple pods conflicts unless all pods mount it
+//     read-only; read-only mounts and io2 Multi-Attach volumes can legitimately be shared,
+//     matching the GCE PD semantics.
+//   - Two ISCSI volumes are the same if they share the same iqn. As iscsi volumes are of type
+//     RWO or ROX, we could permit only one RW mount; they conflict unless all mounts are read-only.
+//   - Two RBD images are the same if they share at least one Ceph monitor, are in the same RADOS
+//     pool, and have the same image name; they conflict unless all mounts are read-only.
+//   - Two FC volumes are the same if they share a target WWN addressing the same LUN, or share
+//     a WWID; they conflict unless all mounts are read-only.
+//   - Two FlexVolumes are the same if they share the driver and the value of an
+//     identifying option key; they conflict unless all mounts are read-only.
+//   - Two vSphere volumes are the same if they share the VolumePath. VMDKs are
+//     single-writer and the volume source carries no read-only hint, so they
+//     always conflict.
+//   - Same Cinder volume mounted by multiple pods conflicts unless all pods
+//     mount it read-only, matching the GCE PD semantics.
+//   - Two AzureDisk volumes are the same if they share the data disk URI (or
+//     disk name); they conflict unless all mounts are read-only or every
+//     reference uses the disk in shared mode.
+//   - Two ScaleIO volumes are the same if they share the system and volume
+//     name; they conflict unless all mounts are read-only. Only checked when
+//     LegacyVolumeSources is set.
+//   - Two StorageOS volumes are the same if they share the volume name; the
+//     volume namespace commonly defaults from the pod and takes no part in the
+//     identity. They conflict unless all mounts are read-only. Only checked
+//     when LegacyVolumeSources is set.
+func (nv *nodeVolumeIdentities) conflicts(volume *v1.Volume) bool {
+	switch {
+	case volume.GCEPersistentDisk != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok && !(volume.GCEPersistentDisk.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.AWSElasticBlockStore != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok && !(volume.AWSElasticBlockStore.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.ISCSI != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok && !(volume.ISCSI.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.FlexVolume != nil:
+		key, ok := nv.volumeKey(volume)
+		if !ok {
+			return false
+		}
+		if entry, exists := nv.identities[key]; exists && !(volume.FlexVolume.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.VsphereVolume != nil:
+		key, _ := volumeIdentityKey(volume)
+		if _, ok := nv.identities[key]; ok {
+			return true
+		}
+	case volume.Cinder != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok && !(volume.Cinder.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.AzureDisk != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok {
+			// A disk in shared mode supports multiple simultaneous writers,
+			// but only when every reference uses it in shared mode.
+			if azureDiskShared(volume.AzureDisk) && entry.volume.AzureDisk != nil && azureDiskShared(entry.volume.AzureDisk) {
+				return false
+			}
+			if !(azureDiskReadOnly(volume.AzureDisk) && entry.allReadOnly()) {
 				return true
 			}
 		}
-
-		if volume.ISCSI != nil && existingVolume.ISCSI != nil {
-			iqn := volume.ISCSI.IQN
-			eiqn := existingVolume.ISCSI.IQN
-			// two ISCSI volumes are same, if they share the same iqn. As iscsi volumes are of type
-			// RWO or ROX, we could permit only one RW mount. Same iscsi volume mounted by multiple Pods
-			// conflict unless all other pods mount as read only.
-			if iqn == eiqn && !(volume.ISCSI.ReadOnly && existingVolume.ISCSI.ReadOnly) {
+	case volume.ScaleIO != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok && !(volume.ScaleIO.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.StorageOS != nil:
+		key, _ := volumeIdentityKey(volume)
+		if entry, ok := nv.identities[key]; ok && !(volume.StorageOS.ReadOnly && entry.allReadOnly()) {
+			return true
+		}
+	case volume.RBD != nil:
+		// RBD identities with distinct monitor lists can still refer to the same
+		// image, so all RBD identities are checked for monitor overlap.
+		for _, entry := range nv.identities {
+			existing := entry.volume.RBD
+			if existing == nil {
+				continue
+			}
+			if haveOverlap(volume.RBD.CephMonitors, existing.CephMonitors) &&
+				rbdImageIdentity(volume.RBD) == rbdImageIdentity(existing) &&
+				!(volume.RBD.ReadOnly && entry.allReadOnly()) {
 				return true
 			}
 		}
-
-		if volume.RBD != nil && existingVolume.RBD != nil {
-			mon, pool, image := volume.RBD.CephMonitors, volume.RBD.RBDPool, volume.RBD.RBDImage
-			emon, epool, eimage := existingVolume.RBD.CephMonitors, existingVolume.RBD.RBDPool, existingVolume.RBD.RBDImage
-			// two RBDs images are the same if they share the same Ceph monitor, are in the same RADOS Pool, and have the same image name
-			// only one read-write mount is permitted for the same RBD image.
-			// same RBD image mounted by multiple Pods conflicts unless all Pods mount the image read-only
-			if haveOverlap(mon, emon) && pool == epool && image == eimage && !(volume.RBD.ReadOnly && existingVolume.RBD.ReadOnly) {
+	case volume.FC != nil:
+		// FC identities with distinct target or WWID lists can still address
+		// the same device, so all FC identities are checked for overlap.
+		for _, entry := range nv.identities {
+			existing := entry.volume.FC
+			if existing == nil {
+				continue
+			}
+			if fcVolumesOverlap(volume.FC, existing) &&
+				!(volume.FC.ReadOnly && entry.allReadOnly()) {
 				return true
 			}
 		}
 	}
-
 	return false
 }
 
+// csiDriverKnown reports whether the driver has a CSIDriver object registered,
+// which is what tells the plugin the driver's attach semantics. Drivers
+// without one fall under the configured UnknownCSIDriverPolicy.
+func (pl *VolumeRestrictions) csiDriverKnown(driver string) bool {
+	_, err := pl.csiDriverLister.Get(driver)
+	return err == nil
+}
+
+// smallOverlapSliceLen is the slice length up to which haveOverlap compares
+// element-wise instead of building a set. Monitor and WWN lists rarely exceed
+// a handful of entries, so the comparison stays allocation-free on the Filter
+// hot path.
+const smallOverlapSliceLen = 4
+
 // haveOverlap searches two arrays and returns true if they have at least one common element; returns false otherwise.
 func haveOverlap(a1, a2 []string) bool {
 	if len(a1) > len(a2) {
 		a1, a2 = a2, a1
 	}
+	if len(a1) <= smallOverlapSliceLen {
+		for _, val := range a1 {
+			for _, other := range a2 {
+				if val == other {
+					return true
+				}
+			}
+		}
+		return false
+	}
 	m := sets.New(a1...)
 	for _, val := range a2 {
 		if _, ok := m[val]; ok {
@@ -156,136 +1153,1441 @@ func haveOverlap(a1, a2 []string) bool {
 	return false
 }
 
-// return true if there are conflict checking targets.
-func needsRestrictionsCheck(v v1.Volume) bool {
-	return v.GCEPersistentDisk != nil || v.AWSElasticBlockStore != nil || v.RBD != nil || v.ISCSI != nil
+// return true if there are conflict checking targets. The legacy ScaleIO and
+// StorageOS sources are targets only when LegacyVolumeSources is set.
+func needsRestrictionsCheck(v v1.Volume, legacyVolumeSources bool) bool {
+	if legacyVolumeSources && (v.ScaleIO != nil || v.StorageOS != nil) {
+		return true
+	}
+	return v.GCEPersistentDisk != nil || v.AWSElasticBlockStore != nil || v.RBD != nil || v.ISCSI != nil || v.FC != nil || v.FlexVolume != nil || v.VsphereVolume != nil || v.Cinder != nil || v.AzureDisk != nil
+}
+
+// isKnownSharedVolume reports whether the volume is a known multi-writer
+// volume source: Quobyte, CephFS and Glusterfs are shared filesystems designed
+// for concurrent writers (ReadWriteMany), so they intentionally take no part
+// in disk conflict checking. The helper exists so that can be said explicitly
+// in the logs; it has no effect on filtering.
+func isKnownSharedVolume(v *v1.Volume) bool {
+	return v.Quobyte != nil || v.CephFS != nil || v.Glusterfs != nil
+}
+
+// volumeCheckEnabled reports whether the volume's conflict category is among
+// the configured EnabledDiskConflictCategories, isn't declared multi-writer
+// through MultiWriterVolumeSources, and its identity is not listed in
+// ExemptVolumeIdentities.
+func (pl *VolumeRestrictions) volumeCheckEnabled(volume *v1.Volume) bool {
+	category := conflictCategoryForVolume(volume)
+	if !pl.enabledConflictCategories[category] || pl.multiWriterCategories[category] {
+		return false
+	}
+	if len(pl.exemptVolumeIdentities) > 0 {
+		if key, ok := volumeIdentityKey(volume); ok && pl.exemptVolumeIdentities.Has(key) {
+			return false
+		}
+	}
+	return true
 }
 
 // PreFilter computes and stores cycleState containing details for enforcing ReadWriteOncePod.
+// It also narrows the candidate nodes to those satisfying the node affinity of all
+// PersistentVolumes already bound to the pod's claims. With
+// EnforceReadWriteOncePerNode, a bound ReadWriteOnce claim already in use pins
+// the pod to the node using it, narrowing the candidates further.
 func (pl *VolumeRestrictions) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
 	needsCheck := false
-	for i := range pod.Spec.Volumes {
-		if needsRestrictionsCheck(pod.Spec.Volumes[i]) {
+	if pl.diskConflictMode != config.DiskConflictOff {
+		for i := range pod.Spec.Volumes {
+			volume := &pod.Spec.Volumes[i]
+			if !needsRestrictionsCheck(*volume, pl.legacyVolumeSources) || !pl.volumeCheckEnabled(volume) {
+				continue
+			}
 			needsCheck = true
-			break
+			if !pl.strictVolumeIdentity {
+				break
+			}
+			if field, empty := emptyVolumeIdentityField(volume); empty {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEmptyVolumeIdentity,
+					fmt.Sprintf("volume %q has an empty %s", volume.Name, field))
+			}
+		}
+	}
+
+	// Pods mounting shared-filesystem volumes occasionally prompt "why wasn't
+	// this pod restricted" questions; at high verbosity, say explicitly that
+	// skipping them is intentional.
+	if logger := klog.FromContext(ctx); logger.V(5).Enabled() {
+		var sharedVolumes []string
+		for i := range pod.Spec.Volumes {
+			if isKnownSharedVolume(&pod.Spec.Volumes[i]) {
+				sharedVolumes = append(sharedVolumes, pod.Spec.Volumes[i].Name)
+			}
+		}
+		if len(sharedVolumes) > 0 {
+			logger.V(5).Info("Pod mounts known multi-writer volumes, which intentionally take no part in disk conflict checking",
+				"pod", klog.KObj(pod), "volumes", sharedVolumes)
+		}
+	}
+
+	if needsCheck {
+		// A DaemonSet replica lands on every node, so a DaemonSet pod mounting a
+		// fixed-identity volume read-write is guaranteed to conflict across nodes,
+		// which the per-node Filter cannot see. The warning is advisory only.
+		if keys := daemonSetSharedVolumeKeys(pod, pl.legacyVolumeSources); len(keys) > 0 {
+			logger := klog.FromContext(ctx)
+			logger.V(2).Info("Pod is owned by a DaemonSet but mounts fixed-identity volumes read-write; the replicas are guaranteed to conflict across nodes",
+				"pod", klog.KObj(pod), "volumes", keys)
+		}
+	}
+
+	pvcs, status := pl.readWriteOncePodPVCsForPod(ctx, pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+
+	if len(pvcs) > 0 {
+		// A ReadWriteOncePod claim shared by the replicas of a controller is a likely
+		// misconfiguration: only one of the replicas can ever run at a time.
+		// The warning is advisory and best effort only; detecting it scans
+		// every pod in the snapshot, so the scan only runs when the verbosity
+		// would show the result.
+		if logger := klog.FromContext(ctx); logger.V(2).Enabled() {
+			if nodeInfos, err := pl.sharedLister.NodeInfos().List(); err == nil && hasCoReplicaPods(pod, nodeInfos) {
+				logger.V(2).Info("Pod references a ReadWriteOncePod PersistentVolumeClaim but is owned by a controller managing multiple replicas; only one replica can run at a time",
+					"pod", klog.KObj(pod), "persistentVolumeClaims", sets.List(pvcs))
+			}
+		}
+	}
+
+	nodeNames, status := pl.pvNodeAffinityNodeNames(pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	if nodeNames != nil && nodeNames.Len() == 0 {
+		// The node affinity of the pod's bound PersistentVolumes contradict each
+		// other; no node can ever satisfy all of them.
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPVNodeAffinityConflict)
+	}
+
+	gcePDNodeNames, status := pl.gcePDZoneNodeNames(pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	if gcePDNodeNames != nil {
+		if nodeNames == nil {
+			nodeNames = gcePDNodeNames
+		} else {
+			nodeNames = nodeNames.Intersection(gcePDNodeNames)
+		}
+		if nodeNames.Len() == 0 {
+			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonGCEPDZoneConflict)
+		}
+	}
+
+	ebsNodeNames, status := pl.ebsAttachmentNodeNames(pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	if ebsNodeNames != nil {
+		if nodeNames == nil {
+			nodeNames = ebsNodeNames
+		} else {
+			nodeNames = nodeNames.Intersection(ebsNodeNames)
+		}
+		if nodeNames.Len() == 0 {
+			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEBSVolumeAttachedElsewhere)
+		}
+	}
+
+	s, err := pl.calPreFilterState(ctx, pod, pvcs)
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	s.pvcResourceVersions = pl.pvcResourceVersionsForPod(pod)
+	s.reconcileOnce = new(sync.Once)
+	s.boundPVModes, status = pl.boundPVModesForPod(pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	s.readWriteOncePVCNodes, status = pl.readWriteOncePVCNodesForPod(pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	rwoNodeNames, status := readWriteOncePerNodeNodeNames(s.readWriteOncePVCNodes)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	if rwoNodeNames != nil {
+		if nodeNames == nil {
+			nodeNames = rwoNodeNames
+		} else {
+			nodeNames = nodeNames.Intersection(rwoNodeNames)
+		}
+		if nodeNames.Len() == 0 {
+			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonReadWriteOnceNodeConflict)
+		}
+	}
+	s.csiBlockVolumes, status = pl.csiBlockUsagesForPod(pod)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	if pl.enableConflictReasonAggregation {
+		s.conflictReasons = &conflictReasonAggregator{}
+	}
+	pl.updateReadWriteOncePodGauges(pod, s)
+	if needsCheck {
+		// Seed the node volume indexes from Prewarm, if it ran and they haven't
+		// been consumed yet.
+		s.nodeVolumes = pl.takeWarmNodeVolumes()
+		s.sharedNodeVolumes = &sync.Map{}
+	}
+
+	if !needsCheck && s.conflictingPVCRefCount == 0 && len(s.boundPVModes) == 0 && len(s.readWriteOncePVCNodes) == 0 {
+		if nodeNames != nil {
+			// Nothing for Filter to check, but the candidate nodes can still be narrowed.
+			cycleState.Write(preFilterStateKey, s)
+			return &framework.PreFilterResult{NodeNames: nodeNames}, nil
+		}
+		return nil, framework.NewStatus(framework.Skip)
+	}
+	cycleState.Write(preFilterStateKey, s)
+	if nodeNames != nil {
+		return &framework.PreFilterResult{NodeNames: nodeNames}, nil
+	}
+	return nil, nil
+}
+
+// pvNodeAffinityNodeNames computes the set of nodes satisfying the node affinity of
+// every PersistentVolume bound to the pod's claims. It returns nil if none of the
+// bound volumes restrict placement, in which case all nodes remain candidates.
+// Unbound claims are left for the VolumeBinding plugin to resolve.
+func (pl *VolumeRestrictions) pvNodeAffinityNodeNames(pod *v1.Pod) (sets.Set[string], *framework.Status) {
+	var pvs []*v1.PersistentVolume
+	for i := range pod.Spec.Volumes {
+		pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+		if pvcSource == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, pvcSource.ClaimName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := pl.pvLister.Get(pvc.Spec.VolumeName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+		pvs = append(pvs, pv)
+	}
+	if len(pvs) == 0 {
+		return nil, nil
+	}
+
+	nodeInfos, err := pl.sharedLister.NodeInfos().List()
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	nodeNames := sets.New[string]()
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		satisfiesAll := true
+		for _, pv := range pvs {
+			if err := storagehelpers.CheckNodeAffinity(pv, node.Labels); err != nil {
+				satisfiesAll = false
+				break
+			}
+		}
+		if satisfiesAll {
+			nodeNames.Insert(node.Name)
+		}
+	}
+	return nodeNames, nil
+}
+
+// AttachmentState maps a volume identity key to the name of the node the
+// volume is currently attached to. Single-attach volumes absent from the map
+// are considered detached. The keys match those produced internally for
+// restriction-relevant volumes, e.g. "aws://<volumeID>" and "gce://<pdName>".
+type AttachmentState map[string]string
+
+// FeasibleNodesForVolumes returns the names of the nodes where every
+// restriction-relevant volume of the pod can currently be attached and
+// mounted without conflict: a single-attach volume attached to a node per
+// attachmentState pins the pod to that node, and mount conflicts with the
+// pods already on a node exclude it. The helper serves external schedulers
+// and capacity tools; the plugin itself narrows the candidate nodes during
+// PreFilter from the scheduler's snapshot.
+func FeasibleNodesForVolumes(pod *v1.Pod, nodes []*framework.NodeInfo, attachmentState AttachmentState) sets.Set[string] {
+	feasible := sets.New[string]()
+	for _, nodeInfo := range nodes {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		if satisfyVolumeConflicts(pod, nodeInfo, false) && volumesAttachableOnNode(pod, node.Name, attachmentState) {
+			feasible.Insert(node.Name)
+		}
+	}
+	return feasible
+}
+
+// volumesAttachableOnNode returns whether every single-attach volume of the
+// pod is either detached or already attached to the given node.
+func volumesAttachableOnNode(pod *v1.Pod, nodeName string, attachmentState AttachmentState) bool {
+	if len(attachmentState) == 0 {
+		return true
+	}
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		if !volumeIsSingleAttach(volume) {
+			continue
+		}
+		key, ok := volumeIdentityKey(volume)
+		if !ok {
+			continue
+		}
+		if attachedTo, attached := attachmentState[key]; attached && attachedTo != nodeName {
+			return false
+		}
+	}
+	return true
+}
+
+// gcePDZoneNodeNames computes the set of nodes in the zones of the GCE
+// PersistentDisks bound to the pod's claims. A zonal disk is labeled on its
+// PersistentVolume with the one zone it can attach in; a regional disk is
+// replicated across two zones, labeled with both joined by the multi-zone
+// delimiter, and can attach in either. It returns nil if none of the bound
+// volumes are zone-labeled GCE PersistentDisks, in which case all nodes remain
+// candidates. Unbound claims are left for the VolumeBinding plugin to resolve.
+func (pl *VolumeRestrictions) gcePDZoneNodeNames(pod *v1.Pod) (sets.Set[string], *framework.Status) {
+	var pdZones []sets.Set[string]
+	for i := range pod.Spec.Volumes {
+		pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+		if pvcSource == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, pvcSource.ClaimName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv, err := pl.pvLister.Get(pvc.Spec.VolumeName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if pv.Spec.GCEPersistentDisk == nil {
+			continue
+		}
+		zoneLabel, ok := pv.Labels[v1.LabelTopologyZone]
+		if !ok {
+			zoneLabel, ok = pv.Labels[v1.LabelFailureDomainBetaZone]
+		}
+		if !ok {
+			continue
+		}
+		zones, err := volumehelpers.LabelZonesToSet(zoneLabel)
+		if err != nil {
+			// A malformed zone label cannot restrict placement.
+			continue
+		}
+		pdZones = append(pdZones, sets.Set[string](zones))
+	}
+	if len(pdZones) == 0 {
+		return nil, nil
+	}
+
+	nodeInfos, err := pl.sharedLister.NodeInfos().List()
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	nodeNames := sets.New[string]()
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		zone, ok := node.Labels[v1.LabelTopologyZone]
+		if !ok {
+			zone = node.Labels[v1.LabelFailureDomainBetaZone]
+		}
+		inAllZones := zone != ""
+		for _, zones := range pdZones {
+			if !zones.Has(zone) {
+				inAllZones = false
+				break
+			}
+		}
+		if inAllZones {
+			nodeNames.Insert(node.Name)
+		}
+	}
+	return nodeNames, nil
+}
+
+// volumeIsSingleAttach returns whether the volume can only be attached to one
+// node at a time. AWS EBS volumes always are; GCE disks may be attached to
+// several nodes only when every mount is read-only, so a read-write mount
+// behaves single-attach.
+func volumeIsSingleAttach(volume *v1.Volume) bool {
+	switch {
+	case volume.AWSElasticBlockStore != nil:
+		return true
+	case volume.GCEPersistentDisk != nil:
+		return !volume.GCEPersistentDisk.ReadOnly
+	}
+	return false
+}
+
+// ebsInTreePluginName is the in-tree volume plugin name prefixing the unique
+// volume names that nodes report for attached AWS EBS volumes.
+const ebsInTreePluginName = "kubernetes.io/aws-ebs"
+
+// ebsAttachmentNodeNames computes, for the AWS EBS volumes the pod mounts, the
+// only node the pod could run on: EBS volumes are single-attach cluster-wide,
+// so a volume already attached to a node makes every other node infeasible.
+// Attachments are read from node status. It returns nil if none of the pod's
+// EBS volumes are attached anywhere, in which case all nodes remain candidates,
+// and UnschedulableAndUnresolvable when a volume is stuck attached to an
+// unavailable node.
+func (pl *VolumeRestrictions) ebsAttachmentNodeNames(pod *v1.Pod) (sets.Set[string], *framework.Status) {
+	var volumeIDs []string
+	for i := range pod.Spec.Volumes {
+		if ebs := pod.Spec.Volumes[i].AWSElasticBlockStore; ebs != nil {
+			volumeIDs = append(volumeIDs, ebs.VolumeID)
+		}
+	}
+	if len(volumeIDs) == 0 {
+		return nil, nil
+	}
+
+	nodeInfos, err := pl.sharedLister.NodeInfos().List()
+	if err != nil {
+		return nil, framework.AsStatus(err)
+	}
+	var nodeNames sets.Set[string]
+	for _, volumeID := range volumeIDs {
+		for _, nodeInfo := range nodeInfos {
+			node := nodeInfo.Node()
+			if !nodeHasEBSVolumeAttached(node, volumeID) {
+				continue
+			}
+			if !nodeAvailableForAttachedVolume(node) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEBSVolumeAttachedToUnavailableNode)
+			}
+			if nodeNames == nil {
+				nodeNames = sets.New(node.Name)
+			} else {
+				// Two of the pod's volumes attached to different nodes leave an
+				// empty intersection; the caller rejects the pod.
+				nodeNames = nodeNames.Intersection(sets.New(node.Name))
+			}
+			break
+		}
+	}
+	return nodeNames, nil
+}
+
+// nodeHasEBSVolumeAttached reports whether the node's status lists the AWS EBS
+// volume as attached. Unique volume names take the form
+// "kubernetes.io/aws-ebs/<volumeID>" or "kubernetes.io/aws-ebs/aws://<zone>/<volumeID>".
+func nodeHasEBSVolumeAttached(node *v1.Node, volumeID string) bool {
+	for _, attached := range node.Status.VolumesAttached {
+		name := string(attached.Name)
+		if !strings.HasPrefix(name, ebsInTreePluginName+"/") {
+			continue
+		}
+		if suffix := strings.TrimPrefix(name, ebsInTreePluginName+"/"); suffix == volumeID || strings.HasSuffix(suffix, "/"+volumeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeAvailableForAttachedVolume reports whether a node holding a single-attach
+// volume can still accept the pod. A cordoned or not-ready node keeps the
+// volume attached while being unable to run the pod.
+func nodeAvailableForAttachedVolume(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && condition.Status != v1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// boundPVModesForPod resolves how the pod consumes the PersistentVolumes bound
+// to its claims, keyed by PV name. It returns an empty map if none of the pod's
+// claims are bound; unbound claims are left for the VolumeBinding plugin to resolve.
+func (pl *VolumeRestrictions) boundPVModesForPod(pod *v1.Pod) (map[string]pvModeUsage, *framework.Status) {
+	var modes map[string]pvModeUsage
+	for i := range pod.Spec.Volumes {
+		pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+		if pvcSource == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, pvcSource.ClaimName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		mode := volumeModeForClaim(pvc)
+		if pvc.Spec.VolumeMode == nil {
+			// An unset claim volume mode predates defaulting; fall back to the mode
+			// recorded on the bound PersistentVolume.
+			if pv, err := pl.pvLister.Get(pvc.Spec.VolumeName); err == nil && pv.Spec.VolumeMode != nil {
+				mode = *pv.Spec.VolumeMode
+			}
+		}
+		if modes == nil {
+			modes = make(map[string]pvModeUsage)
+		}
+		usage, ok := modes[pvc.Spec.VolumeName]
+		modes[pvc.Spec.VolumeName] = pvModeUsage{
+			mode:     mode,
+			readOnly: pvcSource.ReadOnly && (!ok || usage.readOnly),
+		}
+	}
+	return modes, nil
+}
+
+// csiBlockUsagesForPod resolves the pod's raw block mappings of CSI-backed
+// PersistentVolumes: claims with the Block volume mode bound to a
+// PersistentVolume with a CSI source. Unbound claims are left for the
+// VolumeBinding plugin to resolve.
+func (pl *VolumeRestrictions) csiBlockUsagesForPod(pod *v1.Pod) ([]csiBlockUsage, *framework.Status) {
+	var usages []csiBlockUsage
+	for i := range pod.Spec.Volumes {
+		pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+		if pvcSource == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, pvcSource.ClaimName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if usage, ok := pl.csiBlockUsageForClaim(pvc, pvcSource.ReadOnly); ok {
+			usages = append(usages, usage)
+		}
+	}
+	return usages, nil
+}
+
+// csiBlockUsageForClaim resolves a claim to a raw block mapping of a
+// CSI-backed PersistentVolume, or returns false for unbound claims,
+// filesystem-mode claims and claims bound to non-CSI volumes.
+func (pl *VolumeRestrictions) csiBlockUsageForClaim(pvc *v1.PersistentVolumeClaim, readOnly bool) (csiBlockUsage, bool) {
+	if pvc.Spec.VolumeName == "" || volumeModeForClaim(pvc) != v1.PersistentVolumeBlock {
+		return csiBlockUsage{}, false
+	}
+	pv, err := pl.pvLister.Get(pvc.Spec.VolumeName)
+	if err != nil || pv.Spec.CSI == nil {
+		return csiBlockUsage{}, false
+	}
+	return csiBlockUsage{csi: pv.Spec.CSI, readOnly: readOnly}, true
+}
+
+// satisfyCSIBlockVolumes checks the pod's raw block mappings of CSI-backed
+// PersistentVolumes against the pods already on the node. The driver and
+// volume handle identify the underlying device, so two block mappings of the
+// same handle map the same device even through different PersistentVolumes,
+// and may share it only if both map it read-only. Block mappings of the same
+// PersistentVolume are already covered by the volume mode checks; this check
+// adds the handle-level identity. Drivers without a CSIDriver object are
+// handled per the UnknownCSIDriverPolicy: Strict treats the device as
+// single-attach and rejects even read-only sharing, Permissive leaves it
+// unchecked.
+func (pl *VolumeRestrictions) satisfyCSIBlockVolumes(state *preFilterState, nodeInfo *framework.NodeInfo) *framework.Status {
+	if len(state.csiBlockVolumes) == 0 {
+		return nil
+	}
+	for _, podInfo := range nodeInfo.Pods {
+		existingPod := podInfo.Pod
+		for i := range existingPod.Spec.Volumes {
+			pvcSource := existingPod.Spec.Volumes[i].PersistentVolumeClaim
+			if pvcSource == nil {
+				continue
+			}
+			namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, existingPod.Namespace, pvcSource.ClaimName)
+			pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+			if err != nil {
+				// A claim of an existing pod that cannot be resolved cannot
+				// contribute a conflict.
+				continue
+			}
+			existing, ok := pl.csiBlockUsageForClaim(pvc, pvcSource.ReadOnly)
+			if !ok {
+				continue
+			}
+			for _, usage := range state.csiBlockVolumes {
+				if usage.csi.Driver != existing.csi.Driver || usage.csi.VolumeHandle != existing.csi.VolumeHandle {
+					continue
+				}
+				if !pl.csiDriverKnown(usage.csi.Driver) {
+					if pl.unknownCSIDriverPolicy == config.UnknownCSIDriverPermissive {
+						continue
+					}
+				} else if usage.readOnly && existing.readOnly {
+					continue
+				}
+				return framework.NewStatus(framework.Unschedulable, ErrReasonCSIBlockVolumeConflict)
+			}
+		}
+	}
+	return nil
+}
+
+// volumeModeForClaim returns the volume mode a claim provides its volume with,
+// defaulting to Filesystem when unset, matching the API default.
+func volumeModeForClaim(pvc *v1.PersistentVolumeClaim) v1.PersistentVolumeMode {
+	if pvc.Spec.VolumeMode != nil {
+		return *pvc.Spec.VolumeMode
+	}
+	return v1.PersistentVolumeFilesystem
+}
+
+// satisfyPVVolumeModes checks the pod's bound PersistentVolumes against the
+// claims of the pods already on the node:
+//   - A block-mode and a filesystem-mode claim for the same PersistentVolume is a
+//     misconfiguration that no other node can resolve.
+//   - Two consumers of the same block-mode PersistentVolume conflict unless all
+//     of their mounts are read-only.
+//   - Filesystem-mode consumers of the same PersistentVolume are left to the
+//     access mode checks.
+func (pl *VolumeRestrictions) satisfyPVVolumeModes(state *preFilterState, nodeInfo *framework.NodeInfo) *framework.Status {
+	if len(state.boundPVModes) == 0 {
+		return nil
+	}
+	for _, podInfo := range nodeInfo.Pods {
+		existingPod := podInfo.Pod
+		for i := range existingPod.Spec.Volumes {
+			pvcSource := existingPod.Spec.Volumes[i].PersistentVolumeClaim
+			if pvcSource == nil {
+				continue
+			}
+			namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, existingPod.Namespace, pvcSource.ClaimName)
+			pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+			if err != nil {
+				// A claim of an existing pod that cannot be resolved cannot
+				// contribute a conflict.
+				continue
+			}
+			if pvc.Spec.VolumeName == "" {
+				continue
+			}
+			usage, ok := state.boundPVModes[pvc.Spec.VolumeName]
+			if !ok {
+				continue
+			}
+			if existingMode := volumeModeForClaim(pvc); existingMode != usage.mode {
+				return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonVolumeModeConflict)
+			}
+			if usage.mode == v1.PersistentVolumeBlock && !(usage.readOnly && pvcSource.ReadOnly) {
+				return framework.NewStatus(framework.Unschedulable, ErrReasonBlockVolumeConflict)
+			}
+		}
+	}
+	return nil
+}
+
+// AddPod from pre-computed data in cycleState.
+func (pl *VolumeRestrictions) AddPod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	state, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if state.skipsPreFilterExtensions() {
+		return nil
+	}
+	state.updateWithPod(podInfoToAdd, 1)
+	state.updateNodeVolumes(nodeInfo, podInfoToAdd.Pod, 1)
+	return nil
+}
+
+// RemovePod from pre-computed data in cycleState.
+func (pl *VolumeRestrictions) RemovePod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
+	state, err := getPreFilterState(cycleState)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if state.skipsPreFilterExtensions() {
+		return nil
+	}
+	state.updateWithPod(podInfoToRemove, -1)
+	state.updateNodeVolumes(nodeInfo, podInfoToRemove.Pod, -1)
+	return nil
+}
+
+// skipsPreFilterExtensions reports whether the AddPod/RemovePod callbacks have
+// nothing to maintain for this cycle: the pod references no ReadWriteOncePod
+// claims, so there are no conflict counts to keep up to date, and no
+// incremental node volume indexes exist, so Filter rebuilds the identities
+// lazily from the nodeInfo it is handed, which during preemption simulation
+// already reflects the simulated change. The framework only skips the
+// callbacks for plugins whose PreFilterExtensions is nil regardless of the
+// pod, so the per-pod short-circuit lives in the callbacks themselves.
+func (s *preFilterState) skipsPreFilterExtensions() bool {
+	return len(s.readWriteOncePodPVCs) == 0 && s.nodeVolumes == nil
+}
+
+func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
+	c, err := cycleState.Read(preFilterStateKey)
+	if err != nil {
+		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
+		return nil, fmt.Errorf("cannot read %q from cycleState", preFilterStateKey)
+	}
+
+	s, ok := c.(*preFilterState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to volumerestrictions.state error", c)
+	}
+	return s, nil
+}
+
+// calPreFilterState computes preFilterState describing which PVCs use ReadWriteOncePod
+// and which pods in the cluster are in conflict.
+func (pl *VolumeRestrictions) calPreFilterState(ctx context.Context, pod *v1.Pod, pvcs sets.Set[string]) (*preFilterState, error) {
+	conflictingPVCRefCount := 0
+	for pvc := range pvcs {
+		if pl.sharedLister.StorageInfos().IsPVCUsedByPods(pvc) {
+			if pl.verifyReadWriteOncePodHolders && pl.staleReadWriteOncePodClaim(pvc) {
+				logger := klog.FromContext(ctx)
+				logger.Info("Warning: ReadWriteOncePod claim is reported in use but no holding pod exists anymore, ignoring the stale reference",
+					"pod", klog.KObj(pod), "persistentVolumeClaim", pvc)
+				continue
+			}
+			// There can only be at most one pod using the ReadWriteOncePod PVC.
+			conflictingPVCRefCount += 1
+			continue
+		}
+		if pl.assumedPodHoldsClaim(pvc) {
+			conflictingPVCRefCount += 1
+		}
+	}
+	return &preFilterState{
+		readWriteOncePodPVCs:   pvcs,
+		conflictingPVCRefCount: conflictingPVCRefCount,
+		crossNamespaceRefs:     pl.enableCrossNamespaceVolumeReferences,
+		ignoreTerminatingPods:  pl.ignoreTerminatingPods,
+		ignoreMirrorPods:       pl.ignoreMirrorPods,
+		flexIdentityKeys:       pl.flexVolumeIdentityKeys,
+	}, nil
+}
+
+// assumedPodHoldsClaim reports whether a pod the scheduler has assumed but not
+// yet bound references the claim. It closes the race window between two pods
+// contending for the same ReadWriteOncePod claim being scheduled back to back,
+// before the first one's binding reaches the snapshot. It always reports false
+// when the handle doesn't list assumed pods.
+func (pl *VolumeRestrictions) assumedPodHoldsClaim(claimKey string) bool {
+	if pl.assumedPodLister == nil {
+		return false
+	}
+	for _, assumed := range pl.assumedPodLister.AssumedPods() {
+		if pl.podReferencesClaim(assumed, claimKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// staleReadWriteOncePodClaim reports whether the claim's usage report is
+// stale: the snapshot names pods holding it, but none of them exists in the
+// informer cache anymore. When the holders cannot be determined or verified,
+// the report is trusted.
+func (pl *VolumeRestrictions) staleReadWriteOncePodClaim(claimKey string) bool {
+	nodeInfos, err := pl.sharedLister.NodeInfos().List()
+	if err != nil {
+		return false
+	}
+	holderFound := false
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			holder := podInfo.Pod
+			if !pl.podReferencesClaim(holder, claimKey) {
+				continue
+			}
+			holderFound = true
+			if _, err := pl.podLister.Pods(holder.Namespace).Get(holder.Name); err == nil || !apierrors.IsNotFound(err) {
+				// The holder still exists, or its existence cannot be verified.
+				return false
+			}
+		}
+	}
+	return holderFound
+}
+
+// podReferencesClaim reports whether one of the pod's volumes references the
+// claim with the given namespaced name, directly or through a generic
+// ephemeral volume.
+func (pl *VolumeRestrictions) podReferencesClaim(pod *v1.Pod, claimKey string) bool {
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+			if framework.GetNamespacedName(namespace, name) == claimKey {
+				return true
+			}
+		case volume.Ephemeral != nil:
+			if framework.GetNamespacedName(pod.Namespace, ephemeral.VolumeClaimName(pod, volume)) == claimKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pvcResourceVersionsForPod records the ResourceVersion of each claim the pod
+// references, keyed by namespaced claim name. Claims missing from the lister
+// are skipped; PreFilter has already rejected the pod if a claim it needs is
+// missing.
+func (pl *VolumeRestrictions) pvcResourceVersionsForPod(pod *v1.Pod) map[string]string {
+	var versions map[string]string
+	for i := range pod.Spec.Volumes {
+		pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+		if pvcSource == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, pvcSource.ClaimName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		if versions == nil {
+			versions = map[string]string{}
+		}
+		versions[framework.GetNamespacedName(namespace, name)] = pvc.ResourceVersion
+	}
+	return versions
+}
+
+// podPVCsMutatedSincePreFilter reports whether a claim the pod references has
+// changed since PreFilter recorded its ResourceVersion.
+func (pl *VolumeRestrictions) podPVCsMutatedSincePreFilter(state *preFilterState) bool {
+	for key, version := range state.pvcResourceVersions {
+		namespace, name, _ := strings.Cut(key, "/")
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil || pvc.ResourceVersion != version {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcilePVCMutations guards Filter against claims mutated between PreFilter
+// and Filter: when one of the pod's claims changed since PreFilter recorded
+// its ResourceVersion (a claim gaining the ReadWriteOncePod access mode, for
+// instance), the ReadWriteOncePod classification is recomputed so Filter does
+// not act on the stale one. The reconciliation runs at most once per
+// scheduling cycle; Filter runs for many nodes in parallel and each call waits
+// for it to complete before reading the state.
+func (pl *VolumeRestrictions) reconcilePVCMutations(ctx context.Context, pod *v1.Pod, state *preFilterState) *framework.Status {
+	if state.reconcileOnce == nil {
+		return nil
+	}
+	var status *framework.Status
+	state.reconcileOnce.Do(func() {
+		if !pl.podPVCsMutatedSincePreFilter(state) {
+			return
+		}
+		// Drop the cached classifications of the pod's claims so the recompute
+		// observes the lister's current objects rather than the cache.
+		if pl.rwopCache != nil {
+			for key := range state.pvcResourceVersions {
+				pl.rwopCache.invalidate(key)
+			}
+		}
+		pvcs, s := pl.readWriteOncePodPVCsForPod(ctx, pod)
+		if !s.IsSuccess() {
+			status = s
+			return
+		}
+		fresh, err := pl.calPreFilterState(ctx, pod, pvcs)
+		if err != nil {
+			status = framework.AsStatus(err)
+			return
+		}
+		state.readWriteOncePodPVCs = fresh.readWriteOncePodPVCs
+		state.conflictingPVCRefCount = fresh.conflictingPVCRefCount
+		state.pvcResourceVersions = pl.pvcResourceVersionsForPod(pod)
+	})
+	return status
+}
+
+// resolveClaimReference returns the namespace and name a pod volume's claim
+// reference points at. Claim references are same-namespace today; with
+// cross-namespace volume references enabled, a reference of the form
+// "namespace/name" points at a claim in another namespace, subject to a
+// reference grant checked at admission.
+func resolveClaimReference(crossNamespaceRefs bool, podNamespace, claimName string) (string, string) {
+	if crossNamespaceRefs {
+		if namespace, name, ok := strings.Cut(claimName, "/"); ok {
+			return namespace, name
+		}
+	}
+	return podNamespace, claimName
+}
+
+// featureGatesInfluencing returns the names of the feature gates whose gated
+// behavior changed how the pod's volumes were interpreted. Today the only
+// gated path that can alter a conflict decision is cross-namespace claim
+// resolution: with CrossNamespaceVolumeDataSource enabled, a claim reference
+// of the form "namespace/name" resolves to a claim in another namespace, so
+// any decision reached through such a reference is attributable to the gate.
+func (pl *VolumeRestrictions) featureGatesInfluencing(pod *v1.Pod) []string {
+	var gates []string
+	if pl.enableCrossNamespaceVolumeReferences {
+		for i := range pod.Spec.Volumes {
+			pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim
+			if pvcSource == nil {
+				continue
+			}
+			namespace, name := resolveClaimReference(true, pod.Namespace, pvcSource.ClaimName)
+			if namespace != pod.Namespace || name != pvcSource.ClaimName {
+				gates = append(gates, "CrossNamespaceVolumeDataSource")
+				break
+			}
+		}
+	}
+	return gates
+}
+
+// logGateAttribution records which feature gates influenced a rejection, so
+// operators can trace a decision that changed after toggling a gate back to
+// the gated path that produced it. It logs nothing when no gated path was
+// involved, which is the common case.
+func (pl *VolumeRestrictions) logGateAttribution(ctx context.Context, pod *v1.Pod, nodeInfo *framework.NodeInfo, reason string) {
+	gates := pl.featureGatesInfluencing(pod)
+	if len(gates) == 0 {
+		return
+	}
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Conflict decision was influenced by feature-gated behavior",
+		"pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.Node()), "reason", reason, "featureGates", gates)
+}
+
+// updateReadWriteOncePodGauges refreshes the waiting gauge from the plugin's
+// contention tracking. The gauge follows the set of pods whose last attempt
+// found a claim held, so repeated attempts of the same pod don't double-count.
+// The in-use gauge is deliberately not touched here: rebuilding it scans every
+// scheduled pod, which is too expensive per attempt, so it is refreshed on a
+// timer instead.
+func (pl *VolumeRestrictions) updateReadWriteOncePodGauges(pod *v1.Pod, state *preFilterState) {
+	podKey := framework.GetNamespacedName(pod.Namespace, pod.Name)
+	pl.contendingPodsLock.Lock()
+	if state.conflictingPVCRefCount > 0 {
+		pl.contendingPods.Insert(podKey)
+	} else {
+		pl.contendingPods.Delete(podKey)
+	}
+	metrics.PodsWaitingOnReadWriteOncePodPVCs.Set(float64(pl.contendingPods.Len()))
+	pl.contendingPodsLock.Unlock()
+}
+
+// readWriteOncePodGaugeRefreshInterval is how often the in-use gauge is
+// rebuilt. The gauge is for capacity dashboards, not alerting, so a refresh
+// well off the scheduling path is accurate enough.
+const readWriteOncePodGaugeRefreshInterval = 30 * time.Second
+
+// runReadWriteOncePodGaugeRefresh periodically rebuilds the in-use gauge and
+// prunes deleted pods from the waiting gauge until ctx is cancelled.
+func (pl *VolumeRestrictions) runReadWriteOncePodGaugeRefresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		pl.refreshReadWriteOncePodPVCsInUse()
+		pl.pruneDeletedContendingPods()
+	}
+}
+
+// pruneDeletedContendingPods drops waiting-gauge entries for pods that no
+// longer exist. A pod deleted while pending never re-runs PreFilter, so
+// without the pruning its entry would stay in contendingPods forever and the
+// waiting gauge would only ever grow.
+func (pl *VolumeRestrictions) pruneDeletedContendingPods() {
+	pl.contendingPodsLock.Lock()
+	defer pl.contendingPodsLock.Unlock()
+	for key := range pl.contendingPods {
+		namespace, name, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+		if _, err := pl.podLister.Pods(namespace).Get(name); apierrors.IsNotFound(err) {
+			pl.contendingPods.Delete(key)
+		}
+	}
+	metrics.PodsWaitingOnReadWriteOncePodPVCs.Set(float64(pl.contendingPods.Len()))
+}
+
+// refreshReadWriteOncePodPVCsInUse rebuilds the in-use gauge from the pod
+// informer, counting the distinct ReadWriteOncePod claims referenced by
+// scheduled pods.
+func (pl *VolumeRestrictions) refreshReadWriteOncePodPVCsInUse() {
+	pods, err := pl.podLister.List(labels.Everything())
+	if err != nil {
+		return
+	}
+	metrics.ReadWriteOncePodPVCsInUse.Set(float64(pl.countReadWriteOncePodPVCsInUse(pods)))
+}
+
+// countReadWriteOncePodPVCsInUse counts the distinct ReadWriteOncePod claims
+// referenced by the scheduled pods among the given pods. Claims shared by
+// several references are counted once.
+func (pl *VolumeRestrictions) countReadWriteOncePodPVCsInUse(pods []*v1.Pod) int {
+	inUse := sets.New[string]()
+	// isRWOP caches the access mode lookup per claim, since many pods may
+	// reference the same non-ReadWriteOncePod claim.
+	isRWOP := map[string]bool{}
+	for _, existing := range pods {
+		if existing.Spec.NodeName == "" {
+			continue
+		}
+		for _, volume := range existing.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, existing.Namespace, volume.PersistentVolumeClaim.ClaimName)
+			key := framework.GetNamespacedName(namespace, name)
+			rwop, checked := isRWOP[key]
+			if !checked {
+				pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+				rwop = err == nil && v1helper.ContainsAccessMode(pvc.Spec.AccessModes, v1.ReadWriteOncePod)
+				isRWOP[key] = rwop
+			}
+			if rwop {
+				inUse.Insert(key)
+			}
+		}
+	}
+	return inUse.Len()
+}
+
+// daemonSetSharedVolumeKeys returns the identities of the fixed-identity
+// volumes a DaemonSet-owned pod mounts read-write. Every replica of the
+// DaemonSet mounts the same underlying volumes on a different node, so any
+// such volume guarantees cross-node conflicts. It returns nil for pods not
+// owned by a DaemonSet.
+func daemonSetSharedVolumeKeys(pod *v1.Pod, legacyVolumeSources bool) []string {
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil || controllerRef.Kind != "DaemonSet" {
+		return nil
+	}
+	var keys []string
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		if !needsRestrictionsCheck(*volume, legacyVolumeSources) || volumeReadOnly(volume) {
+			continue
+		}
+		if key, ok := volumeIdentityKey(volume); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// hasCoReplicaPods returns whether another pod in the snapshot is controlled by
+// the same controller as the given pod, which indicates the controller manages
+// more than one replica.
+func hasCoReplicaPods(pod *v1.Pod, nodeInfos []*framework.NodeInfo) bool {
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil {
+		return false
+	}
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			other := podInfo.Pod
+			if other.UID == pod.UID || other.Namespace != pod.Namespace {
+				continue
+			}
+			otherRef := metav1.GetControllerOf(other)
+			if otherRef != nil && otherRef.UID == controllerRef.UID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (pl *VolumeRestrictions) readWriteOncePodPVCsForPod(ctx context.Context, pod *v1.Pod) (sets.Set[string], *framework.Status) {
+	var claims []podClaimReference
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claims = append(claims, podClaimReference{claimName: volume.PersistentVolumeClaim.ClaimName})
+		case volume.Ephemeral != nil:
+			// A generic ephemeral volume is backed by a claim named
+			// deterministically after the pod and volume.
+			claims = append(claims, podClaimReference{claimName: ephemeral.VolumeClaimName(pod, volume), forEphemeralVolume: true})
+		}
+	}
+
+	pvcs := sets.New[string]()
+	if len(claims) < parallelPVCLookupThreshold {
+		for _, claim := range claims {
+			name, status := pl.readWriteOncePodPVC(pod, claim)
+			if !status.IsSuccess() {
+				return nil, status
+			}
+			if name != "" {
+				pvcs.Insert(name)
+			}
+		}
+		return pvcs, nil
+	}
+
+	names := make([]string, len(claims))
+	statuses := make([]*framework.Status, len(claims))
+	pl.parallelizer.Until(ctx, len(claims), func(i int) {
+		names[i], statuses[i] = pl.readWriteOncePodPVC(pod, claims[i])
+	}, Name)
+	// Return the first failure in volume order so the result is deterministic
+	// and keeps the per-claim NotFound-vs-other status distinction.
+	for _, status := range statuses {
+		if !status.IsSuccess() {
+			return nil, status
+		}
+	}
+	for _, name := range names {
+		if name != "" {
+			pvcs.Insert(name)
+		}
+	}
+	return pvcs, nil
+}
+
+// podClaimReference identifies one claim a pod references, either directly or
+// through a generic ephemeral volume.
+type podClaimReference struct {
+	claimName string
+	// forEphemeralVolume is true when the claim backs a generic ephemeral
+	// volume of the pod. Such claims always live in the pod's namespace and
+	// must be owned by the pod.
+	forEphemeralVolume bool
+}
+
+// readWriteOncePodPVC fetches the referenced claim and returns its namespaced
+// name if the claim uses the ReadWriteOncePod access mode and is subject to
+// enforcement, or the empty string otherwise.
+func (pl *VolumeRestrictions) readWriteOncePodPVC(pod *v1.Pod, claim podClaimReference) (string, *framework.Status) {
+	namespace, name := pod.Namespace, claim.claimName
+	if !claim.forEphemeralVolume {
+		namespace, name = resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, claim.claimName)
+	}
+	key := framework.GetNamespacedName(namespace, name)
+	// Generic ephemeral claims must additionally be verified to be owned by
+	// the pod, which needs the object itself, so the cache serves only plain
+	// claim references.
+	if !claim.forEphemeralVolume && pl.rwopCache != nil {
+		if info, ok := pl.rwopCache.get(key); ok {
+			if pl.failOnMissingAccessModes && info.missingAccessModes {
+				return "", framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPVCMissingAccessModes)
+			}
+			if !info.hasReadWriteOncePod {
+				return "", nil
+			}
+			// The label selector and the opt-out annotation depend on mutable
+			// metadata, so claims subject to them are re-read.
+			if pl.readWriteOncePodSelector == nil && pl.allowSharedWritersAnnotation == "" {
+				return key, nil
+			}
+		}
+	}
+	pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+		}
+		return "", framework.AsStatus(err)
+	}
+	if claim.forEphemeralVolume {
+		if err := ephemeral.VolumeIsForPod(pod, pvc); err != nil {
+			return "", framework.AsStatus(err)
+		}
+	} else if pl.rwopCache != nil {
+		pl.rwopCache.set(key, rwopClaimInfo{
+			hasReadWriteOncePod: v1helper.ContainsAccessMode(pvc.Spec.AccessModes, v1.ReadWriteOncePod),
+			missingAccessModes:  len(pvc.Spec.AccessModes) == 0,
+		})
+	}
+
+	if pl.failOnMissingAccessModes && len(pvc.Spec.AccessModes) == 0 {
+		// A claim without access modes is malformed; by default it is treated as
+		// non-ReadWriteOncePod, strict mode surfaces the misconfiguration instead.
+		return "", framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPVCMissingAccessModes)
+	}
+
+	if !v1helper.ContainsAccessMode(pvc.Spec.AccessModes, v1.ReadWriteOncePod) {
+		return "", nil
+	}
+	if pl.readWriteOncePodSelector != nil && !pl.readWriteOncePodSelector.Matches(labels.Set(pvc.Labels)) {
+		// Enforcement is being rolled out gradually and this claim is not opted in yet.
+		return "", nil
+	}
+	if pl.allowSharedWritersAnnotation != "" && pvc.Annotations[pl.allowSharedWritersAnnotation] == "true" {
+		// The claim explicitly opts out of single-writer enforcement; a
+		// cluster-aware filesystem coordinates the writers itself.
+		return "", nil
+	}
+	return framework.GetNamespacedName(namespace, name), nil
+}
+
+// readWriteOncePVCNodesForPod returns, for each bound ReadWriteOnce claim the
+// pod references, the node on which the claim is already in use by a scheduled
+// pod, keyed by namespaced claim name. Claims not in use on any node are
+// absent. It returns nil unless EnforceReadWriteOncePerNode is enabled.
+func (pl *VolumeRestrictions) readWriteOncePVCNodesForPod(pod *v1.Pod) (map[string]string, *framework.Status) {
+	if !pl.enforceReadWriteOncePerNode {
+		return nil, nil
+	}
+	claims := sets.New[string]()
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+			}
+			return nil, framework.AsStatus(err)
+		}
+		if pvc.Status.Phase != v1.ClaimBound {
+			// Unbound claims are left for the VolumeBinding plugin to resolve.
+			continue
 		}
-	}
-
-	pvcs, err := pl.readWriteOncePodPVCsForPod(ctx, pod)
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+		if !v1helper.ContainsAccessMode(pvc.Spec.AccessModes, v1.ReadWriteOnce) {
+			continue
 		}
-		return nil, framework.AsStatus(err)
+		claims.Insert(framework.GetNamespacedName(namespace, name))
 	}
-
-	s, err := pl.calPreFilterState(ctx, pod, pvcs)
+	if claims.Len() == 0 {
+		return nil, nil
+	}
+	nodeInfos, err := pl.sharedLister.NodeInfos().List()
 	if err != nil {
-		return nil, framework.AsStatus(err)
+		return nil, framework.AsStatus(fmt.Errorf("listing NodeInfos: %w", err))
 	}
-
-	if !needsCheck && s.conflictingPVCRefCount == 0 {
-		return nil, framework.NewStatus(framework.Skip)
+	nodes := map[string]string{}
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if node == nil {
+			continue
+		}
+		for _, podInfo := range nodeInfo.Pods {
+			existing := podInfo.Pod
+			for _, volume := range existing.Spec.Volumes {
+				if volume.PersistentVolumeClaim == nil {
+					continue
+				}
+				namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, existing.Namespace, volume.PersistentVolumeClaim.ClaimName)
+				if key := framework.GetNamespacedName(namespace, name); claims.Has(key) {
+					nodes[key] = node.Name
+				}
+			}
+		}
 	}
-	cycleState.Write(preFilterStateKey, s)
-	return nil, nil
+	return nodes, nil
 }
 
-// AddPod from pre-computed data in cycleState.
-func (pl *VolumeRestrictions) AddPod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToAdd *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
-	state, err := getPreFilterState(cycleState)
-	if err != nil {
-		return framework.AsStatus(err)
+// readWriteOncePerNodeNodeNames computes the nodes the pod is pinned to by the
+// bound ReadWriteOnce claims it references that are already in use: each such
+// claim restricts the pod to the node using it, so handing the framework the
+// narrowed candidate set skips filtering every other node. It returns nil when
+// no claim pins the pod, in which case all nodes remain candidates, and an
+// error status when two claims are in use on different nodes, which no node
+// can ever satisfy.
+func readWriteOncePerNodeNodeNames(pvcNodes map[string]string) (sets.Set[string], *framework.Status) {
+	if len(pvcNodes) == 0 {
+		return nil, nil
 	}
-	state.updateWithPod(podInfoToAdd, 1)
-	return nil
+	nodeNames := sets.New[string]()
+	for _, nodeName := range pvcNodes {
+		nodeNames.Insert(nodeName)
+	}
+	if nodeNames.Len() > 1 {
+		return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonReadWriteOnceNodeConflict,
+			"the ReadWriteOnce PersistentVolumeClaims used by the pod are in use on different nodes")
+	}
+	return nodeNames, nil
 }
 
-// RemovePod from pre-computed data in cycleState.
-func (pl *VolumeRestrictions) RemovePod(ctx context.Context, cycleState *framework.CycleState, podToSchedule *v1.Pod, podInfoToRemove *framework.PodInfo, nodeInfo *framework.NodeInfo) *framework.Status {
-	state, err := getPreFilterState(cycleState)
-	if err != nil {
-		return framework.AsStatus(err)
+// satisfyReadWriteOncePerNode checks that the node is the one already using
+// each ReadWriteOnce claim the pod references that is in use somewhere. A
+// claim in use on another node rejects the node; the conflict clears when the
+// using pod goes away, so the status stays Unschedulable.
+func satisfyReadWriteOncePerNode(state *preFilterState, nodeInfo *framework.NodeInfo) *framework.Status {
+	if len(state.readWriteOncePVCNodes) == 0 {
+		return nil
+	}
+	node := nodeInfo.Node()
+	for key, nodeName := range state.readWriteOncePVCNodes {
+		if nodeName != node.Name {
+			return framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOnceNodeConflict,
+				fmt.Sprintf("PersistentVolumeClaim %s is in use on node %s", key, nodeName))
+		}
 	}
-	state.updateWithPod(podInfoToRemove, -1)
 	return nil
 }
 
-func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, error) {
-	c, err := cycleState.Read(preFilterStateKey)
-	if err != nil {
-		// preFilterState doesn't exist, likely PreFilter wasn't invoked.
-		return nil, fmt.Errorf("cannot read %q from cycleState", preFilterStateKey)
-	}
+// Checks if scheduling the pod onto this node would cause any conflicts with
+// existing volumes.
+func satisfyVolumeConflicts(pod *v1.Pod, nodeInfo *framework.NodeInfo, legacyVolumeSources bool) bool {
+	return satisfyVolumeConflictsWithIdentities(pod, volumeIdentitiesForNode(nodeInfo, false, false, nil), legacyVolumeSources)
+}
 
-	s, ok := c.(*preFilterState)
-	if !ok {
-		return nil, fmt.Errorf("%+v convert to volumerestrictions.state error", c)
-	}
-	return s, nil
+// Checks if scheduling the pod onto the node holding the given distinct volume
+// identities would cause any conflicts with existing volumes.
+func satisfyVolumeConflictsWithIdentities(pod *v1.Pod, volumes *nodeVolumeIdentities, legacyVolumeSources bool) bool {
+	return firstVolumeConflict(pod, volumes, legacyVolumeSources) == nil
 }
 
-// calPreFilterState computes preFilterState describing which PVCs use ReadWriteOncePod
-// and which pods in the cluster are in conflict.
-func (pl *VolumeRestrictions) calPreFilterState(ctx context.Context, pod *v1.Pod, pvcs sets.Set[string]) (*preFilterState, error) {
-	conflictingPVCRefCount := 0
-	for pvc := range pvcs {
-		key := framework.GetNamespacedName(pod.Namespace, pvc)
-		if pl.sharedLister.StorageInfos().IsPVCUsedByPods(key) {
-			// There can only be at most one pod using the ReadWriteOncePod PVC.
-			conflictingPVCRefCount += 1
+// firstVolumeConflict returns the first of the pod's volumes that conflicts with
+// the node's distinct volume identities, or nil if there is no conflict.
+func firstVolumeConflict(pod *v1.Pod, volumes *nodeVolumeIdentities, legacyVolumeSources bool) *v1.Volume {
+	for i := range pod.Spec.Volumes {
+		v := &pod.Spec.Volumes[i]
+		if !needsRestrictionsCheck(*v, legacyVolumeSources) {
+			continue
+		}
+		if volumes.conflicts(v) {
+			return v
 		}
 	}
-	return &preFilterState{
-		readWriteOncePodPVCs:   pvcs,
-		conflictingPVCRefCount: conflictingPVCRefCount,
-	}, nil
+	return nil
 }
 
-func (pl *VolumeRestrictions) readWriteOncePodPVCsForPod(ctx context.Context, pod *v1.Pod) (sets.Set[string], error) {
-	pvcs := sets.New[string]()
-	for _, volume := range pod.Spec.Volumes {
-		if volume.PersistentVolumeClaim == nil {
+// firstVolumeConflictWithPolicy is firstVolumeConflict with the plugin
+// configuration applied: volumes of disabled conflict categories are skipped,
+// and the conflict policy, when configured, is consulted per volume before
+// the built-in rules. A policy that defers leaves the decision to the
+// built-in rules.
+func (pl *VolumeRestrictions) firstVolumeConflictWithPolicy(pod *v1.Pod, volumes *nodeVolumeIdentities, nodeInfo *framework.NodeInfo) *v1.Volume {
+	for i := range pod.Spec.Volumes {
+		v := &pod.Spec.Volumes[i]
+		if !needsRestrictionsCheck(*v, pl.legacyVolumeSources) || !pl.volumeCheckEnabled(v) {
 			continue
 		}
-
-		pvc, err := pl.pvcLister.PersistentVolumeClaims(pod.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
-		if err != nil {
-			return nil, err
+		if pl.conflictPolicy != nil {
+			switch pl.conflictPolicy(pod, v, nodeInfo) {
+			case ConflictPolicyAllow:
+				continue
+			case ConflictPolicyDeny:
+				return v
+			}
 		}
-
-		if !v1helper.ContainsAccessMode(pvc.Spec.AccessModes, v1.ReadWriteOncePod) {
-			continue
+		if volumes.conflicts(v) {
+			return v
 		}
-		pvcs.Insert(pvc.Name)
 	}
-	return pvcs, nil
+	return nil
 }
 
-// Checks if scheduling the pod onto this node would cause any conflicts with
-// existing volumes.
-func satisfyVolumeConflicts(pod *v1.Pod, nodeInfo *framework.NodeInfo) bool {
-	for i := range pod.Spec.Volumes {
-		v := pod.Spec.Volumes[i]
-		if !needsRestrictionsCheck(v) {
-			continue
+// conflictLayerReason returns a reason naming the layer the conflicting volume
+// conflicts at, to guide remediation. AWS EBS devices are single-attach when
+// mounted read-write, so their conflicts are at the attach layer; the other
+// restriction-relevant volumes can be shared as long as every mount is
+// read-only, so their conflicts are at the mount layer.
+func conflictLayerReason(volume *v1.Volume) string {
+	if volume.AWSElasticBlockStore != nil {
+		return ErrReasonAttachLayerConflict
+	}
+	return ErrReasonMountLayerConflict
+}
+
+// diskConflictHolderReasons returns structured reasons naming the conflicting
+// volume's source kind and identity and the pods on the node holding it. They
+// accompany the flat conflict message in the returned status, and through it
+// the scheduler's per-node diagnosis, so events and kubectl output can name
+// the holder.
+func diskConflictHolderReasons(volume *v1.Volume, nodeInfo *framework.NodeInfo) []string {
+	key, ok := volumeIdentityKey(volume)
+	if !ok {
+		return nil
+	}
+	kind := conflictCategoryNames[conflictCategoryForVolume(volume)]
+	var reasons []string
+	for _, podInfo := range nodeInfo.Pods {
+		existing := podInfo.Pod
+		for i := range existing.Spec.Volumes {
+			if existingKey, ok := volumeIdentityKey(&existing.Spec.Volumes[i]); ok && existingKey == key {
+				reasons = append(reasons, fmt.Sprintf("%s volume %s is held by pod %s", kind, key,
+					framework.GetNamespacedName(existing.Namespace, existing.Name)))
+				break
+			}
 		}
-		for _, ev := range nodeInfo.Pods {
-			if isVolumeConflict(&v, ev.Pod) {
-				return false
+	}
+	return reasons
+}
+
+// readWriteOncePodHolderReasons returns structured reasons naming the
+// ReadWriteOncePod claims in conflict and the pods on the node holding them,
+// for the scheduler's per-node diagnosis.
+func readWriteOncePodHolderReasons(pod *v1.Pod, state *preFilterState, nodeInfo *framework.NodeInfo) []string {
+	var reasons []string
+	for _, podInfo := range nodeInfo.Pods {
+		existing := podInfo.Pod
+		for _, volume := range existing.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
 			}
+			namespace, name := resolveClaimReference(state.crossNamespaceRefs, existing.Namespace, volume.PersistentVolumeClaim.ClaimName)
+			key := framework.GetNamespacedName(namespace, name)
+			if !state.readWriteOncePodPVCs.Has(key) {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("PersistentVolumeClaim %s is held by pod %s",
+				key, framework.GetNamespacedName(existing.Namespace, existing.Name)))
 		}
 	}
-	return true
+	return reasons
 }
 
 // Checks if scheduling the pod would cause any ReadWriteOncePod PVC access mode conflicts.
-func satisfyReadWriteOncePod(ctx context.Context, state *preFilterState) *framework.Status {
+func satisfyReadWriteOncePod(ctx context.Context, state *preFilterState, code framework.Code) *framework.Status {
 	if state == nil {
 		return nil
 	}
 	if state.conflictingPVCRefCount > 0 {
-		return framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict)
+		return framework.NewStatus(code, ErrReasonReadWriteOncePodConflict)
 	}
 	return nil
 }
@@ -299,22 +2601,166 @@ func (pl *VolumeRestrictions) PreFilterExtensions() framework.PreFilterExtension
 // It evaluates if a pod can fit due to the volumes it requests, and those that
 // are already mounted. If there is already a volume mounted on that node, another pod that uses the same volume
 // can't be scheduled there.
-// This is GCE, Amazon EBS, ISCSI and Ceph RBD specific for now:
+// This is GCE, Amazon EBS, ISCSI, Ceph RBD and FibreChannel specific for now:
 // - GCE PD allows multiple mounts as long as they're all read-only
-// - AWS EBS forbids any two pods mounting the same volume ID
+// - AWS EBS allows multiple mounts as long as they're all read-only
 // - Ceph RBD forbids if any two pods share at least same monitor, and match pool and image, and the image is read-only
 // - ISCSI forbids if any two pods share at least same IQN and ISCSI volume is read-only
+// - FibreChannel forbids if any two pods share a target WWN and LUN or a WWID, unless both mounts are read-only
+// - vSphere forbids if any two pods share a VolumePath, as VMDKs are single-writer
+// - Cinder allows multiple mounts as long as they're all read-only
+// - AzureDisk allows multiple mounts if they're all read-only or the disk is in shared mode
+// - ScaleIO and StorageOS volumes, checked only when LegacyVolumeSources is set, allow multiple mounts as long as they're all read-only
 // If the pod uses PVCs with the ReadWriteOncePod access mode, it evaluates if
 // these PVCs are already in-use and if preemption will help.
+// If EnforceReadWriteOncePerNode is enabled, bound ReadWriteOnce claims
+// already in use restrict the pod to the node using them.
+// Finally, it checks the volume mode of the pod's bound PersistentVolumes against
+// the claims of the pods already on the node, and that no pod on the node
+// block-maps the same CSI volume handle read-write.
 func (pl *VolumeRestrictions) Filter(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
-	if !satisfyVolumeConflicts(pod, nodeInfo) {
-		return framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict)
-	}
 	state, err := getPreFilterState(cycleState)
 	if err != nil {
 		return framework.AsStatus(err)
 	}
-	return satisfyReadWriteOncePod(ctx, state)
+	if status := pl.reconcilePVCMutations(ctx, pod, state); status != nil {
+		return status
+	}
+	switch pl.diskConflictMode {
+	case config.DiskConflictOff:
+		// Disk conflict checking is disabled.
+	case config.DiskConflictWarn:
+		if pl.firstVolumeConflictWithPolicy(pod, state.volumeIdentitiesOnNode(nodeInfo), nodeInfo) != nil {
+			metrics.DiskConflictsAudited.Inc()
+			logger := klog.FromContext(ctx)
+			logger.V(2).Info("Pod would have been rejected due to a disk conflict, but DiskConflictMode is Warn",
+				"pod", klog.KObj(pod), "node", klog.KObj(nodeInfo.Node()))
+		}
+	default:
+		if conflictingVolume := pl.firstVolumeConflictWithPolicy(pod, state.volumeIdentitiesOnNode(nodeInfo), nodeInfo); conflictingVolume != nil {
+			category := conflictCategoryForVolume(conflictingVolume)
+			state.recordConflict(category)
+			metrics.ConflictRejections.WithLabelValues(conflictCategoryNames[category]).Inc()
+			pl.recordRejection(pod, nodeInfo, ErrReasonDiskConflict)
+			pl.logGateAttribution(ctx, pod, nodeInfo, ErrReasonDiskConflict)
+			reasons := append([]string{ErrReasonDiskConflict, conflictLayerReason(conflictingVolume)},
+				diskConflictHolderReasons(conflictingVolume, nodeInfo)...)
+			pl.recordConflictEvent(pod, reasons...)
+			return framework.NewStatus(pl.conflictStatusCodes[category], reasons...)
+		}
+	}
+	if status := satisfyReadWriteOncePod(ctx, state, pl.conflictStatusCodes[conflictCategoryReadWriteOncePod]); status != nil {
+		state.recordConflict(conflictCategoryReadWriteOncePod)
+		metrics.ConflictRejections.WithLabelValues(conflictCategoryNames[conflictCategoryReadWriteOncePod]).Inc()
+		pl.recordRejection(pod, nodeInfo, ErrReasonReadWriteOncePodConflict)
+		pl.logGateAttribution(ctx, pod, nodeInfo, ErrReasonReadWriteOncePodConflict)
+		for _, reason := range readWriteOncePodHolderReasons(pod, state, nodeInfo) {
+			status.AppendReason(reason)
+		}
+		pl.recordConflictEvent(pod, status.Reasons()...)
+		return status
+	}
+	if status := satisfyReadWriteOncePerNode(state, nodeInfo); status != nil {
+		pl.recordRejection(pod, nodeInfo, ErrReasonReadWriteOnceNodeConflict)
+		pl.logGateAttribution(ctx, pod, nodeInfo, ErrReasonReadWriteOnceNodeConflict)
+		return status
+	}
+	if status := pl.satisfyPVVolumeModes(state, nodeInfo); status != nil {
+		pl.recordRejection(pod, nodeInfo, status.Message())
+		pl.logGateAttribution(ctx, pod, nodeInfo, status.Message())
+		return status
+	}
+	if status := pl.satisfyCSIBlockVolumes(state, nodeInfo); status != nil {
+		pl.recordRejection(pod, nodeInfo, status.Message())
+		pl.logGateAttribution(ctx, pod, nodeInfo, status.Message())
+		return status
+	}
+	return nil
+}
+
+// ConflictReasonCounts returns, per conflict category, the number of nodes the
+// pod failed the Filter on during the scheduling cycle, for use in the
+// scheduler's failure summary. It returns nil when conflict reason aggregation
+// is disabled or PreFilter was not invoked.
+func (pl *VolumeRestrictions) ConflictReasonCounts(cycleState *framework.CycleState) map[string]int64 {
+	state, err := getPreFilterState(cycleState)
+	if err != nil || state.conflictReasons == nil {
+		return nil
+	}
+	return state.conflictReasons.countsByCategory()
+}
+
+// Score favors nodes that already hold read-only mounts of the fixed-identity
+// volumes the pod mounts read-only, since such volumes can be shared in place
+// without another attach. Only read-only mounts count: a read-write mount of a
+// volume present on the node conflicts outright and is handled by Filter. The
+// score is the fraction of the pod's read-only restriction-relevant volumes
+// already served read-only by the node, scaled by ScoreWeight; weights above
+// one amplify the signal, saturating at the maximum node score.
+func (pl *VolumeRestrictions) Score(ctx context.Context, cycleState *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	if pl.scoreWeight == 0 || pl.diskConflictMode == config.DiskConflictOff {
+		return 0, nil
+	}
+	var readOnlyVolumes []*v1.Volume
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		if needsRestrictionsCheck(*volume, pl.legacyVolumeSources) && pl.volumeCheckEnabled(volume) && volumeReadOnly(volume) {
+			readOnlyVolumes = append(readOnlyVolumes, volume)
+		}
+	}
+	if len(readOnlyVolumes) == 0 {
+		return 0, nil
+	}
+	nodeInfo, err := pl.sharedLister.NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+	}
+	identities := volumeIdentitiesForNode(nodeInfo, pl.ignoreTerminatingPods, pl.ignoreMirrorPods, pl.flexVolumeIdentityKeys)
+	matched := 0
+	for _, volume := range readOnlyVolumes {
+		key, ok := volumeIdentityKey(volume)
+		if !ok {
+			continue
+		}
+		if entry, ok := identities.identities[key]; ok && entry.allReadOnly() {
+			matched++
+		}
+	}
+	score := int64(matched) * framework.MaxNodeScore / int64(len(readOnlyVolumes))
+	score *= int64(pl.scoreWeight)
+	if score > framework.MaxNodeScore {
+		score = framework.MaxNodeScore
+	}
+	return score, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (pl *VolumeRestrictions) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// volumeIdentitiesOnNode returns the node's distinct volume identities maintained
+// while simulating pod changes, or builds them from nodeInfo if they haven't been
+// computed. Built indexes are memoized in sharedNodeVolumes, so evaluating the
+// same node again within the cycle is a map lookup instead of a rebuild; two
+// parallel evaluations racing on the first build at worst build it twice.
+func (s *preFilterState) volumeIdentitiesOnNode(nodeInfo *framework.NodeInfo) *nodeVolumeIdentities {
+	node := nodeInfo.Node()
+	if node != nil {
+		if volumes, ok := s.nodeVolumes[node.Name]; ok {
+			return volumes
+		}
+		if s.sharedNodeVolumes != nil {
+			if cached, ok := s.sharedNodeVolumes.Load(node.Name); ok {
+				return cached.(*nodeVolumeIdentities)
+			}
+		}
+	}
+	volumes := volumeIdentitiesForNode(nodeInfo, s.ignoreTerminatingPods, s.ignoreMirrorPods, s.flexIdentityKeys)
+	if node != nil && s.sharedNodeVolumes != nil {
+		s.sharedNodeVolumes.Store(node.Name, volumes)
+	}
+	return volumes
 }
 
 // EventsToRegister returns the possible events that may make a Pod
@@ -339,21 +2785,163 @@ func (pl *VolumeRestrictions) EventsToRegister(_ context.Context) ([]framework.C
 		// We intentionally don't set QueueingHint since all Node/Add events could make Pods schedulable.
 		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: nodeActionType}},
 		// Pods may fail to schedule because the PVC it uses has not yet been created.
-		// This PVC is required to exist to check its access modes.
-		{Event: framework.ClusterEvent{Resource: framework.PersistentVolumeClaim, ActionType: framework.Add},
-			QueueingHintFn: pl.isSchedulableAfterPersistentVolumeClaimAdded},
+		// This PVC is required to exist to check its access modes. Update and delete
+		// events additionally invalidate the plugin's cached per-claim access mode
+		// classifications.
+		{Event: framework.ClusterEvent{Resource: framework.PersistentVolumeClaim, ActionType: framework.Add | framework.Update | framework.Delete},
+			QueueingHintFn: pl.isSchedulableAfterPersistentVolumeClaimChange},
+		// A statically provisioned PV bound to one of the pod's claims may be deleted
+		// and recreated, which can change the restrictions the pod was rejected on.
+		{Event: framework.ClusterEvent{Resource: framework.PersistentVolume, ActionType: framework.Delete},
+			QueueingHintFn: pl.isSchedulableAfterPersistentVolumeDeleted},
+		// Editing a pre-provisioned PV's access modes changes the restrictions the
+		// pod was rejected on without any event on the claim itself.
+		{Event: framework.ClusterEvent{Resource: framework.PersistentVolume, ActionType: framework.Add | framework.Update},
+			QueueingHintFn: pl.isSchedulableAfterPersistentVolumeChange},
+		// A node holding one of the pod's single-attach volumes becoming Ready
+		// makes that node placeable again, which is the only node the pod can run on.
+		{Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.UpdateNodeCondition},
+			QueueingHintFn: pl.isSchedulableAfterNodeBecameReady},
+		// A pod rejected because one of its CSI volumes had no CSIDriver object
+		// may become schedulable once the object is created, and a changed attach
+		// policy can change how the driver's volumes may be shared.
+		{Event: framework.ClusterEvent{Resource: framework.CSIDriver, ActionType: framework.Add | framework.Update},
+			QueueingHintFn: pl.isSchedulableAfterCSIDriverChange},
 	}, nil
 }
 
-// isSchedulableAfterPersistentVolumeClaimAdded is invoked whenever a PersistentVolumeClaim added or changed, It checks whether
-// that change made a previously unschedulable pod schedulable.
-func (pl *VolumeRestrictions) isSchedulableAfterPersistentVolumeClaimAdded(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
-	_, newPersistentVolumeClaim, err := util.As[*v1.PersistentVolumeClaim](oldObj, newObj)
+// isSchedulableAfterNodeBecameReady is invoked whenever a node's conditions change.
+// A pod mounting a single-attach volume that is stuck attached to a NotReady node
+// cannot run anywhere; the node transitioning back to Ready may make the pod
+// schedulable. It is scoped to nodes actually holding one of the pod's volumes
+// to avoid requeueing on unrelated readiness churn.
+func (pl *VolumeRestrictions) isSchedulableAfterNodeBecameReady(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	oldNode, newNode, err := util.As[*v1.Node](oldObj, newObj)
+	if err != nil {
+		return framework.Queue, fmt.Errorf("unexpected objects in isSchedulableAfterNodeBecameReady: %w", err)
+	}
+
+	if oldNode == nil || nodeIsReady(oldNode) || !nodeIsReady(newNode) {
+		logger.V(5).Info("node condition update is not a NotReady to Ready transition, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "node", klog.KObj(newNode))
+		return framework.QueueSkip, nil
+	}
+
+	for i := range pod.Spec.Volumes {
+		ebs := pod.Spec.Volumes[i].AWSElasticBlockStore
+		if ebs == nil {
+			continue
+		}
+		if nodeHasEBSVolumeAttached(newNode, ebs.VolumeID) {
+			logger.V(5).Info("node holding a single-attach volume used by the pod became ready, which might make this pod schedulable", "pod", klog.KObj(pod), "node", klog.KObj(newNode))
+			return framework.Queue, nil
+		}
+	}
+	logger.V(5).Info("node became ready but holds none of the pod's single-attach volumes, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "node", klog.KObj(newNode))
+	return framework.QueueSkip, nil
+}
+
+// nodeIsReady reports whether the node's Ready condition is true.
+func nodeIsReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podCSIDriverNames returns the names of the CSI drivers backing the pod's
+// volumes: the drivers of inline CSI volumes and the drivers of the
+// PersistentVolumes bound to the pod's claims. Claims that are unbound or
+// cannot be resolved are skipped; the claim events requeue the pod when those
+// change. Claim resolutions are shared between the evaluations the event
+// triggers, since many waiting pods may reference the same claims.
+func (pl *VolumeRestrictions) podCSIDriverNames(eventObj metav1.Object, pod *v1.Pod) sets.Set[string] {
+	drivers := sets.New[string]()
+	for i := range pod.Spec.Volumes {
+		volume := &pod.Spec.Volumes[i]
+		if volume.CSI != nil {
+			drivers.Insert(volume.CSI.Driver)
+		}
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		claimKey := framework.GetNamespacedName(namespace, name)
+		driver := pl.cachedEventLookup(eventObj, "claimDriver/"+claimKey, func() string {
+			pvc, err := pl.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+			if err != nil || pvc.Spec.VolumeName == "" {
+				return ""
+			}
+			pv, err := pl.pvLister.Get(pvc.Spec.VolumeName)
+			if err != nil || pv.Spec.CSI == nil {
+				return ""
+			}
+			return pv.Spec.CSI.Driver
+		})
+		if driver != "" {
+			drivers.Insert(driver)
+		}
+	}
+	return drivers
+}
+
+// attachPolicyEqual reports whether two CSIDriver objects declare the same
+// attach policy. An unset AttachRequired defaults to true.
+func attachPolicyEqual(a, b *storagev1.CSIDriver) bool {
+	return ptr.Deref(a.Spec.AttachRequired, true) == ptr.Deref(b.Spec.AttachRequired, true)
+}
+
+// isSchedulableAfterCSIDriverChange is invoked whenever a CSIDriver object is
+// created or updated. A pod whose CSI-backed volume could not be evaluated
+// because the driver had no CSIDriver object may become schedulable once the
+// object appears, and a changed attach policy changes how the driver's volumes
+// are shared. The hint is scoped to drivers actually backing one of the pod's
+// volumes, so unrelated driver churn doesn't requeue every pending pod.
+func (pl *VolumeRestrictions) isSchedulableAfterCSIDriverChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	originalCSIDriver, modifiedCSIDriver, err := util.As[*storagev1.CSIDriver](oldObj, newObj)
+	if err != nil {
+		return framework.Queue, fmt.Errorf("unexpected objects in isSchedulableAfterCSIDriverChange: %w", err)
+	}
+
+	if !pl.podCSIDriverNames(modifiedCSIDriver, pod).Has(modifiedCSIDriver.Name) {
+		logger.V(5).Info("CSIDriver doesn't back any of the pod's volumes, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "CSIDriver", klog.KObj(modifiedCSIDriver))
+		return framework.QueueSkip, nil
+	}
+	if originalCSIDriver == nil {
+		logger.V(5).Info("CSIDriver backing one of the pod's volumes was created, which might make this pod schedulable", "pod", klog.KObj(pod), "CSIDriver", klog.KObj(modifiedCSIDriver))
+		return framework.Queue, nil
+	}
+	if !attachPolicyEqual(originalCSIDriver, modifiedCSIDriver) {
+		logger.V(5).Info("attach policy of a CSIDriver backing one of the pod's volumes changed, which might make this pod schedulable", "pod", klog.KObj(pod), "CSIDriver", klog.KObj(modifiedCSIDriver))
+		return framework.Queue, nil
+	}
+	logger.V(5).Info("CSIDriver update doesn't change the attach policy, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "CSIDriver", klog.KObj(modifiedCSIDriver))
+	return framework.QueueSkip, nil
+}
+
+// isSchedulableAfterPersistentVolumeClaimChange is invoked whenever a PersistentVolumeClaim is added,
+// updated or deleted. It drops the claim's cached access mode classification on updates and deletes,
+// and checks whether the change made a previously unschedulable pod schedulable.
+func (pl *VolumeRestrictions) isSchedulableAfterPersistentVolumeClaimChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	oldPersistentVolumeClaim, newPersistentVolumeClaim, err := util.As[*v1.PersistentVolumeClaim](oldObj, newObj)
 	if err != nil {
 		return framework.Queue, fmt.Errorf("unexpected objects in isSchedulableAfterPersistentVolumeClaimChange: %w", err)
 	}
 
-	if newPersistentVolumeClaim.Namespace != pod.Namespace {
+	if oldPersistentVolumeClaim != nil && pl.rwopCache != nil {
+		// The claim changed or went away; its cached classification is stale.
+		pl.rwopCache.invalidate(framework.GetNamespacedName(oldPersistentVolumeClaim.Namespace, oldPersistentVolumeClaim.Name))
+	}
+	if newPersistentVolumeClaim == nil {
+		// A deleted claim cannot make the pod schedulable.
+		return framework.QueueSkip, nil
+	}
+
+	// Without cross-namespace references a pod can only refer to claims in its
+	// own namespace; with them, a "namespace/name" reference may point at the
+	// changed claim, so the references have to be resolved first.
+	if !pl.enableCrossNamespaceVolumeReferences && newPersistentVolumeClaim.Namespace != pod.Namespace {
 		return framework.QueueSkip, nil
 	}
 
@@ -362,15 +2950,103 @@ func (pl *VolumeRestrictions) isSchedulableAfterPersistentVolumeClaimAdded(logge
 			continue
 		}
 
-		if volume.PersistentVolumeClaim.ClaimName == newPersistentVolumeClaim.Name {
-			logger.V(5).Info("PVC that is referred from the pod was created, which might make this pod schedulable", "pod", klog.KObj(pod), "PVC", klog.KObj(newPersistentVolumeClaim))
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		if namespace == newPersistentVolumeClaim.Namespace && name == newPersistentVolumeClaim.Name {
+			logger.V(5).Info("PVC that is referred from the pod was created or updated, which might make this pod schedulable", "pod", klog.KObj(pod), "PVC", klog.KObj(newPersistentVolumeClaim))
 			return framework.Queue, nil
 		}
 	}
-	logger.V(5).Info("PVC irrelevant to the Pod was created, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "PVC", klog.KObj(newPersistentVolumeClaim))
+	logger.V(5).Info("PVC irrelevant to the Pod was created or updated, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "PVC", klog.KObj(newPersistentVolumeClaim))
+	return framework.QueueSkip, nil
+}
+
+// cachedEventLookup memoizes a string-valued lookup per cluster event, so hint
+// evaluations of many waiting pods for the same event share the resolution
+// instead of each repeating the same lister lookups. Without a cache the
+// lookup is performed directly.
+func (pl *VolumeRestrictions) cachedEventLookup(eventObj metav1.Object, itemKey string, resolve func() string) string {
+	if pl.hintCache == nil {
+		return resolve()
+	}
+	return pl.hintCache.lookup(eventKeyForObject(eventObj), itemKey, resolve)
+}
+
+// persistentVolumeBoundToPodClaim reports whether the PV is bound to one of the
+// claims the pod references, either through the PV's claim ref or through the
+// claim's recorded volume name. The claim ref carries the last-known binding,
+// which stays authoritative even once the claim itself is updated or gone.
+func (pl *VolumeRestrictions) persistentVolumeBoundToPodClaim(pv *v1.PersistentVolume, pod *v1.Pod) bool {
+	claimRef := pv.Spec.ClaimRef
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		if claimRef != nil && claimRef.Namespace == pod.Namespace && claimRef.Name == volume.PersistentVolumeClaim.ClaimName {
+			return true
+		}
+		claimKey := framework.GetNamespacedName(pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		volumeName := pl.cachedEventLookup(pv, "claimVolume/"+claimKey, func() string {
+			pvc, err := pl.pvcLister.PersistentVolumeClaims(pod.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
+			if err != nil {
+				// A claim that cannot be resolved cannot tell whether it was bound to
+				// the PV; the claim ref check above already covered that binding.
+				return ""
+			}
+			return pvc.Spec.VolumeName
+		})
+		if volumeName == pv.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isSchedulableAfterPersistentVolumeDeleted is invoked whenever a PersistentVolume is deleted.
+// It checks whether the deleted PV was bound to one of the claims the pod references,
+// in which case a recreated PV might make the pod schedulable.
+func (pl *VolumeRestrictions) isSchedulableAfterPersistentVolumeDeleted(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	deletedPersistentVolume, _, err := util.As[*v1.PersistentVolume](oldObj, newObj)
+	if err != nil {
+		return framework.Queue, fmt.Errorf("unexpected objects in isSchedulableAfterPersistentVolumeDeleted: %w", err)
+	}
+
+	if pl.persistentVolumeBoundToPodClaim(deletedPersistentVolume, pod) {
+		logger.V(5).Info("PV bound to a PVC that is referred from the pod was deleted, which might make this pod schedulable once the PV is recreated", "pod", klog.KObj(pod), "PV", klog.KObj(deletedPersistentVolume))
+		return framework.Queue, nil
+	}
+	logger.V(5).Info("PV irrelevant to the pod was deleted, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "PV", klog.KObj(deletedPersistentVolume))
 	return framework.QueueSkip, nil
 }
 
+// accessModesEqual reports whether the two access mode lists grant the same
+// modes, ignoring order and duplicates.
+func accessModesEqual(a, b []v1.PersistentVolumeAccessMode) bool {
+	return sets.New(a...).Equal(sets.New(b...))
+}
+
+// isSchedulableAfterPersistentVolumeChange is invoked whenever a PersistentVolume is
+// added or updated. Editing a pre-provisioned PV's access modes can lift (or introduce)
+// the restriction the pod was rejected on without any event on the claim itself, so the
+// pod is requeued when the PV is bound to one of its claims and the access modes changed.
+func (pl *VolumeRestrictions) isSchedulableAfterPersistentVolumeChange(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+	oldPersistentVolume, newPersistentVolume, err := util.As[*v1.PersistentVolume](oldObj, newObj)
+	if err != nil {
+		return framework.Queue, fmt.Errorf("unexpected objects in isSchedulableAfterPersistentVolumeChange: %w", err)
+	}
+
+	if oldPersistentVolume != nil && accessModesEqual(oldPersistentVolume.Spec.AccessModes, newPersistentVolume.Spec.AccessModes) {
+		logger.V(5).Info("PV update doesn't change the access modes, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "PV", klog.KObj(newPersistentVolume))
+		return framework.QueueSkip, nil
+	}
+	if !pl.persistentVolumeBoundToPodClaim(newPersistentVolume, pod) {
+		logger.V(5).Info("PV irrelevant to the pod was created or updated, which doesn't make this pod schedulable", "pod", klog.KObj(pod), "PV", klog.KObj(newPersistentVolume))
+		return framework.QueueSkip, nil
+	}
+	logger.V(5).Info("access modes of a PV bound to a PVC that is referred from the pod changed, which might make this pod schedulable", "pod", klog.KObj(pod), "PV", klog.KObj(newPersistentVolume))
+	return framework.Queue, nil
+}
+
 // isSchedulableAfterPodDeleted is invoked whenever a pod deleted,
 // It checks whether the deleted pod will conflict with volumes of other pods on the same node
 func (pl *VolumeRestrictions) isSchedulableAfterPodDeleted(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
@@ -379,12 +3055,15 @@ func (pl *VolumeRestrictions) isSchedulableAfterPodDeleted(logger klog.Logger, p
 		return framework.Queue, fmt.Errorf("unexpected objects in isSchedulableAfterPodDeleted: %w", err)
 	}
 
-	if deletedPod.Namespace != pod.Namespace {
+	// With cross-namespace references enabled the deleted pod may have held a
+	// claim the pod refers to across namespaces, so the namespace short-circuit
+	// only applies when they are disabled.
+	if !pl.enableCrossNamespaceVolumeReferences && deletedPod.Namespace != pod.Namespace {
 		return framework.QueueSkip, nil
 	}
 
 	nodeInfo := framework.NewNodeInfo(deletedPod)
-	if !satisfyVolumeConflicts(pod, nodeInfo) {
+	if !satisfyVolumeConflicts(pod, nodeInfo, pl.legacyVolumeSources) {
 		logger.V(5).Info("Pod with the volume that the target pod requires was deleted, which might make this pod schedulable", "pod", klog.KObj(pod), "deletedPod", klog.KObj(deletedPod))
 		return framework.Queue, nil
 	}
@@ -398,11 +3077,16 @@ func (pl *VolumeRestrictions) isSchedulableAfterPodDeleted(logger klog.Logger, p
 	claims := sets.New[string]()
 	for _, volume := range pod.Spec.Volumes {
 		if volume.PersistentVolumeClaim != nil {
-			claims.Insert(volume.PersistentVolumeClaim.ClaimName)
+			namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+			claims.Insert(framework.GetNamespacedName(namespace, name))
 		}
 	}
 	for _, volume := range deletedPod.Spec.Volumes {
-		if volume.PersistentVolumeClaim != nil && claims.Has(volume.PersistentVolumeClaim.ClaimName) {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		namespace, name := resolveClaimReference(pl.enableCrossNamespaceVolumeReferences, deletedPod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		if claims.Has(framework.GetNamespacedName(namespace, name)) {
 			logger.V(5).Info("Pod with the same PVC that the target pod requires was deleted, which might make this pod schedulable", "pod", klog.KObj(pod), "deletedPod", klog.KObj(deletedPod))
 			return framework.Queue, nil
 		}
@@ -413,14 +3097,134 @@ func (pl *VolumeRestrictions) isSchedulableAfterPodDeleted(logger klog.Logger, p
 }
 
 // New initializes a new plugin and returns it.
-func New(_ context.Context, _ runtime.Object, handle framework.Handle, fts feature.Features) (framework.Plugin, error) {
+func New(ctx context.Context, plArgs runtime.Object, handle framework.Handle, fts feature.Features) (framework.Plugin, error) {
+	args, err := getArgs(plArgs)
+	if err != nil {
+		return nil, err
+	}
 	informerFactory := handle.SharedInformerFactory()
 	pvcLister := informerFactory.Core().V1().PersistentVolumeClaims().Lister()
+	pvLister := informerFactory.Core().V1().PersistentVolumes().Lister()
 	sharedLister := handle.SnapshotSharedLister()
 
-	return &VolumeRestrictions{
-		pvcLister:                 pvcLister,
-		sharedLister:              sharedLister,
-		enableSchedulingQueueHint: fts.EnableSchedulingQueueHint,
-	}, nil
+	var readWriteOncePodSelector labels.Selector
+	if args.ReadWriteOncePodLabelSelector != nil {
+		readWriteOncePodSelector, err = metav1.LabelSelectorAsSelector(args.ReadWriteOncePodLabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ReadWriteOncePodLabelSelector: %w", err)
+		}
+	}
+
+	conflictStatusCodes, err := conflictStatusCodesFromArgs(args.ConflictStatusCodes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ConflictStatusCodes: %w", err)
+	}
+
+	pl := &VolumeRestrictions{
+		pvcLister:                            pvcLister,
+		pvLister:                             pvLister,
+		podLister:                            informerFactory.Core().V1().Pods().Lister(),
+		sharedLister:                         sharedLister,
+		diskConflictMode:                     args.DiskConflictMode,
+		enableConflictReasonAggregation:      args.EnableConflictReasonAggregation,
+		failOnMissingAccessModes:             args.FailOnMissingAccessModes,
+		readWriteOncePodSelector:             readWriteOncePodSelector,
+		allowSharedWritersAnnotation:         args.AllowSharedWritersAnnotationKey,
+		unknownCSIDriverPolicy:               args.UnknownCSIDriverPolicy,
+		strictVolumeIdentity:                 args.StrictVolumeIdentity,
+		ignoreTerminatingPods:                args.IgnoreTerminatingPods,
+		ignoreMirrorPods:                     args.IgnoreMirrorPods,
+		verifyReadWriteOncePodHolders:        args.VerifyReadWriteOncePodHolders,
+		enforceReadWriteOncePerNode:          args.EnforceReadWriteOncePerNode,
+		scoreWeight:                          args.ScoreWeight,
+		enabledConflictCategories:            enabledConflictCategoriesFromArgs(args.EnabledDiskConflictCategories),
+		multiWriterCategories:                multiWriterCategoriesFromArgs(args.MultiWriterVolumeSources),
+		legacyVolumeSources:                  args.LegacyVolumeSources,
+		exemptVolumeIdentities:               sets.New(args.ExemptVolumeIdentities...),
+		flexVolumeIdentityKeys:               args.FlexVolumeIdentityKeys,
+		csiDriverLister:                      informerFactory.Storage().V1().CSIDrivers().Lister(),
+		rejections:                           newRejectionRecorder(args.RejectionHistoryLength),
+		conflictStatusCodes:                  conflictStatusCodes,
+		parallelizer:                         handle.Parallelizer(),
+		contendingPods:                       sets.New[string](),
+		enableSchedulingQueueHint:            fts.EnableSchedulingQueueHint,
+		enableCrossNamespaceVolumeReferences: fts.EnableCrossNamespaceVolumeDataSource,
+		hintCache:                            newHintEventCache(),
+	}
+	// The cache is only invalidated from isSchedulableAfterPersistentVolumeClaimChange,
+	// which the scheduler bypasses when SchedulingQueueHints is disabled. Without
+	// that path a claim deleted and recreated under the same name would serve a
+	// stale classification forever, so the cache only runs alongside the hints.
+	if fts.EnableSchedulingQueueHint {
+		pl.rwopCache = newReadWriteOncePodCache()
+	}
+	if args.EmitConflictEvents {
+		if recorder := handle.EventRecorder(); recorder != nil {
+			pl.eventRecorder = recorder
+			pl.conflictEvents = newConflictEventLimiter()
+		}
+	}
+	if assumedPodLister, ok := handle.(AssumedPodLister); ok {
+		pl.assumedPodLister = assumedPodLister
+	}
+	if args.InformerSyncCheckSeconds > 0 {
+		hasSynced := informerFactory.Core().V1().PersistentVolumeClaims().Informer().HasSynced
+		go pl.runPVCInformerSyncCheck(ctx, hasSynced, time.Duration(args.InformerSyncCheckSeconds)*time.Second)
+	}
+	go pl.runReadWriteOncePodGaugeRefresh(ctx, readWriteOncePodGaugeRefreshInterval)
+	return pl, nil
+}
+
+// NewWithConflictPolicy returns a plugin factory building the plugin with the
+// given conflict policy consulted before the built-in conflict rules.
+// Out-of-tree schedulers register it in place of New; a nil policy behaves
+// exactly like New.
+func NewWithConflictPolicy(policy ConflictPolicy) func(ctx context.Context, plArgs runtime.Object, handle framework.Handle, fts feature.Features) (framework.Plugin, error) {
+	return func(ctx context.Context, plArgs runtime.Object, handle framework.Handle, fts feature.Features) (framework.Plugin, error) {
+		plugin, err := New(ctx, plArgs, handle, fts)
+		if err != nil {
+			return nil, err
+		}
+		plugin.(*VolumeRestrictions).conflictPolicy = policy
+		return plugin, nil
+	}
+}
+
+// runPVCInformerSyncCheck periodically verifies that the PVC informer reports
+// having synced. An informer that silently stops syncing leaves the plugin
+// deciding on stale claims, which is otherwise invisible, so staying out of
+// sync across consecutive checks emits a warning log and bumps a metric. The
+// check stops when ctx is cancelled.
+func (pl *VolumeRestrictions) runPVCInformerSyncCheck(ctx context.Context, hasSynced func() bool, interval time.Duration) {
+	logger := klog.FromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var staleSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if hasSynced() {
+			staleSince = time.Time{}
+			continue
+		}
+		if staleSince.IsZero() {
+			// Give the informer one interval to catch up before warning.
+			staleSince = time.Now()
+			continue
+		}
+		metrics.PVCListerStaleChecks.Inc()
+		logger.Info("PersistentVolumeClaim informer has not synced; scheduling decisions may be based on stale claims",
+			"staleFor", time.Since(staleSince).Round(time.Millisecond))
+	}
+}
+
+func getArgs(obj runtime.Object) (config.VolumeRestrictionsArgs, error) {
+	ptr, ok := obj.(*config.VolumeRestrictionsArgs)
+	if !ok {
+		return config.VolumeRestrictionsArgs{}, fmt.Errorf("want args to be of type VolumeRestrictionsArgs, got %T", obj)
+	}
+	return *ptr, validation.ValidateVolumeRestrictionsArgs(nil, ptr)
 }
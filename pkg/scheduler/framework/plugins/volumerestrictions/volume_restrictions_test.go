@@ -18,20 +18,40 @@ package volumerestrictions
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/events"
+	componentbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/ktesting"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/backend/cache"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
 	plugintesting "k8s.io/kubernetes/pkg/scheduler/framework/plugins/testing"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/volumerestrictions/metrics"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulermetrics "k8s.io/kubernetes/pkg/scheduler/metrics"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"k8s.io/utils/ptr"
 )
 
 func TestGCEDiskConflicts(t *testing.T) {
@@ -53,7 +73,8 @@ func TestGCEDiskConflicts(t *testing.T) {
 		Name:         "volume with no restriction",
 		VolumeSource: v1.VolumeSource{},
 	}
-	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"GCEPersistentDisk volume gce://foo is held by pod default/pod-a")
 	tests := []struct {
 		pod                 *v1.Pod
 		nodeInfo            *framework.NodeInfo
@@ -77,7 +98,7 @@ func TestGCEDiskConflicts(t *testing.T) {
 		},
 		{
 			pod:                 st.MakePod().Volume(volState).Obj(),
-			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volState).Obj()),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()),
 			name:                "same state",
 			preFilterWantStatus: nil,
 			wantStatus:          errStatus,
@@ -135,7 +156,16 @@ func TestAWSDiskConflicts(t *testing.T) {
 			},
 		},
 	}
-	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict)
+	readOnlyVolState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
+				VolumeID: "foo",
+				ReadOnly: true,
+			},
+		},
+	}
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonAttachLayerConflict,
+		"AWSElasticBlockStore volume aws://foo is held by pod default/pod-a")
 	tests := []struct {
 		pod                 *v1.Pod
 		nodeInfo            *framework.NodeInfo
@@ -159,7 +189,7 @@ func TestAWSDiskConflicts(t *testing.T) {
 		},
 		{
 			pod:                 st.MakePod().Volume(volState).Obj(),
-			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volState).Obj()),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()),
 			name:                "same state",
 			wantStatus:          errStatus,
 			preFilterWantStatus: nil,
@@ -171,6 +201,27 @@ func TestAWSDiskConflicts(t *testing.T) {
 			wantStatus:          nil,
 			preFilterWantStatus: nil,
 		},
+		{
+			pod:                 st.MakePod().Volume(readOnlyVolState).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(readOnlyVolState).Obj()),
+			name:                "read-only mounts share the volume",
+			wantStatus:          nil,
+			preFilterWantStatus: nil,
+		},
+		{
+			pod:                 st.MakePod().Volume(volState).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(readOnlyVolState).Obj()),
+			name:                "read-write mount conflicts with a read-only holder",
+			wantStatus:          errStatus,
+			preFilterWantStatus: nil,
+		},
+		{
+			pod:                 st.MakePod().Volume(readOnlyVolState).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()),
+			name:                "read-only mount conflicts with a read-write holder",
+			wantStatus:          errStatus,
+			preFilterWantStatus: nil,
+		},
 	}
 
 	for _, test := range tests {
@@ -216,7 +267,8 @@ func TestRBDDiskConflicts(t *testing.T) {
 			},
 		},
 	}
-	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"RBD volume rbd://a,b/foo/bar is held by pod default/pod-a")
 	tests := []struct {
 		pod                 *v1.Pod
 		nodeInfo            *framework.NodeInfo
@@ -240,7 +292,7 @@ func TestRBDDiskConflicts(t *testing.T) {
 		},
 		{
 			pod:                 st.MakePod().Volume(volState).Obj(),
-			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volState).Obj()),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()),
 			name:                "same state",
 			wantStatus:          errStatus,
 			preFilterWantStatus: nil,
@@ -297,7 +349,8 @@ func TestISCSIDiskConflicts(t *testing.T) {
 			},
 		},
 	}
-	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"ISCSI volume iscsi://iqn.2016-12.server:storage.target01 is held by pod default/pod-a")
 	tests := []struct {
 		pod                 *v1.Pod
 		nodeInfo            *framework.NodeInfo
@@ -321,7 +374,7 @@ func TestISCSIDiskConflicts(t *testing.T) {
 		},
 		{
 			pod:                 st.MakePod().Volume(volState).Obj(),
-			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volState).Obj()),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()),
 			name:                "same state",
 			wantStatus:          errStatus,
 			preFilterWantStatus: nil,
@@ -357,109 +410,107 @@ func TestISCSIDiskConflicts(t *testing.T) {
 	}
 }
 
-func TestAccessModeConflicts(t *testing.T) {
-	// Required for querying lister for PVCs in the same namespace.
-	podWithOnePVC := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").Node("node-1").Obj()
-	podWithTwoPVCs := st.MakePod().Name("pod-with-two-pvcs").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").PVC("claim-with-rwop-2").Node("node-1").Obj()
-	podWithOneConflict := st.MakePod().Name("pod-with-one-conflict").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").Node("node-1").Obj()
-	podWithTwoConflicts := st.MakePod().Name("pod-with-two-conflicts").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").PVC("claim-with-rwop-2").Node("node-1").Obj()
-	// Required for querying lister for PVCs in the same namespace.
-	podWithReadWriteManyPVC := st.MakePod().Name("pod-with-rwx").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwx").Node("node-1").Obj()
-
-	node := &v1.Node{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "node-1",
+func TestFCDiskConflicts(t *testing.T) {
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			FC: &v1.FCVolumeSource{
+				TargetWWNs: []string{"50060e801049cfd1"},
+				Lun:        ptr.To[int32](0),
+				FSType:     "ext4",
+			},
 		},
 	}
-
-	readWriteOncePodPVC1 := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "claim-with-rwop-1",
-		},
-		Spec: v1.PersistentVolumeClaimSpec{
-			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+	volStateOtherLun := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			FC: &v1.FCVolumeSource{
+				TargetWWNs: []string{"50060e801049cfd1"},
+				Lun:        ptr.To[int32](1),
+				FSType:     "ext4",
+			},
 		},
 	}
-	readWriteOncePodPVC2 := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "claim-with-rwop-2",
-		},
-		Spec: v1.PersistentVolumeClaimSpec{
-			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+	volStateWWID := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			FC: &v1.FCVolumeSource{
+				WWIDs: []string{"3600508b400105e210000900000490000"},
+			},
 		},
 	}
-	readWriteManyPVC := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "claim-with-rwx",
-		},
-		Spec: v1.PersistentVolumeClaimSpec{
-			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+	volStateWWIDOtherTargets := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			FC: &v1.FCVolumeSource{
+				TargetWWNs: []string{"500a0981891b8dc5"},
+				Lun:        ptr.To[int32](2),
+				WWIDs:      []string{"3600508b400105e210000900000490000"},
+			},
 		},
 	}
-
+	readOnly := func(volume v1.Volume) v1.Volume {
+		fc := *volume.FC
+		fc.ReadOnly = true
+		volume.FC = &fc
+		return volume
+	}
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"FibreChannel volume fc://50060e801049cfd1:0/ is held by pod default/pod-a")
 	tests := []struct {
-		name                string
 		pod                 *v1.Pod
 		nodeInfo            *framework.NodeInfo
-		existingPods        []*v1.Pod
-		existingNodes       []*v1.Node
-		existingPVCs        []*v1.PersistentVolumeClaim
-		preFilterWantStatus *framework.Status
+		name                string
 		wantStatus          *framework.Status
+		preFilterWantStatus *framework.Status
 	}{
 		{
-			name:                "nothing",
 			pod:                 &v1.Pod{},
 			nodeInfo:            framework.NewNodeInfo(),
-			existingPods:        []*v1.Pod{},
-			existingNodes:       []*v1.Node{},
-			existingPVCs:        []*v1.PersistentVolumeClaim{},
-			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			name:                "nothing",
 			wantStatus:          nil,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
 		},
 		{
-			name:                "failed to get PVC",
-			pod:                 podWithOnePVC,
-			nodeInfo:            framework.NewNodeInfo(),
-			existingPods:        []*v1.Pod{},
-			existingNodes:       []*v1.Node{},
-			existingPVCs:        []*v1.PersistentVolumeClaim{},
-			preFilterWantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "persistentvolumeclaim \"claim-with-rwop-1\" not found"),
-			wantStatus:          nil,
+			pod:                 st.MakePod().Volume(volState).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()),
+			name:                "same target WWN and LUN",
+			wantStatus:          errStatus,
+			preFilterWantStatus: nil,
 		},
 		{
-			name:                "no access mode conflict",
-			pod:                 podWithOnePVC,
-			nodeInfo:            framework.NewNodeInfo(podWithReadWriteManyPVC),
-			existingPods:        []*v1.Pod{podWithReadWriteManyPVC},
-			existingNodes:       []*v1.Node{node},
-			existingPVCs:        []*v1.PersistentVolumeClaim{readWriteOncePodPVC1, readWriteManyPVC},
-			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			pod:                 st.MakePod().Volume(volStateOtherLun).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volState).Obj()),
+			name:                "same target WWN, different LUN",
 			wantStatus:          nil,
+			preFilterWantStatus: nil,
 		},
 		{
-			name:                "access mode conflict, unschedulable",
-			pod:                 podWithOneConflict,
-			nodeInfo:            framework.NewNodeInfo(podWithOnePVC, podWithReadWriteManyPVC),
-			existingPods:        []*v1.Pod{podWithOnePVC, podWithReadWriteManyPVC},
-			existingNodes:       []*v1.Node{node},
-			existingPVCs:        []*v1.PersistentVolumeClaim{readWriteOncePodPVC1, readWriteManyPVC},
+			pod:      st.MakePod().Volume(volStateWWID).Obj(),
+			nodeInfo: framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volStateWWID).Obj()),
+			name:     "same WWID",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"FibreChannel volume fc:///3600508b400105e210000900000490000 is held by pod default/pod-a"),
 			preFilterWantStatus: nil,
-			wantStatus:          framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict),
 		},
 		{
-			name:                "two conflicts, unschedulable",
-			pod:                 podWithTwoConflicts,
-			nodeInfo:            framework.NewNodeInfo(podWithTwoPVCs, podWithReadWriteManyPVC),
-			existingPods:        []*v1.Pod{podWithTwoPVCs, podWithReadWriteManyPVC},
-			existingNodes:       []*v1.Node{node},
-			existingPVCs:        []*v1.PersistentVolumeClaim{readWriteOncePodPVC1, readWriteOncePodPVC2, readWriteManyPVC},
+			// The shared WWID identifies the same device even though the
+			// target lists differ, so the holder cannot be named by identity.
+			pod:                 st.MakePod().Volume(volStateWWIDOtherTargets).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volStateWWID).Obj()),
+			name:                "shared WWID, different targets",
+			wantStatus:          framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict),
+			preFilterWantStatus: nil,
+		},
+		{
+			pod:                 st.MakePod().Volume(readOnly(volState)).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(readOnly(volState)).Obj()),
+			name:                "all mounts read-only",
+			wantStatus:          nil,
+			preFilterWantStatus: nil,
+		},
+		{
+			pod:                 st.MakePod().Volume(volState).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(readOnly(volState)).Obj()),
+			name:                "read-write mount of a read-only held device",
+			wantStatus:          errStatus,
 			preFilterWantStatus: nil,
-			wantStatus:          framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict),
 		},
 	}
 
@@ -468,14 +519,14 @@ func TestAccessModeConflicts(t *testing.T) {
 			_, ctx := ktesting.NewTestContext(t)
 			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
-			p := newPluginWithListers(ctx, t, test.existingPods, test.existingNodes, test.existingPVCs)
+			p := newPlugin(ctx, t)
 			cycleState := framework.NewCycleState()
 			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
 			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
 				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
 			}
 			// If PreFilter fails, then Filter will not run.
-			if test.preFilterWantStatus.IsSuccess() {
+			if test.preFilterWantStatus == nil {
 				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
 				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
 					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
@@ -485,318 +536,5271 @@ func TestAccessModeConflicts(t *testing.T) {
 	}
 }
 
-func Test_isSchedulableAfterPodDeleted(t *testing.T) {
-	GCEDiskVolState := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
-				PDName: "foo",
+func TestFlexVolumeConflicts(t *testing.T) {
+	flexVolume := func(driver string, options map[string]string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				FlexVolume: &v1.FlexVolumeSource{
+					Driver:   driver,
+					Options:  options,
+					ReadOnly: readOnly,
+				},
 			},
+		}
+	}
+	volFoo := flexVolume("foo-driver", map[string]string{"volumeID": "foo"}, false)
+	volFooReadOnly := flexVolume("foo-driver", map[string]string{"volumeID": "foo"}, true)
+	volBar := flexVolume("foo-driver", map[string]string{"volumeID": "bar"}, false)
+	volOtherDriver := flexVolume("bar-driver", map[string]string{"volumeID": "foo"}, false)
+	volDevice := flexVolume("foo-driver", map[string]string{"device": "dev-1"}, false)
+	volNoIdentity := flexVolume("foo-driver", nil, false)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"FlexVolume volume flex://foo-driver/foo is held by pod default/pod-a")
+
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		flexIdentityKeys    []string
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:       "same driver and device",
+			pod:        st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			wantStatus: errStatus,
+		},
+		{
+			name:       "all mounts read-only",
+			pod:        st.MakePod().Volume(volFooReadOnly).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFooReadOnly).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:       "different device",
+			pod:        st.MakePod().Volume(volBar).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:       "same option value under a different driver",
+			pod:        st.MakePod().Volume(volOtherDriver).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:       "identifying option not among the configured keys",
+			pod:        st.MakePod().Volume(volDevice).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volDevice).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:             "custom identifying option key",
+			pod:              st.MakePod().Volume(volDevice).Obj(),
+			nodeInfo:         framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volDevice).Obj()),
+			flexIdentityKeys: []string{"device"},
+			wantStatus:       framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict),
+		},
+		{
+			name:       "no identifying option",
+			pod:        st.MakePod().Volume(volNoIdentity).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volNoIdentity).Obj()),
+			wantStatus: nil,
 		},
 	}
-	GCEDiskVolState2 := v1.Volume{
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.FlexVolumeIdentityKeys = test.flexIdentityKeys
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiWriterVolumeSources(t *testing.T) {
+	gceVolume := v1.Volume{
 		VolumeSource: v1.VolumeSource{
 			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
-				PDName: "bar",
+				PDName: "foo",
 			},
 		},
 	}
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"GCEPersistentDisk volume gce://foo is held by pod default/pod-a")
 
-	AWSDiskVolState := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
-				VolumeID: "foo",
-			},
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		multiWriterSources  []string
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:       "conflict without an allowlist",
+			pod:        st.MakePod().Volume(gceVolume).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(gceVolume).Obj()),
+			wantStatus: errStatus,
 		},
-	}
-	AWSDiskVolState2 := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
-				VolumeID: "bar",
-			},
+		{
+			name:                "allowlisted category never conflicts",
+			pod:                 st.MakePod().Volume(gceVolume).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(gceVolume).Obj()),
+			multiWriterSources:  []string{"GCEPersistentDisk"},
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
+		},
+		{
+			name:               "allowlisting another category keeps the conflict",
+			pod:                st.MakePod().Volume(gceVolume).Obj(),
+			nodeInfo:           framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(gceVolume).Obj()),
+			multiWriterSources: []string{"AWSElasticBlockStore"},
+			wantStatus:         errStatus,
 		},
 	}
 
-	RBDDiskVolState := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			RBD: &v1.RBDVolumeSource{
-				CephMonitors: []string{"a", "b"},
-				RBDPool:      "foo",
-				RBDImage:     "bar",
-				FSType:       "ext4",
-			},
-		},
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.MultiWriterVolumeSources = test.multiWriterSources
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
 	}
-	RBDDiskVolState2 := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			RBD: &v1.RBDVolumeSource{
-				CephMonitors: []string{"c", "d"},
-				RBDPool:      "foo",
-				RBDImage:     "bar",
-				FSType:       "ext4",
+}
+
+func TestVsphereVolumeConflicts(t *testing.T) {
+	vsphereVolume := func(volumePath string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				VsphereVolume: &v1.VsphereVirtualDiskVolumeSource{
+					VolumePath: volumePath,
+				},
 			},
-		},
+		}
 	}
+	volFoo := vsphereVolume("[datastore] kube/foo.vmdk")
+	volBar := vsphereVolume("[datastore] kube/bar.vmdk")
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"VsphereVolume volume vsphere://[datastore] kube/foo.vmdk is held by pod default/pod-a")
 
-	ISCSIDiskVolState := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			ISCSI: &v1.ISCSIVolumeSource{
-				TargetPortal: "127.0.0.1:3260",
-				IQN:          "iqn.2016-12.server:storage.target01",
-				FSType:       "ext4",
-				Lun:          0,
-			},
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			// vSphere VMDKs are single-writer and the volume source carries no
+			// read-only hint, so sharing the path always conflicts.
+			name:       "same volume path",
+			pod:        st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			wantStatus: errStatus,
+		},
+		{
+			name:       "different volume path",
+			pod:        st.MakePod().Volume(volBar).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			wantStatus: nil,
 		},
 	}
-	ISCSIDiskVolState2 := v1.Volume{
-		VolumeSource: v1.VolumeSource{
-			ISCSI: &v1.ISCSIVolumeSource{
-				TargetPortal: "127.0.0.1:3260",
-				IQN:          "iqn.2017-12.server:storage.target01",
-				FSType:       "ext4",
-				Lun:          0,
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPlugin(ctx, t)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCinderVolumeConflicts(t *testing.T) {
+	cinderVolume := func(volumeID string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				Cinder: &v1.CinderVolumeSource{
+					VolumeID: volumeID,
+					ReadOnly: readOnly,
+				},
 			},
+		}
+	}
+	volFoo := cinderVolume("foo", false)
+	volFooReadOnly := cinderVolume("foo", true)
+	volBar := cinderVolume("bar", false)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"Cinder volume cinder://foo is held by pod default/pod-a")
+
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:       "same volume ID mounted read-write",
+			pod:        st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			wantStatus: errStatus,
+		},
+		{
+			name:       "all mounts read-only",
+			pod:        st.MakePod().Volume(volFooReadOnly).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFooReadOnly).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:     "read-write mount next to a read-only mount",
+			pod:      st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo: framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFooReadOnly).Obj()),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"Cinder volume cinder://foo is held by pod default/pod-a"),
+		},
+		{
+			name:       "different volume ID",
+			pod:        st.MakePod().Volume(volBar).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			wantStatus: nil,
 		},
 	}
 
-	podGCEDisk := st.MakePod().Volume(GCEDiskVolState).Obj()
-	podGCEDiskConflicts := st.MakePod().Volume(GCEDiskVolState).Obj()
-	podGCEDiskNoConflicts := st.MakePod().Volume(GCEDiskVolState2).Obj()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPlugin(ctx, t)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
 
-	podAWSDisk := st.MakePod().Volume(AWSDiskVolState).Obj()
-	podAWSDiskConflicts := st.MakePod().Volume(AWSDiskVolState).Obj()
-	podAWSDiskNoConflicts := st.MakePod().Volume(AWSDiskVolState2).Obj()
+func TestAzureDiskVolumeConflicts(t *testing.T) {
+	azureDiskVolume := func(diskURI string, kind v1.AzureDataDiskKind, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				AzureDisk: &v1.AzureDiskVolumeSource{
+					DiskName:    "disk",
+					DataDiskURI: diskURI,
+					Kind:        &kind,
+					ReadOnly:    &readOnly,
+				},
+			},
+		}
+	}
+	const fooURI = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/foo"
+	const barURI = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/bar"
+	volFoo := azureDiskVolume(fooURI, v1.AzureManagedDisk, false)
+	volFooReadOnly := azureDiskVolume(fooURI, v1.AzureManagedDisk, true)
+	volFooShared := azureDiskVolume(fooURI, v1.AzureSharedBlobDisk, false)
+	volFooUpperCase := azureDiskVolume(strings.ToUpper(fooURI), v1.AzureManagedDisk, false)
+	volBar := azureDiskVolume(barURI, v1.AzureManagedDisk, false)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"AzureDisk volume azuredisk://"+strings.ToLower(fooURI)+" is held by pod default/pod-a")
 
-	podRBDDiskDisk := st.MakePod().Volume(RBDDiskVolState).Obj()
-	podRBDDiskDiskConflicts := st.MakePod().Volume(RBDDiskVolState).Obj()
-	podRBDDiskNoConflicts := st.MakePod().Volume(RBDDiskVolState2).Obj()
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:       "same disk mounted read-write",
+			pod:        st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			wantStatus: errStatus,
+		},
+		{
+			name:       "disk URIs differing only in case refer to the same disk",
+			pod:        st.MakePod().Volume(volFooUpperCase).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			wantStatus: errStatus,
+		},
+		{
+			name:       "all mounts read-only",
+			pod:        st.MakePod().Volume(volFooReadOnly).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFooReadOnly).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:       "all references in shared mode",
+			pod:        st.MakePod().Volume(volFooShared).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFooShared).Obj()),
+			wantStatus: nil,
+		},
+		{
+			name:       "shared mode reference next to a non-shared one",
+			pod:        st.MakePod().Volume(volFooShared).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			wantStatus: errStatus,
+		},
+		{
+			name:       "different disk",
+			pod:        st.MakePod().Volume(volBar).Obj(),
+			nodeInfo:   framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			wantStatus: nil,
+		},
+	}
 
-	podISCSIDiskDisk := st.MakePod().Volume(ISCSIDiskVolState).Obj()
-	podISCSIDiskConflicts := st.MakePod().Volume(ISCSIDiskVolState).Obj()
-	podISCSIDiskNoConflicts := st.MakePod().Volume(ISCSIDiskVolState2).Obj()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPlugin(ctx, t)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
 
-	testcases := map[string]struct {
-		pod            *v1.Pod
-		oldObj, newObj interface{}
-		existingPods   []*v1.Pod
-		existingPVC    *v1.PersistentVolumeClaim
-		expectedHint   framework.QueueingHint
-		expectedErr    bool
+func TestScaleIOVolumeConflicts(t *testing.T) {
+	scaleIOVolume := func(system, volumeName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				ScaleIO: &v1.ScaleIOVolumeSource{
+					System:     system,
+					VolumeName: volumeName,
+					ReadOnly:   readOnly,
+				},
+			},
+		}
+	}
+	volFoo := scaleIOVolume("sio", "foo", false)
+	volFooReadOnly := scaleIOVolume("sio", "foo", true)
+	volFooOtherSystem := scaleIOVolume("sio-dr", "foo", false)
+	volBar := scaleIOVolume("sio", "bar", false)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"ScaleIO volume scaleio://sio/foo is held by pod default/pod-a")
+
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		legacyVolumeSources bool
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
 	}{
-		"queue-new-object-gcedisk-conflict": {
-			pod:          podGCEDisk,
-			oldObj:       podGCEDiskConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.Queue,
-			expectedErr:  false,
+		{
+			name:                "same system and volume name mounted read-write",
+			pod:                 st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          errStatus,
 		},
-		"skip-new-object-gcedisk-no-conflict": {
-			pod:          podGCEDisk,
-			oldObj:       podGCEDiskNoConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+		{
+			name:                "all mounts read-only",
+			pod:                 st.MakePod().Volume(volFooReadOnly).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volFooReadOnly).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          nil,
 		},
-		"queue-new-object-awsdisk-conflict": {
-			pod:          podAWSDisk,
-			oldObj:       podAWSDiskConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.Queue,
-			expectedErr:  false,
+		{
+			name:                "same volume name under a different system",
+			pod:                 st.MakePod().Volume(volFooOtherSystem).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          nil,
 		},
-		"skip-new-object-awsdisk-no-conflict": {
-			pod:          podAWSDisk,
-			oldObj:       podAWSDiskNoConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+		{
+			name:                "different volume name",
+			pod:                 st.MakePod().Volume(volBar).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          nil,
 		},
-		"queue-new-object-rbddisk-conflict": {
-			pod:          podRBDDiskDisk,
-			oldObj:       podRBDDiskDiskConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.Queue,
-			expectedErr:  false,
+		{
+			name:                "legacy sources disabled skips the check",
+			pod:                 st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
 		},
-		"skip-new-object-rbddisk-no-conflict": {
-			pod:          podRBDDiskDisk,
-			oldObj:       podRBDDiskNoConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.LegacyVolumeSources = test.legacyVolumeSources
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestStorageOSVolumeConflicts(t *testing.T) {
+	storageOSVolume := func(volumeName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				StorageOS: &v1.StorageOSVolumeSource{
+					VolumeName: volumeName,
+					ReadOnly:   readOnly,
+				},
+			},
+		}
+	}
+	volFoo := storageOSVolume("foo", false)
+	volFooReadOnly := storageOSVolume("foo", true)
+	volBar := storageOSVolume("bar", false)
+	errStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+		"StorageOS volume storageos://foo is held by pod default/pod-a")
+
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		legacyVolumeSources bool
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "same volume name mounted read-write",
+			pod:                 st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          errStatus,
 		},
-		"queue-new-object-iscsidisk-conflict": {
-			pod:          podISCSIDiskDisk,
-			oldObj:       podISCSIDiskConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.Queue,
-			expectedErr:  false,
+		{
+			name:                "all mounts read-only",
+			pod:                 st.MakePod().Volume(volFooReadOnly).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volFooReadOnly).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          nil,
 		},
-		"skip-new-object-iscsidisk-no-conflict": {
-			pod:          podISCSIDiskDisk,
-			oldObj:       podISCSIDiskNoConflicts,
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+		{
+			name:                "different volume name",
+			pod:                 st.MakePod().Volume(volBar).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Volume(volFoo).Obj()),
+			legacyVolumeSources: true,
+			wantStatus:          nil,
 		},
-		"queue-has-same-claim": {
-			pod:          st.MakePod().Name("pod1").PVC("claim-rwop").Obj(),
-			oldObj:       st.MakePod().Name("pod2").PVC("claim-rwop").Obj(),
-			existingPods: []*v1.Pod{},
-			existingPVC:  &v1.PersistentVolumeClaim{},
-			expectedHint: framework.Queue,
-			expectedErr:  false,
+		{
+			name:                "legacy sources disabled skips the check",
+			pod:                 st.MakePod().Volume(volFoo).Obj(),
+			nodeInfo:            framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volFoo).Obj()),
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
 		},
-		"skip-no-same-claim": {
-			pod:          st.MakePod().Name("pod1").PVC("claim-1-rwop").Obj(),
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.LegacyVolumeSources = test.legacyVolumeSources
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestKnownSharedVolumes(t *testing.T) {
+	logLine := "known multi-writer volumes, which intentionally take no part in disk conflict checking"
+
+	tests := []struct {
+		name   string
+		volume v1.Volume
+	}{
+		{
+			name: "Quobyte",
+			volume: v1.Volume{
+				Name: "quobyte-vol",
+				VolumeSource: v1.VolumeSource{
+					Quobyte: &v1.QuobyteVolumeSource{Registry: "registry:7861", Volume: "shared"},
+				},
+			},
+		},
+		{
+			name: "CephFS",
+			volume: v1.Volume{
+				Name: "cephfs-vol",
+				VolumeSource: v1.VolumeSource{
+					CephFS: &v1.CephFSVolumeSource{Monitors: []string{"mon-1"}, Path: "/shared"},
+				},
+			},
+		},
+		{
+			name: "Glusterfs",
+			volume: v1.Volume{
+				Name: "glusterfs-vol",
+				VolumeSource: v1.VolumeSource{
+					Glusterfs: &v1.GlusterfsVolumeSource{EndpointsName: "glusterfs-cluster", Path: "shared"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := ktesting.NewLogger(t, ktesting.NewConfig(ktesting.BufferLogs(true), ktesting.Verbosity(5)))
+			ctx, cancel := context.WithCancel(klog.NewContext(context.Background(), logger))
+			defer cancel()
+			pod := st.MakePod().Volume(test.volume).Obj()
+			nodeInfo := framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(test.volume).Obj())
+
+			// Shared filesystems are multi-writer by design, so two pods
+			// mounting the same volume never conflict.
+			if !satisfyVolumeConflicts(pod, nodeInfo, true) {
+				t.Errorf("expected no conflict for two pods mounting the same %s volume", test.name)
+			}
+
+			p := newPlugin(ctx, t)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(framework.NewStatus(framework.Skip), preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+
+			underlier, ok := logger.GetSink().(ktesting.Underlier)
+			if !ok {
+				t.Fatalf("Expected a ktesting logger, got %T", logger.GetSink())
+			}
+			output := underlier.GetBuffer().String()
+			if !strings.Contains(output, logLine) {
+				t.Errorf("expected the skip to be logged, log output:\n%s", output)
+			}
+			if !strings.Contains(output, test.volume.Name) {
+				t.Errorf("expected the volume name in the log, log output:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestHaveOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a1   []string
+		a2   []string
+		want bool
+	}{
+		{
+			name: "both slices empty",
+		},
+		{
+			name: "one slice empty",
+			a2:   []string{"a", "b"},
+		},
+		{
+			name: "no common element",
+			a1:   []string{"a", "b"},
+			a2:   []string{"c", "d"},
+		},
+		{
+			name: "common element in small slices",
+			a1:   []string{"a", "b"},
+			a2:   []string{"c", "b"},
+			want: true,
+		},
+		{
+			name: "duplicate entries still overlap",
+			a1:   []string{"a", "a"},
+			a2:   []string{"b", "a", "a"},
+			want: true,
+		},
+		{
+			name: "common element past the small-slice threshold",
+			a1:   []string{"a", "b", "c", "d", "e", "f"},
+			a2:   []string{"x", "y", "z", "u", "v", "f"},
+			want: true,
+		},
+		{
+			name: "no common element past the small-slice threshold",
+			a1:   []string{"a", "b", "c", "d", "e", "f"},
+			a2:   []string{"t", "u", "v", "w", "x", "y"},
+		},
+		{
+			name: "small slice against a large one",
+			a1:   []string{"f"},
+			a2:   []string{"a", "b", "c", "d", "e", "f"},
+			want: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := haveOverlap(test.a1, test.a2); got != test.want {
+				t.Errorf("expected haveOverlap(%v, %v) to return %v, got %v", test.a1, test.a2, test.want, got)
+			}
+			// The result must not depend on the argument order.
+			if got := haveOverlap(test.a2, test.a1); got != test.want {
+				t.Errorf("expected haveOverlap(%v, %v) to return %v, got %v", test.a2, test.a1, test.want, got)
+			}
+		})
+	}
+}
+
+func BenchmarkHaveOverlap(b *testing.B) {
+	makeSlice := func(n int, prefix string) []string {
+		s := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			s = append(s, fmt.Sprintf("%s-%d", prefix, i))
+		}
+		return s
+	}
+
+	benchmarks := []struct {
+		name string
+		size int
+	}{
+		{name: "small slices", size: 3},
+		{name: "large slices", size: 32},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			// Disjoint slices exercise the full comparison.
+			a1 := makeSlice(bm.size, "mon-a")
+			a2 := makeSlice(bm.size, "mon-b")
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				haveOverlap(a1, a2)
+			}
+		})
+	}
+}
+
+func TestDiskConflictModes(t *testing.T) {
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
+				PDName: "foo",
+			},
+		},
+	}
+	pod := st.MakePod().Volume(volState).Obj()
+	conflictingNodeInfo := func() *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj())
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+		return nodeInfo
+	}
+
+	tests := []struct {
+		name                string
+		mode                config.DiskConflictMode
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "Enforce rejects a conflicting pod",
+			mode:                config.DiskConflictEnforce,
+			preFilterWantStatus: nil,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/pod-a"),
+		},
+		{
+			name:                "Warn admits a conflicting pod",
+			mode:                config.DiskConflictWarn,
+			preFilterWantStatus: nil,
+			wantStatus:          nil,
+		},
+		{
+			name:                "Off skips conflict checking entirely",
+			mode:                config.DiskConflictOff,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsAndListers(ctx, t, &config.VolumeRestrictionsArgs{DiskConflictMode: test.mode, UnknownCSIDriverPolicy: config.UnknownCSIDriverStrict}, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter skips, then Filter will not run.
+			if test.preFilterWantStatus == nil {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, conflictingNodeInfo())
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestEnabledDiskConflictCategories(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	awsVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"},
+		},
+	}
+	conflictingNodeInfo := func(volume v1.Volume) *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volume).Obj())
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+		return nodeInfo
+	}
+
+	tests := []struct {
+		name                string
+		enabledCategories   []string
+		volume              v1.Volume
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:              "a conflicting volume of an enabled category rejects the pod",
+			enabledCategories: []string{"GCEPersistentDisk"},
+			volume:            gceVolume,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/pod-a"),
+		},
+		{
+			name:                "a conflicting volume of a disabled category is ignored",
+			enabledCategories:   []string{"GCEPersistentDisk"},
+			volume:              awsVolume,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:                "an empty list disables every disk conflict check",
+			enabledCategories:   []string{},
+			volume:              gceVolume,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.EnabledDiskConflictCategories = test.enabledCategories
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			pod := st.MakePod().Volume(test.volume).Obj()
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter skips, then Filter will not run.
+			if test.preFilterWantStatus == nil {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, conflictingNodeInfo(test.volume))
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestExemptVolumeIdentities(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	conflictingNodeInfo := func(volume v1.Volume) *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volume).Obj())
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+		return nodeInfo
+	}
+
+	tests := []struct {
+		name                string
+		exemptIdentities    []string
+		volume              v1.Volume
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:   "a conflicting volume is rejected without exemptions",
+			volume: gceVolume,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/pod-a"),
+		},
+		{
+			name:                "an exempt identity bypasses the conflict check",
+			exemptIdentities:    []string{"gce://foo"},
+			volume:              gceVolume,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:             "an exemption for a different identity does not apply",
+			exemptIdentities: []string{"gce://bar"},
+			volume:           gceVolume,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/pod-a"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.ExemptVolumeIdentities = test.exemptIdentities
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			pod := st.MakePod().Volume(test.volume).Obj()
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter skips, then Filter will not run.
+			if test.preFilterWantStatus == nil {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, conflictingNodeInfo(test.volume))
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestIgnoreTerminatingPods(t *testing.T) {
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	pod := st.MakePod().Volume(volState).Obj()
+	terminatingConflictingNodeInfo := func() *framework.NodeInfo {
+		holder := st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj()
+		holder.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		nodeInfo := framework.NewNodeInfo(holder)
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+		return nodeInfo
+	}
+
+	tests := []struct {
+		name                  string
+		ignoreTerminatingPods bool
+		wantStatus            *framework.Status
+	}{
+		{
+			name: "by default a terminating pod still holds its volumes",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/pod-a"),
+		},
+		{
+			name:                  "terminating pods are excluded when enabled",
+			ignoreTerminatingPods: true,
+			wantStatus:            nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.IgnoreTerminatingPods = test.ignoreTerminatingPods
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, terminatingConflictingNodeInfo())
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestIgnoreMirrorPods(t *testing.T) {
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	hostPathVol := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			HostPath: &v1.HostPathVolumeSource{Path: "/etc/kubernetes"},
+		},
+	}
+	pod := st.MakePod().Volume(volState).Obj()
+	mirrorConflictingNodeInfo := func() *framework.NodeInfo {
+		holder := st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(hostPathVol).Volume(volState).Obj()
+		holder.Annotations = map[string]string{v1.MirrorPodAnnotationKey: "mirror"}
+		nodeInfo := framework.NewNodeInfo(holder)
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+		return nodeInfo
+	}
+
+	tests := []struct {
+		name             string
+		ignoreMirrorPods bool
+		wantStatus       *framework.Status
+	}{
+		{
+			name: "by default a mirror pod still holds its volumes",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/pod-a"),
+		},
+		{
+			name:             "mirror pods are excluded when enabled",
+			ignoreMirrorPods: true,
+			wantStatus:       nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.IgnoreMirrorPods = test.ignoreMirrorPods
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, mirrorConflictingNodeInfo())
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestConflictEvents(t *testing.T) {
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	conflictingNodeInfo := func() *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj())
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+		return nodeInfo
+	}
+	newPluginWithRecorder := func(ctx context.Context, t *testing.T, args *config.VolumeRestrictionsArgs, recorder events.EventRecorder) framework.Plugin {
+		informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewClientset(), 0)
+		fh, err := frameworkruntime.NewFramework(ctx, nil, nil,
+			frameworkruntime.WithSnapshotSharedLister(cache.NewSnapshot(nil, nil)),
+			frameworkruntime.WithInformerFactory(informerFactory),
+			frameworkruntime.WithEventRecorder(recorder))
+		if err != nil {
+			t.Fatalf("Failed creating framework runtime: %v", err)
+		}
+		p, err := New(ctx, args, fh, feature.Features{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		informerFactory.Start(ctx.Done())
+		informerFactory.WaitForCacheSync(ctx.Done())
+		return p
+	}
+	receivedEvent := func(recorder *events.FakeRecorder) (string, bool) {
+		select {
+		case event := <-recorder.Events:
+			return event, true
+		default:
+			return "", false
+		}
+	}
+	runFilter := func(ctx context.Context, t *testing.T, p framework.Plugin, pod *v1.Pod) {
+		cycleState := framework.NewCycleState()
+		if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+			t.Fatalf("Unexpected PreFilter status: %v", status)
+		}
+		status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, conflictingNodeInfo())
+		if status.Code() != framework.Unschedulable {
+			t.Fatalf("Expected the pod to be rejected, got status: %v", status)
+		}
+	}
+	pod := st.MakePod().Name("pod-b").UID("pod-b").Volume(volState).Obj()
+	wantEvent := fmt.Sprintf("%s %s %s; %s; %s", v1.EventTypeWarning, conflictEventReason,
+		ErrReasonDiskConflict, ErrReasonMountLayerConflict, "GCEPersistentDisk volume gce://foo is held by pod default/pod-a")
+
+	t.Run("a conflict emits an event on the pod once per interval", func(t *testing.T) {
+		_, ctx := ktesting.NewTestContext(t)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		recorder := events.NewFakeRecorder(10)
+		args := getDefaultVolumeRestrictionsArgs()
+		args.EmitConflictEvents = true
+		p := newPluginWithRecorder(ctx, t, args, recorder)
+
+		runFilter(ctx, t, p, pod)
+		if event, ok := receivedEvent(recorder); !ok {
+			t.Fatal("Expected a conflict event to be recorded")
+		} else if event != wantEvent {
+			t.Errorf("Unexpected event: got %q, want %q", event, wantEvent)
+		}
+
+		// A rejection within the interval is rate-limited.
+		runFilter(ctx, t, p, pod)
+		if event, ok := receivedEvent(recorder); ok {
+			t.Errorf("Expected the second event to be rate-limited, got %q", event)
+		}
+
+		// Once the interval has passed the next rejection is reported again.
+		limiter := p.(*VolumeRestrictions).conflictEvents
+		limiter.now = func() time.Time { return time.Now().Add(2 * conflictEventInterval) }
+		runFilter(ctx, t, p, pod)
+		if _, ok := receivedEvent(recorder); !ok {
+			t.Error("Expected a conflict event after the rate-limit interval passed")
+		}
+	})
+
+	t.Run("no events unless enabled", func(t *testing.T) {
+		_, ctx := ktesting.NewTestContext(t)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		recorder := events.NewFakeRecorder(10)
+		p := newPluginWithRecorder(ctx, t, getDefaultVolumeRestrictionsArgs(), recorder)
+
+		runFilter(ctx, t, p, pod)
+		if event, ok := receivedEvent(recorder); ok {
+			t.Errorf("Expected no conflict event, got %q", event)
+		}
+	})
+}
+
+func TestConflictRejectionsMetric(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Metrics are inert until created, which normally happens on registration.
+	metrics.ConflictRejections.Create(nil)
+	before, err := testutil.GetCounterMetricValue(metrics.ConflictRejections.WithLabelValues("GCEPersistentDisk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	pod := st.MakePod().Volume(volState).Obj()
+	nodeInfo := framework.NewNodeInfo(st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(volState).Obj())
+	nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	p := newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), nil, nil, nil, nil)
+	cycleState := framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo); status.Code() != framework.Unschedulable {
+		t.Fatalf("Expected the pod to be rejected, got status: %v", status)
+	}
+
+	after, err := testutil.GetCounterMetricValue(metrics.ConflictRejections.WithLabelValues("GCEPersistentDisk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after != before+1 {
+		t.Errorf("Expected the GCEPersistentDisk conflict rejection counter to grow by one, before: %v, after: %v", before, after)
+	}
+}
+
+func TestUnknownCSIDriverPolicy(t *testing.T) {
+	blockMode := v1.PersistentVolumeBlock
+	makeCSIPV := func(name, handle string) *v1.PersistentVolume {
+		return &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1.PersistentVolumeSpec{
+				VolumeMode: &blockMode,
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{
+						Driver:       "disk.csi.example.com",
+						VolumeHandle: handle,
+					},
+				},
+			},
+		}
+	}
+	makePVC := func(name, volumeName string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName: volumeName,
+				VolumeMode: &blockMode,
+			},
+		}
+	}
+	makeClaimVolume := func(claimName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			Name: claimName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+					ReadOnly:  readOnly,
+				},
+			},
+		}
+	}
+
+	// pv-1 and pv-2 wrap the same CSI volume handle.
+	pvs := []*v1.PersistentVolume{
+		makeCSIPV("pv-1", "handle-1"),
+		makeCSIPV("pv-2", "handle-1"),
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("claim-1", "pv-1"),
+		makePVC("claim-2", "pv-2"),
+	}
+	registeredDriver := &storagev1.CSIDriver{
+		ObjectMeta: metav1.ObjectMeta{Name: "disk.csi.example.com"},
+	}
+
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	readOnlyPod := st.MakePod().Name("read-only-pod").Namespace(metav1.NamespaceDefault).Volume(makeClaimVolume("claim-1", true)).Obj()
+	existingPod := st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).PVC("claim-2").Node("node-1").Obj()
+	existingPodReadOnly := st.MakePod().Name("existing-ro").Namespace(metav1.NamespaceDefault).Volume(makeClaimVolume("claim-2", true)).Node("node-1").Obj()
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	tests := []struct {
+		name        string
+		policy      config.UnknownCSIDriverPolicy
+		csiDrivers  []*storagev1.CSIDriver
+		pod         *v1.Pod
+		existingPod *v1.Pod
+		wantStatus  *framework.Status
+	}{
+		{
+			name:        "strict rejects read-only sharing when the driver has no CSIDriver object",
+			policy:      config.UnknownCSIDriverStrict,
+			pod:         readOnlyPod,
+			existingPod: existingPodReadOnly,
+			wantStatus:  framework.NewStatus(framework.Unschedulable, ErrReasonCSIBlockVolumeConflict),
+		},
+		{
+			name:        "permissive leaves a driver without a CSIDriver object unchecked",
+			policy:      config.UnknownCSIDriverPermissive,
+			pod:         pod,
+			existingPod: existingPod,
+			wantStatus:  nil,
+		},
+		{
+			name:        "a registered driver allows read-only sharing under strict",
+			policy:      config.UnknownCSIDriverStrict,
+			csiDrivers:  []*storagev1.CSIDriver{registeredDriver},
+			pod:         readOnlyPod,
+			existingPod: existingPodReadOnly,
+			wantStatus:  nil,
+		},
+		{
+			name:        "a registered driver conflicts read-write regardless of policy",
+			policy:      config.UnknownCSIDriverPermissive,
+			csiDrivers:  []*storagev1.CSIDriver{registeredDriver},
+			pod:         pod,
+			existingPod: existingPod,
+			wantStatus:  framework.NewStatus(framework.Unschedulable, ErrReasonCSIBlockVolumeConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.UnknownCSIDriverPolicy = test.policy
+			p := newPluginWithArgsListersAndCSIDrivers(ctx, t, args, []*v1.Pod{test.existingPod}, []*v1.Node{node}, pvcs, pvs, test.csiDrivers)
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod); status != nil {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, framework.NewNodeInfo(test.existingPod))
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAccessModeConflicts(t *testing.T) {
+	// Required for querying lister for PVCs in the same namespace.
+	podWithOnePVC := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").Node("node-1").Obj()
+	podWithTwoPVCs := st.MakePod().Name("pod-with-two-pvcs").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").PVC("claim-with-rwop-2").Node("node-1").Obj()
+	podWithOneConflict := st.MakePod().Name("pod-with-one-conflict").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").Node("node-1").Obj()
+	podWithTwoConflicts := st.MakePod().Name("pod-with-two-conflicts").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwop-1").PVC("claim-with-rwop-2").Node("node-1").Obj()
+	// Required for querying lister for PVCs in the same namespace.
+	podWithReadWriteManyPVC := st.MakePod().Name("pod-with-rwx").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwx").Node("node-1").Obj()
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "node-1",
+		},
+	}
+
+	readWriteOncePodPVC1 := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-with-rwop-1",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	readWriteOncePodPVC2 := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-with-rwop-2",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	readWriteManyPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-with-rwx",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		nodeInfo            *framework.NodeInfo
+		existingPods        []*v1.Pod
+		existingNodes       []*v1.Node
+		existingPVCs        []*v1.PersistentVolumeClaim
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "nothing",
+			pod:                 &v1.Pod{},
+			nodeInfo:            framework.NewNodeInfo(),
+			existingPods:        []*v1.Pod{},
+			existingNodes:       []*v1.Node{},
+			existingPVCs:        []*v1.PersistentVolumeClaim{},
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
+		},
+		{
+			name:                "failed to get PVC",
+			pod:                 podWithOnePVC,
+			nodeInfo:            framework.NewNodeInfo(),
+			existingPods:        []*v1.Pod{},
+			existingNodes:       []*v1.Node{},
+			existingPVCs:        []*v1.PersistentVolumeClaim{},
+			preFilterWantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "persistentvolumeclaim \"claim-with-rwop-1\" not found"),
+			wantStatus:          nil,
+		},
+		{
+			name:                "no access mode conflict",
+			pod:                 podWithOnePVC,
+			nodeInfo:            framework.NewNodeInfo(podWithReadWriteManyPVC),
+			existingPods:        []*v1.Pod{podWithReadWriteManyPVC},
+			existingNodes:       []*v1.Node{node},
+			existingPVCs:        []*v1.PersistentVolumeClaim{readWriteOncePodPVC1, readWriteManyPVC},
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
+		},
+		{
+			name:                "access mode conflict, unschedulable",
+			pod:                 podWithOneConflict,
+			nodeInfo:            framework.NewNodeInfo(podWithOnePVC, podWithReadWriteManyPVC),
+			existingPods:        []*v1.Pod{podWithOnePVC, podWithReadWriteManyPVC},
+			existingNodes:       []*v1.Node{node},
+			existingPVCs:        []*v1.PersistentVolumeClaim{readWriteOncePodPVC1, readWriteManyPVC},
+			preFilterWantStatus: nil,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-with-rwop-1 is held by pod default/pod-with-one-pvc"),
+		},
+		{
+			name:                "two conflicts, unschedulable",
+			pod:                 podWithTwoConflicts,
+			nodeInfo:            framework.NewNodeInfo(podWithTwoPVCs, podWithReadWriteManyPVC),
+			existingPods:        []*v1.Pod{podWithTwoPVCs, podWithReadWriteManyPVC},
+			existingNodes:       []*v1.Node{node},
+			existingPVCs:        []*v1.PersistentVolumeClaim{readWriteOncePodPVC1, readWriteOncePodPVC2, readWriteManyPVC},
+			preFilterWantStatus: nil,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-with-rwop-1 is held by pod default/pod-with-two-pvcs",
+				"PersistentVolumeClaim default/claim-with-rwop-2 is held by pod default/pod-with-two-pvcs"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, test.existingPods, test.existingNodes, test.existingPVCs)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			// If PreFilter fails, then Filter will not run.
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyReadWriteOncePodHolders(t *testing.T) {
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj()
+	holder := st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Node("node-1").Obj()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	readWriteOncePodPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-rwop",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		verifyHolders       bool
+		holderExists        bool
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name: "stale holder reference blocks the pod by default",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-rwop is held by pod default/holder"),
+		},
+		{
+			name:                "stale holder reference is ignored when verification is enabled",
+			verifyHolders:       true,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:          "existing holder still blocks the pod",
+			verifyHolders: true,
+			holderExists:  true,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-rwop is held by pod default/holder"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.VerifyReadWriteOncePodHolders = test.verifyHolders
+			pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				return New(ctx, plArgs, fh, feature.Features{})
+			}
+			// The snapshot always names the holder; only the informer cache
+			// reflects whether the holding pod still exists.
+			snapshot := cache.NewSnapshot([]*v1.Pod{holder}, []*v1.Node{node})
+			objects := []runtime.Object{readWriteOncePodPVC}
+			if test.holderExists {
+				objects = append(objects, holder)
+			}
+			p := plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, args, snapshot, objects)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if test.preFilterWantStatus.IsSuccess() {
+				nodeInfo := framework.NewNodeInfo(holder)
+				nodeInfo.SetNode(node)
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+// assumedPodHandle wraps a framework.Handle with an AssumedPods
+// implementation, mimicking a handle backed by a scheduler cache that exposes
+// its assumed pods.
+type assumedPodHandle struct {
+	framework.Handle
+	assumed []*v1.Pod
+}
+
+func (h *assumedPodHandle) AssumedPods() []*v1.Pod {
+	return h.assumed
+}
+
+func TestAssumedPodConflicts(t *testing.T) {
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj()
+	assumedHolder := st.MakePod().Name("assumed-holder").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Node("node-1").Obj()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	readWriteOncePodPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-rwop",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		assumedPods         []*v1.Pod
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "no assumed pods leave the claim unheld",
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:        "an assumed pod referencing another claim leaves the claim unheld",
+			assumedPods: []*v1.Pod{st.MakePod().Name("assumed-other").Namespace(metav1.NamespaceDefault).PVC("claim-other").Node("node-1").Obj()},
+			// The assumed pod's claim is not the contended one, so there is
+			// nothing to filter and PreFilter short-circuits.
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:        "an assumed pod holding the claim blocks the pod",
+			assumedPods: []*v1.Pod{assumedHolder},
+			// The assumed holder is not part of any NodeInfo yet, so the
+			// rejection carries no holder detail.
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				return New(ctx, plArgs, &assumedPodHandle{Handle: fh, assumed: test.assumedPods}, feature.Features{})
+			}
+			// The snapshot does not report the claim as in use; only the
+			// assumed pods can make it contended.
+			snapshot := cache.NewSnapshot(nil, []*v1.Node{node})
+			p := plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, args, snapshot, []runtime.Object{readWriteOncePodPVC})
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if test.preFilterWantStatus.IsSuccess() {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(node)
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestEnforceReadWriteOncePerNode(t *testing.T) {
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwo").Obj()
+	holder := st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).PVC("claim-rwo").Node("node-1").Obj()
+	node1 := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	node2 := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}
+	readWriteOncePVC := func(phase v1.PersistentVolumeClaimPhase) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "claim-rwo",
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			},
+			Status: v1.PersistentVolumeClaimStatus{
+				Phase: phase,
+			},
+		}
+	}
+	nodeInfoFor := func(node *v1.Node, pods ...*v1.Pod) *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(pods...)
+		nodeInfo.SetNode(node)
+		return nodeInfo
+	}
+
+	tests := []struct {
+		name                string
+		enforce             bool
+		existingPods        []*v1.Pod
+		existingNodes       []*v1.Node
+		existingPVC         *v1.PersistentVolumeClaim
+		nodeInfo            *framework.NodeInfo
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "disabled by default",
+			existingPods:        []*v1.Pod{holder},
+			existingNodes:       []*v1.Node{node1, node2},
+			existingPVC:         readWriteOncePVC(v1.ClaimBound),
+			nodeInfo:            nodeInfoFor(node2),
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:          "claim in use on another node",
+			enforce:       true,
+			existingPods:  []*v1.Pod{holder},
+			existingNodes: []*v1.Node{node1, node2},
+			existingPVC:   readWriteOncePVC(v1.ClaimBound),
+			nodeInfo:      nodeInfoFor(node2),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOnceNodeConflict,
+				"PersistentVolumeClaim default/claim-rwo is in use on node node-1"),
+		},
+		{
+			name:          "claim in use on this node",
+			enforce:       true,
+			existingPods:  []*v1.Pod{holder},
+			existingNodes: []*v1.Node{node1, node2},
+			existingPVC:   readWriteOncePVC(v1.ClaimBound),
+			nodeInfo:      nodeInfoFor(node1, holder),
+			wantStatus:    nil,
+		},
+		{
+			name:                "claim not in use anywhere",
+			enforce:             true,
+			existingNodes:       []*v1.Node{node1, node2},
+			existingPVC:         readWriteOncePVC(v1.ClaimBound),
+			nodeInfo:            nodeInfoFor(node2),
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:                "unbound claims are ignored",
+			enforce:             true,
+			existingPods:        []*v1.Pod{holder},
+			existingNodes:       []*v1.Node{node1, node2},
+			existingPVC:         readWriteOncePVC(v1.ClaimPending),
+			nodeInfo:            nodeInfoFor(node2),
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.EnforceReadWriteOncePerNode = test.enforce
+			p := newPluginWithArgsAndListers(ctx, t, args, test.existingPods, test.existingNodes, []*v1.PersistentVolumeClaim{test.existingPVC}, nil)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if test.preFilterWantStatus.IsSuccess() {
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, test.nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestReadWriteOncePerNodePreFilterResult(t *testing.T) {
+	node1 := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	node2 := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}
+	readWriteOncePVC := func(name string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			},
+			Status: v1.PersistentVolumeClaimStatus{
+				Phase: v1.ClaimBound,
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		enforce      bool
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		wantResult   *framework.PreFilterResult
+		wantStatus   *framework.Status
+	}{
+		{
+			name:    "claim in use pins the pod to the node using it",
+			enforce: true,
+			pod:     st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-a").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).PVC("claim-a").Node("node-1").Obj(),
+			},
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-1")},
+		},
+		{
+			name: "enforcement disabled does not narrow",
+			pod:  st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-a").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).PVC("claim-a").Node("node-1").Obj(),
+			},
+			wantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:       "claim not in use anywhere does not narrow",
+			enforce:    true,
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-a").Obj(),
+			wantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:    "claims in use on different nodes can never be satisfied",
+			enforce: true,
+			pod:     st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-a").PVC("claim-b").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("holder-a").Namespace(metav1.NamespaceDefault).PVC("claim-a").Node("node-1").Obj(),
+				st.MakePod().Name("holder-b").Namespace(metav1.NamespaceDefault).PVC("claim-b").Node("node-2").Obj(),
+			},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonReadWriteOnceNodeConflict,
+				"the ReadWriteOnce PersistentVolumeClaims used by the pod are in use on different nodes"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.EnforceReadWriteOncePerNode = test.enforce
+			pvcs := []*v1.PersistentVolumeClaim{readWriteOncePVC("claim-a"), readWriteOncePVC("claim-b")}
+			p := newPluginWithArgsAndListers(ctx, t, args, test.existingPods, []*v1.Node{node1, node2}, pvcs, nil)
+			cycleState := framework.NewCycleState()
+			gotResult, gotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantResult, gotResult); diff != "" {
+				t.Errorf("Unexpected PreFilter result (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPreFilterExtensionsShortCircuit(t *testing.T) {
+	gceVol := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
+				PDName: "foo",
+			},
+		},
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	t.Run("callbacks are skipped for pods without ReadWriteOncePod claims", func(t *testing.T) {
+		_, ctx := ktesting.NewTestContext(t)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		pod := st.MakePod().Volume(gceVol).Obj()
+		existingPod := st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod-a").Volume(gceVol).Obj()
+		p := newPlugin(ctx, t)
+		cycleState := framework.NewCycleState()
+		if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); !status.IsSuccess() {
+			t.Fatalf("unexpected PreFilter status: %v", status)
+		}
+
+		nodeInfo := framework.NewNodeInfo(existingPod)
+		nodeInfo.SetNode(node)
+		podInfo, err := framework.NewPodInfo(existingPod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := p.(framework.PreFilterPlugin).PreFilterExtensions().AddPod(ctx, cycleState, pod, podInfo, nodeInfo); !status.IsSuccess() {
+			t.Fatalf("unexpected AddPod status: %v", status)
+		}
+		state, err := getPreFilterState(cycleState)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.nodeVolumes != nil {
+			t.Errorf("expected the AddPod callback to be short-circuited, but it maintained node volume identities")
+		}
+
+		// Disk conflicts are still filtered; the identities are rebuilt lazily
+		// from the nodeInfo Filter is handed.
+		wantStatus := framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+			"GCEPersistentDisk volume gce://foo is held by pod default/pod-a")
+		gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo)
+		if diff := cmp.Diff(wantStatus, gotStatus); diff != "" {
+			t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+		}
+	})
+
+	t.Run("callbacks still maintain ReadWriteOncePod conflict counts", func(t *testing.T) {
+		_, ctx := ktesting.NewTestContext(t)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj()
+		holder := st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Node("node-1").Obj()
+		readWriteOncePodPVC := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "claim-rwop",
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+			},
+		}
+		p := newPluginWithListers(ctx, t, []*v1.Pod{holder}, []*v1.Node{node}, []*v1.PersistentVolumeClaim{readWriteOncePodPVC})
+		cycleState := framework.NewCycleState()
+		if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); !status.IsSuccess() {
+			t.Fatalf("unexpected PreFilter status: %v", status)
+		}
+
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		podInfo, err := framework.NewPodInfo(holder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := p.(framework.PreFilterPlugin).PreFilterExtensions().RemovePod(ctx, cycleState, pod, podInfo, nodeInfo); !status.IsSuccess() {
+			t.Fatalf("unexpected RemovePod status: %v", status)
+		}
+		state, err := getPreFilterState(cycleState)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.conflictingPVCRefCount != 0 {
+			t.Errorf("expected the RemovePod callback to release the conflicting claim reference, got %d", state.conflictingPVCRefCount)
+		}
+	})
+}
+
+func TestConflictingPVCRefCountDeduplicatesClaims(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj()
+	// The holder mounts the same claim under two volume entries; removing it
+	// must release a single claim reference, not one per entry.
+	holder := st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).
+		Volume(v1.Volume{Name: "vol-a", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "claim-rwop"}}}).
+		Volume(v1.Volume{Name: "vol-b", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "claim-rwop"}}}).
+		Node("node-1").Obj()
+	readWriteOncePodPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-rwop",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	p := newPluginWithListers(ctx, t, []*v1.Pod{holder}, []*v1.Node{node}, []*v1.PersistentVolumeClaim{readWriteOncePodPVC})
+	cycleState := framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); !status.IsSuccess() {
+		t.Fatalf("unexpected PreFilter status: %v", status)
+	}
+	state, err := getPreFilterState(cycleState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.conflictingPVCRefCount != 1 {
+		t.Fatalf("expected the claim to be counted once, got %d", state.conflictingPVCRefCount)
+	}
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	podInfo, err := framework.NewPodInfo(holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := p.(framework.PreFilterPlugin).PreFilterExtensions().RemovePod(ctx, cycleState, pod, podInfo, nodeInfo); !status.IsSuccess() {
+		t.Fatalf("unexpected RemovePod status: %v", status)
+	}
+	if state.conflictingPVCRefCount != 0 {
+		t.Errorf("expected removing the holder to release exactly one claim reference, got %d", state.conflictingPVCRefCount)
+	}
+}
+
+func TestConflictReasonAggregation(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	iscsiVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{IQN: "iqn.2016-12.world.srv:storage.target01"},
+		},
+	}
+	podWithGCEVolume := st.MakePod().Name("pod-with-gce").Volume(gceVolume).Obj()
+	podWithISCSIVolume := st.MakePod().Name("pod-with-iscsi").Volume(iscsiVolume).Obj()
+	pod := st.MakePod().Name("pod").Volume(gceVolume).Volume(iscsiVolume).Obj()
+
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	args := &config.VolumeRestrictionsArgs{
+		DiskConflictMode:                config.DiskConflictEnforce,
+		UnknownCSIDriverPolicy:          config.UnknownCSIDriverStrict,
+		EnableConflictReasonAggregation: true,
+	}
+	p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
+
+	cycleState := framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	// Two nodes fail due to the GCE disk, one due to the ISCSI volume.
+	for _, nodeInfo := range []*framework.NodeInfo{
+		framework.NewNodeInfo(podWithGCEVolume),
+		framework.NewNodeInfo(podWithGCEVolume),
+		framework.NewNodeInfo(podWithISCSIVolume),
+	} {
+		if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo); status.Code() != framework.Unschedulable {
+			t.Fatalf("Expected Unschedulable, got: %v", status)
+		}
+	}
+
+	wantCounts := map[string]int64{
+		"GCEPersistentDisk": 2,
+		"ISCSI":             1,
+	}
+	gotCounts := p.(*VolumeRestrictions).ConflictReasonCounts(cycleState)
+	if diff := cmp.Diff(wantCounts, gotCounts); diff != "" {
+		t.Errorf("Unexpected conflict reason counts (-want, +got): %s", diff)
+	}
+
+	// With aggregation disabled no counts are reported.
+	p = newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), nil, nil, nil, nil)
+	cycleState = framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); status != nil {
+		t.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+	if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, framework.NewNodeInfo(podWithGCEVolume)); status.Code() != framework.Unschedulable {
+		t.Fatalf("Expected Unschedulable, got: %v", status)
+	}
+	if gotCounts := p.(*VolumeRestrictions).ConflictReasonCounts(cycleState); gotCounts != nil {
+		t.Errorf("Expected nil conflict reason counts, got: %v", gotCounts)
+	}
+}
+
+func TestConflictDelta(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	iscsiVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{IQN: "iqn.2016-12.world.srv:storage.target01"},
+		},
+	}
+	podWithGCEVolume := st.MakePod().Name("pod-with-gce").Volume(gceVolume).Obj()
+	podWithISCSIVolume := st.MakePod().Name("pod-with-iscsi").Volume(iscsiVolume).Obj()
+	pod := st.MakePod().Name("pod").Volume(gceVolume).Volume(iscsiVolume).Obj()
+
+	gceConflict := ConflictDetail{Category: "GCEPersistentDisk", VolumeKey: "gce://foo"}
+	iscsiConflict := ConflictDetail{Category: "ISCSI", VolumeKey: "iscsi://iqn.2016-12.world.srv:storage.target01"}
+
+	testcases := map[string]struct {
+		oldPods, newPods       []*v1.Pod
+		wantAdded, wantRemoved []ConflictDetail
+	}{
+		"removing the conflicting pod removes the conflict": {
+			oldPods:     []*v1.Pod{podWithGCEVolume},
+			wantRemoved: []ConflictDetail{gceConflict},
+		},
+		"adding a conflicting pod adds the conflict": {
+			newPods:   []*v1.Pod{podWithISCSIVolume},
+			wantAdded: []ConflictDetail{iscsiConflict},
+		},
+		"a conflict present on both sides is reported on neither": {
+			oldPods:   []*v1.Pod{podWithGCEVolume},
+			newPods:   []*v1.Pod{podWithGCEVolume, podWithISCSIVolume},
+			wantAdded: []ConflictDetail{iscsiConflict},
+		},
+		"no conflicts on either side": {},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPlugin(ctx, t)
+
+			added, removed := p.(*VolumeRestrictions).ConflictDelta(pod,
+				framework.NewNodeInfo(tc.oldPods...), framework.NewNodeInfo(tc.newPods...))
+			if diff := cmp.Diff(tc.wantAdded, added); diff != "" {
+				t.Errorf("Unexpected added conflicts (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantRemoved, removed); diff != "" {
+				t.Errorf("Unexpected removed conflicts (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPreemptionExplanation(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	rwopPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-rwop",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).Volume(gceVolume).PVC("claim-rwop").Obj()
+	diskVictim := st.MakePod().Name("disk-victim").Namespace(metav1.NamespaceDefault).Volume(gceVolume).Node("node-1").Obj()
+	claimVictim := st.MakePod().Name("claim-victim").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Node("node-1").Obj()
+	bystander := st.MakePod().Name("bystander").Namespace(metav1.NamespaceDefault).Node("node-1").Obj()
+
+	testcases := map[string]struct {
+		victims []*v1.Pod
+		want    string
+	}{
+		"a victim holding a contended volume is explained": {
+			victims: []*v1.Pod{diskVictim},
+			want:    "preempting pod default/disk-victim frees GCEPersistentDisk volume gce://foo",
+		},
+		"a victim holding a ReadWriteOncePod claim is explained": {
+			victims: []*v1.Pod{claimVictim},
+			want:    "preempting pod default/claim-victim frees ReadWriteOncePod claim default/claim-rwop",
+		},
+		"victims holding nothing the pod waits on are omitted": {
+			victims: []*v1.Pod{diskVictim, bystander},
+			want:    "preempting pod default/disk-victim frees GCEPersistentDisk volume gce://foo",
+		},
+		"no victim holds anything": {
+			victims: []*v1.Pod{bystander},
+			want:    "",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, tc.victims, nil, []*v1.PersistentVolumeClaim{rwopPVC})
+			nodeInfo := framework.NewNodeInfo(tc.victims...)
+			nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+			got := p.(*VolumeRestrictions).PreemptionExplanation(pod, nodeInfo, tc.victims)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected explanation (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestPVNodeAffinityConflicts(t *testing.T) {
+	makeNode := func(name, zone string) *v1.Node {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if zone != "" {
+			node.Labels = map[string]string{"zone": zone}
+		}
+		return node
+	}
+	makePV := func(name, zone string) *v1.PersistentVolume {
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if zone != "" {
+			pv.Spec.NodeAffinity = &v1.VolumeNodeAffinity{
+				Required: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{
+							MatchExpressions: []v1.NodeSelectorRequirement{
+								{
+									Key:      "zone",
+									Operator: v1.NodeSelectorOpIn,
+									Values:   []string{zone},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+		return pv
+	}
+	makePVC := func(name, volumeName string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+				VolumeName:  volumeName,
+			},
+		}
+	}
+
+	nodes := []*v1.Node{makeNode("node-a", "a"), makeNode("node-b", "b"), makeNode("node-c", "")}
+	pvs := []*v1.PersistentVolume{makePV("pv-zone-a", "a"), makePV("pv-zone-a-2", "a"), makePV("pv-zone-b", "b"), makePV("pv-no-affinity", "")}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("claim-zone-a", "pv-zone-a"),
+		makePVC("claim-zone-a-2", "pv-zone-a-2"),
+		makePVC("claim-zone-b", "pv-zone-b"),
+		makePVC("claim-no-affinity", "pv-no-affinity"),
+		makePVC("claim-unbound", ""),
+	}
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		wantResult *framework.PreFilterResult
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "bound PV without node affinity",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-no-affinity").Obj(),
+			wantResult: nil,
+			// Bound claims disable Skip so that Filter can check volume modes.
+			wantStatus: nil,
+		},
+		{
+			name:       "unbound claim is left to volume binding",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-unbound").Obj(),
+			wantResult: nil,
+			wantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:       "single PV with node affinity narrows the candidates",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-zone-a").Obj(),
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-a")},
+			wantStatus: nil,
+		},
+		{
+			name:       "compatible PV node affinities",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-zone-a").PVC("claim-zone-a-2").Obj(),
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-a")},
+			wantStatus: nil,
+		},
+		{
+			name:       "contradictory PV node affinities",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-zone-a").PVC("claim-zone-b").Obj(),
+			wantResult: nil,
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPVNodeAffinityConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), nil, nodes, pvcs, pvs)
+			cycleState := framework.NewCycleState()
+			gotResult, gotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantResult, gotResult); diff != "" {
+				t.Errorf("Unexpected PreFilter result (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRegionalPDZoneNarrowing(t *testing.T) {
+	makeNode := func(name, zoneLabel, zone string) *v1.Node {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		if zoneLabel != "" {
+			node.Labels = map[string]string{zoneLabel: zone}
+		}
+		return node
+	}
+	makeGCEPV := func(name, zones string) *v1.PersistentVolume {
+		pv := &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: v1.PersistentVolumeSpec{
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: name},
+				},
+			},
+		}
+		if zones != "" {
+			pv.Labels = map[string]string{v1.LabelTopologyZone: zones}
+		}
+		return pv
+	}
+	makePVC := func(name, volumeName string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+				VolumeName:  volumeName,
+			},
+		}
+	}
+
+	nodes := []*v1.Node{
+		makeNode("node-a", v1.LabelTopologyZone, "us-central1-a"),
+		makeNode("node-b", v1.LabelTopologyZone, "us-central1-b"),
+		makeNode("node-c", v1.LabelTopologyZone, "us-central1-c"),
+		// Older nodes may still carry only the beta zone label.
+		makeNode("node-legacy", v1.LabelFailureDomainBetaZone, "us-central1-b"),
+		makeNode("node-no-zone", "", ""),
+	}
+	pvs := []*v1.PersistentVolume{
+		makeGCEPV("pd-zonal", "us-central1-a"),
+		// A regional PD is labeled with both of its zones and can attach in either.
+		makeGCEPV("pd-regional", "us-central1-a__us-central1-b"),
+		makeGCEPV("pd-remote", "europe-west1-b__europe-west1-c"),
+		makeGCEPV("pd-unlabeled", ""),
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("claim-zonal", "pd-zonal"),
+		makePVC("claim-regional", "pd-regional"),
+		makePVC("claim-remote", "pd-remote"),
+		makePVC("claim-unlabeled", "pd-unlabeled"),
+	}
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		wantResult *framework.PreFilterResult
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "zonal PD narrows to its one zone",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-zonal").Obj(),
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-a")},
+			wantStatus: nil,
+		},
+		{
+			name:       "regional PD keeps both of its zones feasible",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-regional").Obj(),
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-a", "node-b", "node-legacy")},
+			wantStatus: nil,
+		},
+		{
+			name:       "regional and zonal PD intersect to the shared zone",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-regional").PVC("claim-zonal").Obj(),
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-a")},
+			wantStatus: nil,
+		},
+		{
+			name:       "PD without a zone label does not narrow",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-unlabeled").Obj(),
+			wantResult: nil,
+			// Bound claims disable Skip so that Filter can check volume modes.
+			wantStatus: nil,
+		},
+		{
+			name:       "regional PD with no nodes in its zones",
+			pod:        st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-remote").Obj(),
+			wantResult: nil,
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonGCEPDZoneConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), nil, nodes, pvcs, pvs)
+			cycleState := framework.NewCycleState()
+			gotResult, gotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantResult, gotResult); diff != "" {
+				t.Errorf("Unexpected PreFilter result (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestEBSAttachmentNarrowing(t *testing.T) {
+	makeNode := func(name string, unschedulable bool, ready v1.ConditionStatus, attachedVolumeIDs ...string) *v1.Node {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Unschedulable: unschedulable},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+			},
+		}
+		for _, volumeID := range attachedVolumeIDs {
+			node.Status.VolumesAttached = append(node.Status.VolumesAttached, v1.AttachedVolume{
+				Name: v1.UniqueVolumeName("kubernetes.io/aws-ebs/aws://us-east-1a/" + volumeID),
+			})
+		}
+		return node
+	}
+	ebsVolume := func(volumeID string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: volumeID},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		nodes      []*v1.Node
+		wantResult *framework.PreFilterResult
+		wantStatus *framework.Status
+	}{
+		{
+			name: "volume attached elsewhere narrows to that node",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-1")).Obj(),
+			nodes: []*v1.Node{
+				makeNode("node-a", false, v1.ConditionTrue, "vol-1"),
+				makeNode("node-b", false, v1.ConditionTrue),
+			},
+			wantResult: &framework.PreFilterResult{NodeNames: sets.New("node-a")},
+		},
+		{
+			name: "detached volume leaves all nodes as candidates",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-1")).Obj(),
+			nodes: []*v1.Node{
+				makeNode("node-a", false, v1.ConditionTrue),
+				makeNode("node-b", false, v1.ConditionTrue),
+			},
+		},
+		{
+			name: "volume attached to a cordoned node",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-1")).Obj(),
+			nodes: []*v1.Node{
+				makeNode("node-a", true, v1.ConditionTrue, "vol-1"),
+				makeNode("node-b", false, v1.ConditionTrue),
+			},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEBSVolumeAttachedToUnavailableNode),
+		},
+		{
+			name: "volume attached to a not ready node",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-1")).Obj(),
+			nodes: []*v1.Node{
+				makeNode("node-a", false, v1.ConditionFalse, "vol-1"),
+				makeNode("node-b", false, v1.ConditionTrue),
+			},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEBSVolumeAttachedToUnavailableNode),
+		},
+		{
+			name: "two volumes attached to different nodes",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-1")).Volume(ebsVolume("vol-2")).Obj(),
+			nodes: []*v1.Node{
+				makeNode("node-a", false, v1.ConditionTrue, "vol-1"),
+				makeNode("node-b", false, v1.ConditionTrue, "vol-2"),
+			},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEBSVolumeAttachedElsewhere),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, nil, test.nodes, nil)
+			cycleState := framework.NewCycleState()
+			gotResult, gotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantResult, gotResult); diff != "" {
+				t.Errorf("Unexpected PreFilter result (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFeasibleNodesForVolumes(t *testing.T) {
+	ebsVolume := func(volumeID string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: volumeID},
+			},
+		}
+	}
+	gceVolume := func(pdName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: pdName, ReadOnly: readOnly},
+			},
+		}
+	}
+	makeNodeInfo := func(name string, pods ...*v1.Pod) *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(pods...)
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		return nodeInfo
+	}
+	existingPod := st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").Volume(ebsVolume("vol-1")).Obj()
+	nodes := []*framework.NodeInfo{
+		makeNodeInfo("node-1", existingPod),
+		makeNodeInfo("node-2"),
+		makeNodeInfo("node-3"),
+	}
+
+	tests := []struct {
+		name            string
+		pod             *v1.Pod
+		attachmentState AttachmentState
+		want            sets.Set[string]
+	}{
+		{
+			name: "mount conflict excludes the node",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-1")).Obj(),
+			want: sets.New("node-2", "node-3"),
+		},
+		{
+			name:            "attached EBS volume pins the pod to its node",
+			pod:             st.MakePod().Name("pod").Volume(ebsVolume("vol-2")).Obj(),
+			attachmentState: AttachmentState{"aws://vol-2": "node-3"},
+			want:            sets.New("node-3"),
+		},
+		{
+			name: "volumes attached to different nodes leave no feasible node",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-2")).Volume(ebsVolume("vol-3")).Obj(),
+			attachmentState: AttachmentState{
+				"aws://vol-2": "node-2",
+				"aws://vol-3": "node-3",
+			},
+			want: sets.New[string](),
+		},
+		{
+			name:            "read-write GCE disk behaves single-attach",
+			pod:             st.MakePod().Name("pod").Volume(gceVolume("foo", false)).Obj(),
+			attachmentState: AttachmentState{"gce://foo": "node-2"},
+			want:            sets.New("node-2"),
+		},
+		{
+			name:            "read-only GCE disk ignores the attachment state",
+			pod:             st.MakePod().Name("pod").Volume(gceVolume("foo", true)).Obj(),
+			attachmentState: AttachmentState{"gce://foo": "node-2"},
+			want:            sets.New("node-1", "node-2", "node-3"),
+		},
+		{
+			name: "detached volumes leave all nodes feasible",
+			pod:  st.MakePod().Name("pod").Volume(ebsVolume("vol-2")).Obj(),
+			want: sets.New("node-1", "node-2", "node-3"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := FeasibleNodesForVolumes(test.pod, nodes, test.attachmentState)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected feasible nodes (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_isSchedulableAfterPodDeleted(t *testing.T) {
+	GCEDiskVolState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
+				PDName: "foo",
+			},
+		},
+	}
+	GCEDiskVolState2 := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
+				PDName: "bar",
+			},
+		},
+	}
+
+	AWSDiskVolState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
+				VolumeID: "foo",
+			},
+		},
+	}
+	AWSDiskVolState2 := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
+				VolumeID: "bar",
+			},
+		},
+	}
+
+	RBDDiskVolState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			RBD: &v1.RBDVolumeSource{
+				CephMonitors: []string{"a", "b"},
+				RBDPool:      "foo",
+				RBDImage:     "bar",
+				FSType:       "ext4",
+			},
+		},
+	}
+	RBDDiskVolState2 := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			RBD: &v1.RBDVolumeSource{
+				CephMonitors: []string{"c", "d"},
+				RBDPool:      "foo",
+				RBDImage:     "bar",
+				FSType:       "ext4",
+			},
+		},
+	}
+
+	ISCSIDiskVolState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{
+				TargetPortal: "127.0.0.1:3260",
+				IQN:          "iqn.2016-12.server:storage.target01",
+				FSType:       "ext4",
+				Lun:          0,
+			},
+		},
+	}
+	ISCSIDiskVolState2 := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{
+				TargetPortal: "127.0.0.1:3260",
+				IQN:          "iqn.2017-12.server:storage.target01",
+				FSType:       "ext4",
+				Lun:          0,
+			},
+		},
+	}
+
+	podGCEDisk := st.MakePod().Volume(GCEDiskVolState).Obj()
+	podGCEDiskConflicts := st.MakePod().Volume(GCEDiskVolState).Obj()
+	podGCEDiskNoConflicts := st.MakePod().Volume(GCEDiskVolState2).Obj()
+
+	podAWSDisk := st.MakePod().Volume(AWSDiskVolState).Obj()
+	podAWSDiskConflicts := st.MakePod().Volume(AWSDiskVolState).Obj()
+	podAWSDiskNoConflicts := st.MakePod().Volume(AWSDiskVolState2).Obj()
+
+	podRBDDiskDisk := st.MakePod().Volume(RBDDiskVolState).Obj()
+	podRBDDiskDiskConflicts := st.MakePod().Volume(RBDDiskVolState).Obj()
+	podRBDDiskNoConflicts := st.MakePod().Volume(RBDDiskVolState2).Obj()
+
+	podISCSIDiskDisk := st.MakePod().Volume(ISCSIDiskVolState).Obj()
+	podISCSIDiskConflicts := st.MakePod().Volume(ISCSIDiskVolState).Obj()
+	podISCSIDiskNoConflicts := st.MakePod().Volume(ISCSIDiskVolState2).Obj()
+
+	testcases := map[string]struct {
+		pod                *v1.Pod
+		oldObj, newObj     interface{}
+		existingPods       []*v1.Pod
+		existingPVC        *v1.PersistentVolumeClaim
+		crossNamespaceRefs bool
+		expectedHint       framework.QueueingHint
+		expectedErr        bool
+	}{
+		"queue-new-object-gcedisk-conflict": {
+			pod:          podGCEDisk,
+			oldObj:       podGCEDiskConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.Queue,
+			expectedErr:  false,
+		},
+		"skip-new-object-gcedisk-no-conflict": {
+			pod:          podGCEDisk,
+			oldObj:       podGCEDiskNoConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"queue-new-object-awsdisk-conflict": {
+			pod:          podAWSDisk,
+			oldObj:       podAWSDiskConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.Queue,
+			expectedErr:  false,
+		},
+		"skip-new-object-awsdisk-no-conflict": {
+			pod:          podAWSDisk,
+			oldObj:       podAWSDiskNoConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"queue-new-object-rbddisk-conflict": {
+			pod:          podRBDDiskDisk,
+			oldObj:       podRBDDiskDiskConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.Queue,
+			expectedErr:  false,
+		},
+		"skip-new-object-rbddisk-no-conflict": {
+			pod:          podRBDDiskDisk,
+			oldObj:       podRBDDiskNoConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"queue-new-object-iscsidisk-conflict": {
+			pod:          podISCSIDiskDisk,
+			oldObj:       podISCSIDiskConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.Queue,
+			expectedErr:  false,
+		},
+		"skip-new-object-iscsidisk-no-conflict": {
+			pod:          podISCSIDiskDisk,
+			oldObj:       podISCSIDiskNoConflicts,
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"queue-has-same-claim": {
+			pod:          st.MakePod().Name("pod1").PVC("claim-rwop").Obj(),
+			oldObj:       st.MakePod().Name("pod2").PVC("claim-rwop").Obj(),
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.Queue,
+			expectedErr:  false,
+		},
+		"skip-no-same-claim": {
+			pod:          st.MakePod().Name("pod1").PVC("claim-1-rwop").Obj(),
 			oldObj:       st.MakePod().Name("pod2").PVC("claim-2-rwop").Obj(),
 			existingPods: []*v1.Pod{},
 			existingPVC:  &v1.PersistentVolumeClaim{},
 			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+			expectedErr:  false,
+		},
+		"queue-cross-namespace-same-claim": {
+			pod:                st.MakePod().Name("pod1").Namespace(metav1.NamespaceDefault).PVC("other/claim-x").Obj(),
+			oldObj:             st.MakePod().Name("pod2").Namespace("other").PVC("claim-x").Obj(),
+			existingPods:       []*v1.Pod{},
+			existingPVC:        &v1.PersistentVolumeClaim{},
+			crossNamespaceRefs: true,
+			expectedHint:       framework.Queue,
+			expectedErr:        false,
+		},
+		"skip-cross-namespace-same-claim-gate-disabled": {
+			pod:          st.MakePod().Name("pod1").Namespace(metav1.NamespaceDefault).PVC("other/claim-x").Obj(),
+			oldObj:       st.MakePod().Name("pod2").Namespace("other").PVC("claim-x").Obj(),
+			existingPods: []*v1.Pod{},
+			existingPVC:  &v1.PersistentVolumeClaim{},
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			var p framework.Plugin
+			if tc.crossNamespaceRefs {
+				p = newPluginWithCrossNamespaceRefs(ctx, t, tc.existingPods, []*v1.PersistentVolumeClaim{tc.existingPVC})
+			} else {
+				p = newPluginWithListers(ctx, t, tc.existingPods, nil, []*v1.PersistentVolumeClaim{tc.existingPVC})
+			}
+
+			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterPodDeleted(logger, tc.pod, tc.oldObj, nil)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("Expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
+				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_isSchedulableAfterPersistentVolumeClaimChange(t *testing.T) {
+	podWithOnePVC := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwx-1").Node("node-1").Obj()
+	podWithTwoPVCs := st.MakePod().Name("pod-with-two-pvcs").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwx-1").PVC("claim-with-rwx-2").Node("node-1").Obj()
+	podWithNotEqualNamespace := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceSystem).PVC("claim-with-rwx-1").Node("claim-with-rwx-2").Obj()
+	podWithCrossNamespacePVC := st.MakePod().Name("pod-with-cross-namespace-pvc").Namespace(metav1.NamespaceDefault).PVC("other/claim-x").Node("node-1").Obj()
+
+	PVC1 := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-with-rwx-1",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		},
+	}
+
+	PVC2 := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "claim-with-rwx-2",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		},
+	}
+
+	crossNamespacePVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other",
+			Name:      "claim-x",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		},
+	}
+
+	testcases := map[string]struct {
+		existingPods       []*v1.Pod
+		pod                *v1.Pod
+		oldObj, newObj     interface{}
+		crossNamespaceRefs bool
+		expectedHint       framework.QueueingHint
+		expectedErr        bool
+	}{
+		"queue-new-object-pvc-belong-pod": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithTwoPVCs,
+			newObj:       PVC1,
+			expectedHint: framework.Queue,
+			expectedErr:  false,
+		},
+		"skip-new-object-unused": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithOnePVC,
+			newObj:       PVC2,
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"skip-nil-old-object": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithOnePVC,
+			newObj:       PVC2,
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"skip-new-object-not-belong-pod": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithOnePVC,
+			newObj:       PVC2,
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"skip-new-object-namespace-not-equal-pod": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithNotEqualNamespace,
+			newObj:       PVC1,
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"skip-deleted-object": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithOnePVC,
+			oldObj:       PVC1,
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+		"queue-new-object-cross-namespace-reference": {
+			existingPods:       []*v1.Pod{},
+			pod:                podWithCrossNamespacePVC,
+			newObj:             crossNamespacePVC,
+			crossNamespaceRefs: true,
+			expectedHint:       framework.Queue,
+			expectedErr:        false,
+		},
+		"skip-new-object-cross-namespace-reference-gate-disabled": {
+			existingPods: []*v1.Pod{},
+			pod:          podWithCrossNamespacePVC,
+			newObj:       crossNamespacePVC,
+			expectedHint: framework.QueueSkip,
+			expectedErr:  false,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			var pvcs []*v1.PersistentVolumeClaim
+			if tc.newObj != nil {
+				pvcs = append(pvcs, tc.newObj.(*v1.PersistentVolumeClaim))
+			}
+			var p framework.Plugin
+			if tc.crossNamespaceRefs {
+				p = newPluginWithCrossNamespaceRefs(ctx, t, tc.existingPods, pvcs)
+			} else {
+				p = newPluginWithListers(ctx, t, tc.existingPods, nil, pvcs)
+			}
+
+			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterPersistentVolumeClaimChange(logger, tc.pod, tc.oldObj, tc.newObj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("Expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
+				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+// TestPreFilterRequeueConsistency audits that the events registered in
+// EventsToRegister are sufficient to requeue a pod the plugin parked in
+// PreFilter. A pod referencing a claim that does not exist yet must not be
+// skipped (a skipped pod is never re-evaluated by this plugin), the claim's
+// creation must hint Queue, and the requeued pod must then pass PreFilter.
+func TestPreFilterRequeueConsistency(t *testing.T) {
+	pod := st.MakePod().Name("pod-with-rwop").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj()
+	rwopPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-rwop",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+
+	logger, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The PVC creation event must be registered, otherwise nothing requeues
+	// the parked pod once the claim appears.
+	p := newPluginWithListers(ctx, t, nil, nil, nil).(*VolumeRestrictions)
+	events, err := p.EventsToRegister(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected EventsToRegister error: %v", err)
+	}
+	registered := false
+	for _, event := range events {
+		if event.Event.Resource == framework.PersistentVolumeClaim && event.Event.ActionType&framework.Add != 0 {
+			registered = true
+		}
+	}
+	if !registered {
+		t.Fatal("Expected a PersistentVolumeClaim Add event to be registered")
+	}
+
+	// Before the claim exists the pod must be parked, not skipped.
+	_, gotStatus := p.PreFilter(ctx, framework.NewCycleState(), pod)
+	if gotStatus.Code() != framework.UnschedulableAndUnresolvable {
+		t.Fatalf("Expected status code %v before the claim exists, got %v", framework.UnschedulableAndUnresolvable, gotStatus.Code())
+	}
+
+	// The claim's creation must requeue the pod.
+	hint, err := p.isSchedulableAfterPersistentVolumeClaimChange(logger, pod, nil, rwopPVC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hint != framework.Queue {
+		t.Fatalf("Expected %v after the claim is created, got %v", framework.Queue, hint)
+	}
+
+	// The requeued pod must now be evaluated, not skipped, so that the
+	// ReadWriteOncePod enforcement sees the claim held by another pod.
+	holder := st.MakePod().Name("holder").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Node("node-1").Obj()
+	p = newPluginWithListers(ctx, t, []*v1.Pod{holder}, nil, []*v1.PersistentVolumeClaim{rwopPVC}).(*VolumeRestrictions)
+	_, gotStatus = p.PreFilter(ctx, framework.NewCycleState(), pod)
+	if gotStatus.Code() == framework.Skip || !gotStatus.IsSuccess() {
+		t.Fatalf("Expected the pod to be evaluated after the claim is created, got status %v", gotStatus)
+	}
+}
+
+// countingPVCLister wraps a PersistentVolumeClaimLister and counts the Get calls.
+type countingPVCLister struct {
+	corelisters.PersistentVolumeClaimLister
+	gets *int
+}
+
+func (l countingPVCLister) PersistentVolumeClaims(namespace string) corelisters.PersistentVolumeClaimNamespaceLister {
+	return countingPVCNamespaceLister{l.PersistentVolumeClaimLister.PersistentVolumeClaims(namespace), l.gets}
+}
+
+type countingPVCNamespaceLister struct {
+	corelisters.PersistentVolumeClaimNamespaceLister
+	gets *int
+}
+
+func (l countingPVCNamespaceLister) Get(name string) (*v1.PersistentVolumeClaim, error) {
+	*l.gets++
+	return l.PersistentVolumeClaimNamespaceLister.Get(name)
+}
+
+func TestReadWriteOncePodPVCCache(t *testing.T) {
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "claim-1"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		},
+	}
+
+	logger, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Without the SchedulingQueueHints gate the invalidation events never reach
+	// the plugin, so the cache must not be built at all.
+	if p := newPluginWithListers(ctx, t, nil, nil, []*v1.PersistentVolumeClaim{pvc}).(*VolumeRestrictions); p.rwopCache != nil {
+		t.Fatalf("expected no cache when SchedulingQueueHints is disabled")
+	}
+
+	p := newPluginWithQueueingHints(ctx, t, []*v1.PersistentVolumeClaim{pvc}).(*VolumeRestrictions)
+	gets := 0
+	p.pvcLister = countingPVCLister{p.pvcLister, &gets}
+
+	for i := 0; i < 2; i++ {
+		if _, status := p.readWriteOncePodPVCsForPod(ctx, pod); !status.IsSuccess() {
+			t.Fatalf("Unexpected status: %v", status)
+		}
+	}
+	if gets != 1 {
+		t.Fatalf("expected 1 lister lookup across two cycles, got %d", gets)
+	}
+
+	// A claim update event invalidates the cached classification, so the next
+	// cycle reads the claim again.
+	if _, err := p.isSchedulableAfterPersistentVolumeClaimChange(logger, pod, pvc, pvc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, status := p.readWriteOncePodPVCsForPod(ctx, pod); !status.IsSuccess() {
+		t.Fatalf("Unexpected status: %v", status)
+	}
+	if gets != 2 {
+		t.Fatalf("expected the update to invalidate the cache, got %d lookups", gets)
+	}
+}
+
+func BenchmarkReadWriteOncePodPVCLookups(b *testing.B) {
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "claim-1"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		},
+	}
+
+	for _, cached := range []bool{true, false} {
+		name := "cached"
+		if !cached {
+			name = "uncached"
+		}
+		b.Run(name, func(b *testing.B) {
+			_, ctx := ktesting.NewTestContext(b)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithQueueingHints(ctx, b, []*v1.PersistentVolumeClaim{pvc}).(*VolumeRestrictions)
+			gets := 0
+			p.pvcLister = countingPVCLister{p.pvcLister, &gets}
+			if !cached {
+				p.rwopCache = nil
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, status := p.readWriteOncePodPVCsForPod(ctx, pod); !status.IsSuccess() {
+					b.Fatalf("Unexpected status: %v", status)
+				}
+			}
+			b.ReportMetric(float64(gets)/float64(b.N), "listergets/op")
+		})
+	}
+}
+
+func Test_isSchedulableAfterPersistentVolumeDeleted(t *testing.T) {
+	podWithOnePVC := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	podWithBoundPVC := st.MakePod().Name("pod-with-bound-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-2").Obj()
+	podWithNotEqualNamespace := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceSystem).PVC("claim-1").Obj()
+
+	// The claim of pvWithClaimRef no longer exists; the hint relies on the
+	// last-known binding recorded on the deleted PV.
+	pvWithClaimRef := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: v1.PersistentVolumeSpec{
+			ClaimRef: &v1.ObjectReference{
+				Namespace: metav1.NamespaceDefault,
+				Name:      "claim-1",
+			},
+		},
+	}
+	pvWithoutClaimRef := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-2"},
+	}
+	boundPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-2",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName: "pv-2",
+		},
+	}
+
+	testcases := map[string]struct {
+		pod            *v1.Pod
+		existingPVCs   []*v1.PersistentVolumeClaim
+		oldObj, newObj interface{}
+		expectedHint   framework.QueueingHint
+		expectedErr    bool
+	}{
+		"queue-pv-bound-via-claim-ref": {
+			pod:          podWithOnePVC,
+			oldObj:       pvWithClaimRef,
+			expectedHint: framework.Queue,
+		},
+		"queue-pv-bound-via-claim-volume-name": {
+			pod:          podWithBoundPVC,
+			existingPVCs: []*v1.PersistentVolumeClaim{boundPVC},
+			oldObj:       pvWithoutClaimRef,
+			expectedHint: framework.Queue,
+		},
+		"skip-unrelated-pv": {
+			pod:          podWithOnePVC,
+			oldObj:       pvWithoutClaimRef,
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-claim-ref-namespace-not-equal-pod": {
+			pod:          podWithNotEqualNamespace,
+			oldObj:       pvWithClaimRef,
+			expectedHint: framework.QueueSkip,
+		},
+		"error-unexpected-object": {
+			pod:         podWithOnePVC,
+			oldObj:      podWithOnePVC,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, nil, nil, tc.existingPVCs)
+
+			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterPersistentVolumeDeleted(logger, tc.pod, tc.oldObj, tc.newObj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("Expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
+				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_isSchedulableAfterPersistentVolumeChange(t *testing.T) {
+	podWithOnePVC := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	podWithBoundPVC := st.MakePod().Name("pod-with-bound-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-2").Obj()
+
+	makePV := func(name string, claimRef *v1.ObjectReference, modes ...v1.PersistentVolumeAccessMode) *v1.PersistentVolume {
+		return &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1.PersistentVolumeSpec{
+				ClaimRef:    claimRef,
+				AccessModes: modes,
+			},
+		}
+	}
+	claimRef := &v1.ObjectReference{
+		Namespace: metav1.NamespaceDefault,
+		Name:      "claim-1",
+	}
+	boundPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-2",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName: "pv-2",
+		},
+	}
+
+	testcases := map[string]struct {
+		pod            *v1.Pod
+		existingPVCs   []*v1.PersistentVolumeClaim
+		oldObj, newObj interface{}
+		expectedHint   framework.QueueingHint
+		expectedErr    bool
+	}{
+		"queue-added-pv-bound-via-claim-ref": {
+			pod:          podWithOnePVC,
+			newObj:       makePV("pv-1", claimRef, v1.ReadWriteOnce),
+			expectedHint: framework.Queue,
+		},
+		"queue-access-modes-changed-on-pv-bound-via-claim-volume-name": {
+			pod:          podWithBoundPVC,
+			existingPVCs: []*v1.PersistentVolumeClaim{boundPVC},
+			oldObj:       makePV("pv-2", nil, v1.ReadWriteOncePod),
+			newObj:       makePV("pv-2", nil, v1.ReadWriteOnce),
+			expectedHint: framework.Queue,
+		},
+		"skip-access-modes-unchanged": {
+			pod:          podWithOnePVC,
+			oldObj:       makePV("pv-1", claimRef, v1.ReadWriteOnce),
+			newObj:       makePV("pv-1", claimRef, v1.ReadWriteOnce),
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-access-modes-reordered": {
+			pod:          podWithOnePVC,
+			oldObj:       makePV("pv-1", claimRef, v1.ReadWriteOnce, v1.ReadOnlyMany),
+			newObj:       makePV("pv-1", claimRef, v1.ReadOnlyMany, v1.ReadWriteOnce),
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-unrelated-pv-access-modes-changed": {
+			pod:          podWithOnePVC,
+			oldObj:       makePV("pv-2", nil, v1.ReadWriteOncePod),
+			newObj:       makePV("pv-2", nil, v1.ReadWriteOnce),
+			expectedHint: framework.QueueSkip,
+		},
+		"error-unexpected-object": {
+			pod:         podWithOnePVC,
+			newObj:      podWithOnePVC,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, nil, nil, tc.existingPVCs)
+
+			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterPersistentVolumeChange(logger, tc.pod, tc.oldObj, tc.newObj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("Expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
+				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_isSchedulableAfterNodeBecameReady(t *testing.T) {
+	makeNode := func(ready v1.ConditionStatus, attachedVolumeIDs ...string) *v1.Node {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+			},
+		}
+		for _, volumeID := range attachedVolumeIDs {
+			node.Status.VolumesAttached = append(node.Status.VolumesAttached, v1.AttachedVolume{
+				Name: v1.UniqueVolumeName("kubernetes.io/aws-ebs/aws://us-east-1a/" + volumeID),
+			})
+		}
+		return node
+	}
+	podWithEBSVolume := st.MakePod().Name("pod-with-ebs").Volume(v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"},
+		},
+	}).Obj()
+	podWithoutEBSVolume := st.MakePod().Name("pod-without-ebs").PVC("claim-1").Obj()
+
+	testcases := map[string]struct {
+		pod            *v1.Pod
+		oldObj, newObj interface{}
+		expectedHint   framework.QueueingHint
+		expectedErr    bool
+	}{
+		"queue-node-with-attached-volume-became-ready": {
+			pod:          podWithEBSVolume,
+			oldObj:       makeNode(v1.ConditionFalse, "vol-1"),
+			newObj:       makeNode(v1.ConditionTrue, "vol-1"),
+			expectedHint: framework.Queue,
+		},
+		"skip-node-without-attached-volume": {
+			pod:          podWithEBSVolume,
+			oldObj:       makeNode(v1.ConditionFalse),
+			newObj:       makeNode(v1.ConditionTrue),
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-node-stays-ready": {
+			pod:          podWithEBSVolume,
+			oldObj:       makeNode(v1.ConditionTrue, "vol-1"),
+			newObj:       makeNode(v1.ConditionTrue, "vol-1"),
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-node-became-not-ready": {
+			pod:          podWithEBSVolume,
+			oldObj:       makeNode(v1.ConditionTrue, "vol-1"),
+			newObj:       makeNode(v1.ConditionFalse, "vol-1"),
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-pod-without-single-attach-volumes": {
+			pod:          podWithoutEBSVolume,
+			oldObj:       makeNode(v1.ConditionFalse, "vol-1"),
+			newObj:       makeNode(v1.ConditionTrue, "vol-1"),
+			expectedHint: framework.QueueSkip,
+		},
+		"error-unexpected-object": {
+			pod:         podWithEBSVolume,
+			newObj:      podWithEBSVolume,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPlugin(ctx, t)
+
+			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterNodeBecameReady(logger, tc.pod, tc.oldObj, tc.newObj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("Expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
+				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_isSchedulableAfterCSIDriverChange(t *testing.T) {
+	makeDriver := func(name string, attachRequired *bool) *storagev1.CSIDriver {
+		return &storagev1.CSIDriver{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       storagev1.CSIDriverSpec{AttachRequired: attachRequired},
+		}
+	}
+	podWithBoundClaim := st.MakePod().Name("pod-with-bound-claim").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	podWithInlineVolume := st.MakePod().Name("pod-with-inline-volume").Volume(v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			CSI: &v1.CSIVolumeSource{Driver: "inline.csi.example.com"},
+		},
+	}).Obj()
+	boundPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "claim-1"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName: "pv-1",
+		},
+	}
+	csiPV := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{Driver: "disk.csi.example.com", VolumeHandle: "handle-1"},
+			},
+		},
+	}
+
+	testcases := map[string]struct {
+		pod            *v1.Pod
+		oldObj, newObj interface{}
+		expectedHint   framework.QueueingHint
+		expectedErr    bool
+	}{
+		"queue-created-driver-backing-bound-claim": {
+			pod:          podWithBoundClaim,
+			newObj:       makeDriver("disk.csi.example.com", nil),
+			expectedHint: framework.Queue,
+		},
+		"queue-created-driver-backing-inline-volume": {
+			pod:          podWithInlineVolume,
+			newObj:       makeDriver("inline.csi.example.com", nil),
+			expectedHint: framework.Queue,
+		},
+		"queue-attach-policy-changed": {
+			pod:          podWithBoundClaim,
+			oldObj:       makeDriver("disk.csi.example.com", ptr.To(true)),
+			newObj:       makeDriver("disk.csi.example.com", ptr.To(false)),
+			expectedHint: framework.Queue,
+		},
+		"skip-unrelated-driver": {
+			pod:          podWithBoundClaim,
+			newObj:       makeDriver("other.csi.example.com", nil),
+			expectedHint: framework.QueueSkip,
+		},
+		"skip-update-without-attach-policy-change": {
+			pod:          podWithBoundClaim,
+			oldObj:       makeDriver("disk.csi.example.com", ptr.To(true)),
+			newObj:       makeDriver("disk.csi.example.com", ptr.To(true)),
+			expectedHint: framework.QueueSkip,
+		},
+		"error-unexpected-object": {
+			pod:         podWithBoundClaim,
+			newObj:      podWithBoundClaim,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			logger, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), nil, nil,
+				[]*v1.PersistentVolumeClaim{boundPVC}, []*v1.PersistentVolume{csiPV})
+
+			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterCSIDriverChange(logger, tc.pod, tc.oldObj, tc.newObj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("Expect error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
+				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestHintEventCache(t *testing.T) {
+	c := newHintEventCache()
+	calls := 0
+	resolve := func() string {
+		calls++
+		return fmt.Sprintf("value-%d", calls)
+	}
+
+	if got := c.lookup("event-1", "item", resolve); got != "value-1" {
+		t.Fatalf("expected the first lookup to resolve, got %q", got)
+	}
+	if got := c.lookup("event-1", "item", resolve); got != "value-1" {
+		t.Fatalf("expected the repeated lookup to reuse the cached value, got %q", got)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 resolution for repeated lookups of the same event, got %d", calls)
+	}
+	// A different event must not reuse the previous event's entries.
+	if got := c.lookup("event-2", "item", resolve); got != "value-2" {
+		t.Fatalf("expected a new event to resolve again, got %q", got)
+	}
+}
+
+func BenchmarkHintEvaluationsSharedPerEvent(b *testing.B) {
+	// One PersistentVolume deletion evaluated by many waiting pods, all
+	// referencing the same bound claim.
+	const numPods = 100
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "claim-1"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			VolumeName: "pv-1",
+		},
+	}
+	var pods []*v1.Pod
+	for i := 0; i < numPods; i++ {
+		pods = append(pods, st.MakePod().Name(fmt.Sprintf("pod-%d", i)).Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj())
+	}
+
+	for _, cached := range []bool{true, false} {
+		name := "cached"
+		if !cached {
+			name = "uncached"
+		}
+		b.Run(name, func(b *testing.B) {
+			logger, ctx := ktesting.NewTestContext(b)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, b, nil, nil, []*v1.PersistentVolumeClaim{pvc}).(*VolumeRestrictions)
+			gets := 0
+			p.pvcLister = countingPVCLister{p.pvcLister, &gets}
+			if !cached {
+				p.hintCache = nil
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				deletedPV := &v1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: "pv-other", UID: "pv-other", ResourceVersion: fmt.Sprint(i)},
+				}
+				for _, pod := range pods {
+					if _, err := p.isSchedulableAfterPersistentVolumeDeleted(logger, pod, deletedPV, nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+			b.ReportMetric(float64(gets)/float64(b.N), "listergets/event")
+		})
+	}
+}
+
+func TestHasCoReplicaPods(t *testing.T) {
+	makePod := func(name, controllerUID string) *v1.Pod {
+		pod := st.MakePod().Name(name).Namespace(metav1.NamespaceDefault).UID(name).Obj()
+		if controllerUID != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "rs",
+				UID:        types.UID(controllerUID),
+				Controller: ptr.To(true),
+			}}
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		want         bool
+	}{
+		{
+			name:         "sibling replica of the same controller",
+			pod:          makePod("pod-1", "controller-a"),
+			existingPods: []*v1.Pod{makePod("pod-2", "controller-a")},
+			want:         true,
+		},
+		{
+			name:         "pod without a controller owner",
+			pod:          makePod("pod-1", ""),
+			existingPods: []*v1.Pod{makePod("pod-2", "controller-a")},
+			want:         false,
+		},
+		{
+			name:         "existing pod owned by a different controller",
+			pod:          makePod("pod-1", "controller-a"),
+			existingPods: []*v1.Pod{makePod("pod-2", "controller-b")},
+			want:         false,
+		},
+		{
+			name: "existing pod in a different namespace",
+			pod:  makePod("pod-1", "controller-a"),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("pod-2").Namespace(metav1.NamespaceSystem).UID("pod-2").OwnerReference("rs", schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}).Obj(),
+			},
+			want: false,
+		},
+		{
+			name:         "no other pods",
+			pod:          makePod("pod-1", "controller-a"),
+			existingPods: nil,
+			want:         false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo(test.existingPods...)
+			if got := hasCoReplicaPods(test.pod, []*framework.NodeInfo{nodeInfo}); got != test.want {
+				t.Errorf("hasCoReplicaPods() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDaemonSetSharedVolumeKeys(t *testing.T) {
+	makePod := func(controllerKind string, volumes ...v1.Volume) *v1.Pod {
+		pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).Obj()
+		for _, volume := range volumes {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		}
+		if controllerKind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       controllerKind,
+				Name:       "owner",
+				UID:        types.UID("owner"),
+				Controller: ptr.To(true),
+			}}
+		}
+		return pod
+	}
+	rbdVolume := func(readOnly bool) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				RBD: &v1.RBDVolumeSource{CephMonitors: []string{"a"}, RBDPool: "foo", RBDImage: "bar", ReadOnly: readOnly},
+			},
+		}
+	}
+	iscsiVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{IQN: "iqn.2016-12.server:storage.target01"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want []string
+	}{
+		{
+			name: "DaemonSet pod with a fixed RBD image mounted read-write",
+			pod:  makePod("DaemonSet", rbdVolume(false)),
+			want: []string{"rbd://a/foo/bar"},
+		},
+		{
+			name: "DaemonSet pod mounting the image read-only",
+			pod:  makePod("DaemonSet", rbdVolume(true)),
+		},
+		{
+			name: "DaemonSet pod with several shared volumes",
+			pod:  makePod("DaemonSet", rbdVolume(false), iscsiVolume),
+			want: []string{"rbd://a/foo/bar", "iscsi://iqn.2016-12.server:storage.target01"},
+		},
+		{
+			name: "pod owned by a ReplicaSet",
+			pod:  makePod("ReplicaSet", rbdVolume(false)),
+		},
+		{
+			name: "pod without a controller",
+			pod:  makePod("", rbdVolume(false)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := daemonSetSharedVolumeKeys(test.pod, false)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected volume keys (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestVolumeIdentityHash(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	iscsiVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{IQN: "iqn.2016-12.world.srv:storage.target01"},
+		},
+	}
+	irrelevantVolume := v1.Volume{
+		Name:         "irrelevant",
+		VolumeSource: v1.VolumeSource{},
+	}
+
+	pod := st.MakePod().Name("pod").Volume(gceVolume).Volume(iscsiVolume).Obj()
+	identicalPod := st.MakePod().Name("identical-pod").Volume(gceVolume).Volume(iscsiVolume).Obj()
+	reorderedPod := st.MakePod().Name("reordered-pod").Volume(iscsiVolume).Volume(gceVolume).Obj()
+	podWithIrrelevantVolume := st.MakePod().Name("pod-with-irrelevant-volume").Volume(gceVolume).Volume(iscsiVolume).Volume(irrelevantVolume).Obj()
+	differentPod := st.MakePod().Name("different-pod").Volume(gceVolume).Obj()
+
+	hash := VolumeIdentityHash(pod)
+	if got := VolumeIdentityHash(identicalPod); got != hash {
+		t.Errorf("Pods with identical volumes hash differently: %d vs %d", hash, got)
+	}
+	if got := VolumeIdentityHash(reorderedPod); got != hash {
+		t.Errorf("Reordering volumes changed the hash: %d vs %d", hash, got)
+	}
+	if got := VolumeIdentityHash(podWithIrrelevantVolume); got != hash {
+		t.Errorf("A volume without conflict checking changed the hash: %d vs %d", hash, got)
+	}
+	if got := VolumeIdentityHash(differentPod); got == hash {
+		t.Errorf("Pods with different volumes hash equally: %d", got)
+	}
+}
+
+func BenchmarkSatisfyVolumeConflicts(b *testing.B) {
+	sharedReadOnlyVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{
+				PDName:   "shared",
+				ReadOnly: true,
+			},
+		},
+	}
+	// Many existing pods referencing the same read-only volume collapse into a
+	// single distinct volume identity.
+	existingPods := make([]*v1.Pod, 0, 500)
+	for i := 0; i < 500; i++ {
+		existingPods = append(existingPods, st.MakePod().Name(fmt.Sprintf("pod-%d", i)).Volume(sharedReadOnlyVolume).Obj())
+	}
+	nodeInfo := framework.NewNodeInfo(existingPods...)
+	pod := st.MakePod().Name("incoming").Volume(sharedReadOnlyVolume).Obj()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !satisfyVolumeConflicts(pod, nodeInfo, false) {
+			b.Fatal("expected no conflict for a shared read-only volume")
+		}
+	}
+}
+
+// BenchmarkFilterVolumeIdentityIndex exercises Filter against a node dense with
+// restriction-relevant volumes. The first evaluation builds the node's volume
+// identity index; later evaluations within the cycle reuse the memoized index
+// instead of rescanning every pod on the node.
+func BenchmarkFilterVolumeIdentityIndex(b *testing.B) {
+	existingPods := make([]*v1.Pod, 0, 100)
+	for i := 0; i < 100; i++ {
+		existingPods = append(existingPods, st.MakePod().Name(fmt.Sprintf("pod-%d", i)).Volume(v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: fmt.Sprintf("pd-%d", i)},
+			},
+		}).Obj())
+	}
+	nodeInfo := framework.NewNodeInfo(existingPods...)
+	nodeInfo.SetNode(st.MakeNode().Name("node-a").Obj())
+	pod := st.MakePod().Name("incoming").Volume(v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "pd-incoming"},
+		},
+	}).Obj()
+
+	_, ctx := ktesting.NewTestContext(b)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p := newPlugin(ctx, b)
+	cycleState := framework.NewCycleState()
+	if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); !status.IsSuccess() {
+		b.Fatalf("Unexpected PreFilter status: %v", status)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo); !status.IsSuccess() {
+			b.Fatalf("Unexpected Filter status: %v", status)
+		}
+	}
+}
+
+func TestPVCInformerSyncCheck(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Metrics are inert until created, which normally happens on registration.
+	metrics.PVCListerStaleChecks.Create(nil)
+	before, err := testutil.GetCounterMetricValue(metrics.PVCListerStaleChecks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pl := &VolumeRestrictions{}
+	done := make(chan struct{})
+	go func() {
+		// An informer that never syncs must be reported after the first grace interval.
+		pl.runPVCInformerSyncCheck(ctx, func() bool { return false }, time.Millisecond)
+		close(done)
+	}()
+
+	if err := wait.PollUntilContextTimeout(ctx, time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		value, err := testutil.GetCounterMetricValue(metrics.PVCListerStaleChecks)
+		if err != nil {
+			return false, err
+		}
+		return value > before, nil
+	}); err != nil {
+		t.Fatalf("Expected the stale check counter to grow: %v", err)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the sync check to stop when the context is cancelled")
+	}
+}
+
+func TestConflictStatusCodes(t *testing.T) {
+	gceVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	awsVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"},
+		},
+	}
+	rwopPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-rwop",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+	}
+
+	tests := []struct {
+		name                string
+		conflictStatusCodes map[string]string
+		pod                 *v1.Pod
+		existingPod         *v1.Pod
+		wantStatus          *framework.Status
+	}{
+		{
+			name:        "GCE conflict keeps the Unschedulable default",
+			pod:         st.MakePod().Name("pod").Volume(gceVolume).Obj(),
+			existingPod: st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").Volume(gceVolume).Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/existing"),
+		},
+		{
+			name:                "GCE conflict mapped to UnschedulableAndUnresolvable",
+			conflictStatusCodes: map[string]string{"GCEPersistentDisk": "UnschedulableAndUnresolvable"},
+			pod:                 st.MakePod().Name("pod").Volume(gceVolume).Obj(),
+			existingPod:         st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").Volume(gceVolume).Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/existing"),
+		},
+		{
+			name:        "EBS conflict keeps the Unschedulable default",
+			pod:         st.MakePod().Name("pod").Volume(awsVolume).Obj(),
+			existingPod: st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").Volume(awsVolume).Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonAttachLayerConflict,
+				"AWSElasticBlockStore volume aws://vol-1 is held by pod default/existing"),
+		},
+		{
+			name:                "EBS conflict mapped to UnschedulableAndUnresolvable",
+			conflictStatusCodes: map[string]string{"AWSElasticBlockStore": "UnschedulableAndUnresolvable"},
+			pod:                 st.MakePod().Name("pod").Volume(awsVolume).Obj(),
+			existingPod:         st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").Volume(awsVolume).Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonDiskConflict, ErrReasonAttachLayerConflict,
+				"AWSElasticBlockStore volume aws://vol-1 is held by pod default/existing"),
+		},
+		{
+			name:        "ReadWriteOncePod conflict keeps the Unschedulable default",
+			pod:         st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj(),
+			existingPod: st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").PVC("claim-rwop").Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-rwop is held by pod default/existing"),
+		},
+		{
+			name:                "ReadWriteOncePod conflict mapped to UnschedulableAndUnresolvable",
+			conflictStatusCodes: map[string]string{"ReadWriteOncePod": "UnschedulableAndUnresolvable"},
+			pod:                 st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-rwop").Obj(),
+			existingPod:         st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").PVC("claim-rwop").Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-rwop is held by pod default/existing"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.ConflictStatusCodes = test.conflictStatusCodes
+			p := newPluginWithArgsAndListers(ctx, t, args, []*v1.Pod{test.existingPod}, []*v1.Node{node}, []*v1.PersistentVolumeClaim{rwopPVC}, nil)
+
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, framework.NewNodeInfo(test.existingPod))
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAllowSharedWritersAnnotation(t *testing.T) {
+	const annotationKey = "volumerestrictions.kubernetes.io/allow-shared-writers"
+
+	makePVC := func(name string, annotations map[string]string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   metav1.NamespaceDefault,
+				Name:        name,
+				Annotations: annotations,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+			},
+		}
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("claim-annotated", map[string]string{annotationKey: "true"}),
+		makePVC("claim-annotated-false", map[string]string{annotationKey: "false"}),
+		makePVC("claim-plain", nil),
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+	}
+	makePod := func(name, claimName string) *v1.Pod {
+		return st.MakePod().Name(name).Namespace(metav1.NamespaceDefault).PVC(claimName).Obj()
+	}
+
+	tests := []struct {
+		name                string
+		annotationKey       string
+		claimName           string
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "annotated claim is excluded from conflict counting",
+			annotationKey:       annotationKey,
+			claimName:           "claim-annotated",
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name:          "claim annotated with false stays enforced",
+			annotationKey: annotationKey,
+			claimName:     "claim-annotated-false",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-annotated-false is held by pod default/existing"),
+		},
+		{
+			name:          "non-annotated claim stays enforced",
+			annotationKey: annotationKey,
+			claimName:     "claim-plain",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-plain is held by pod default/existing"),
+		},
+		{
+			name:      "allowance disabled ignores the annotation",
+			claimName: "claim-annotated",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-annotated is held by pod default/existing"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.AllowSharedWritersAnnotationKey = test.annotationKey
+			pod := makePod("pod", test.claimName)
+			existingPod := st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").PVC(test.claimName).Obj()
+			p := newPluginWithArgsAndListers(ctx, t, args, []*v1.Pod{existingPod}, []*v1.Node{node}, pvcs, nil)
+
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if !preFilterGotStatus.IsSuccess() {
+				return
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, framework.NewNodeInfo(existingPod))
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReadWriteOncePodLabelSelector(t *testing.T) {
+	podWithLabeledPVC := st.MakePod().Name("pod-with-labeled-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-rwop-labeled").Obj()
+	podWithUnlabeledPVC := st.MakePod().Name("pod-with-unlabeled-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-rwop-unlabeled").Obj()
+	existingPodWithLabeledPVC := st.MakePod().Name("existing-pod-with-labeled-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-rwop-labeled").Node("node-1").Obj()
+	existingPodWithUnlabeledPVC := st.MakePod().Name("existing-pod-with-unlabeled-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-rwop-unlabeled").Node("node-1").Obj()
+
+	labeledPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-rwop-labeled",
+			Labels:    map[string]string{"rwop-enforcement": "enabled"},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	unlabeledPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-rwop-unlabeled",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+	}
+	args := &config.VolumeRestrictionsArgs{
+		DiskConflictMode:       config.DiskConflictEnforce,
+		UnknownCSIDriverPolicy: config.UnknownCSIDriverStrict,
+		ReadWriteOncePodLabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"rwop-enforcement": "enabled"},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		pod                 *v1.Pod
+		existingPod         *v1.Pod
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:                "conflict on a claim matching the selector is enforced",
+			pod:                 podWithLabeledPVC,
+			existingPod:         existingPodWithLabeledPVC,
+			preFilterWantStatus: nil,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-rwop-labeled is held by pod default/existing-pod-with-labeled-pvc"),
+		},
+		{
+			name:                "conflict on a claim not matching the selector is ignored",
+			pod:                 podWithUnlabeledPVC,
+			existingPod:         existingPodWithUnlabeledPVC,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+			wantStatus:          nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			pvcs := []*v1.PersistentVolumeClaim{labeledPVC, unlabeledPVC}
+			p := newPluginWithArgsAndListers(ctx, t, args, []*v1.Pod{test.existingPod}, []*v1.Node{node}, pvcs, nil)
+
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if !preFilterGotStatus.IsSuccess() {
+				return
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, framework.NewNodeInfo(test.existingPod))
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestMissingAccessModes(t *testing.T) {
+	pod := st.MakePod().Name("pod-with-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-without-access-modes").Obj()
+	pvcWithoutAccessModes := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "claim-without-access-modes",
+		},
+		Spec: v1.PersistentVolumeClaimSpec{},
+	}
+
+	tests := []struct {
+		name       string
+		args       *config.VolumeRestrictionsArgs
+		wantStatus *framework.Status
+	}{
+		{
+			name:       "lenient by default",
+			args:       getDefaultVolumeRestrictionsArgs(),
+			wantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name: "strict mode rejects the pod",
+			args: &config.VolumeRestrictionsArgs{
+				DiskConflictMode:         config.DiskConflictEnforce,
+				UnknownCSIDriverPolicy:   config.UnknownCSIDriverStrict,
+				FailOnMissingAccessModes: true,
+			},
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonPVCMissingAccessModes),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsAndListers(ctx, t, test.args, nil, nil, []*v1.PersistentVolumeClaim{pvcWithoutAccessModes}, nil)
+
+			_, gotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, framework.NewCycleState(), pod)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestVolumeModeConflicts(t *testing.T) {
+	blockMode := v1.PersistentVolumeBlock
+	filesystemMode := v1.PersistentVolumeFilesystem
+	makePVC := func(name string, volumeMode *v1.PersistentVolumeMode) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-1",
+				VolumeMode: volumeMode,
+			},
+		}
+	}
+	makeClaimVolume := func(claimName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			Name: claimName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+					ReadOnly:  readOnly,
+				},
+			},
+		}
+	}
+
+	blockPVC1 := makePVC("claim-block-1", &blockMode)
+	blockPVC2 := makePVC("claim-block-2", &blockMode)
+	filesystemPVC := makePVC("claim-fs", &filesystemMode)
+	unsetModePVC := makePVC("claim-unset-mode", nil)
+	// The volume mode recorded on the PersistentVolume is the fallback for
+	// claims that predate volume mode defaulting.
+	pv := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec:       v1.PersistentVolumeSpec{VolumeMode: &blockMode},
+	}
+
+	podWithBlockPVC := st.MakePod().Name("pod-with-block-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-block-1").Obj()
+	podWithReadOnlyBlockPVC := st.MakePod().Name("pod-with-read-only-block-pvc").Namespace(metav1.NamespaceDefault).Volume(makeClaimVolume("claim-block-1", true)).Obj()
+	podWithFilesystemPVC := st.MakePod().Name("pod-with-fs-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-fs").Obj()
+	podWithUnsetModePVC := st.MakePod().Name("pod-with-unset-mode-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-unset-mode").Obj()
+	existingPodWithBlockPVC := st.MakePod().Name("existing-pod-with-block-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-block-2").Node("node-1").Obj()
+	existingPodWithReadOnlyBlockPVC := st.MakePod().Name("existing-pod-with-read-only-block-pvc").Namespace(metav1.NamespaceDefault).Volume(makeClaimVolume("claim-block-2", true)).Node("node-1").Obj()
+	existingPodWithFilesystemPVC := st.MakePod().Name("existing-pod-with-fs-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-fs").Node("node-1").Obj()
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+	}
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		wantStatus   *framework.Status
+	}{
+		{
+			name:         "block and block, all read-write",
+			pod:          podWithBlockPVC,
+			existingPods: []*v1.Pod{existingPodWithBlockPVC},
+			wantStatus:   framework.NewStatus(framework.Unschedulable, ErrReasonBlockVolumeConflict),
+		},
+		{
+			name:         "block and block, all read-only",
+			pod:          podWithReadOnlyBlockPVC,
+			existingPods: []*v1.Pod{existingPodWithReadOnlyBlockPVC},
+			wantStatus:   nil,
+		},
+		{
+			name:         "filesystem and filesystem",
+			pod:          podWithFilesystemPVC,
+			existingPods: []*v1.Pod{existingPodWithFilesystemPVC},
+			wantStatus:   nil,
+		},
+		{
+			name:         "block and filesystem",
+			pod:          podWithBlockPVC,
+			existingPods: []*v1.Pod{existingPodWithFilesystemPVC},
+			wantStatus:   framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonVolumeModeConflict),
+		},
+		{
+			name:         "claim with unset volume mode falls back to the PV's block mode",
+			pod:          podWithUnsetModePVC,
+			existingPods: []*v1.Pod{existingPodWithBlockPVC},
+			wantStatus:   framework.NewStatus(framework.Unschedulable, ErrReasonBlockVolumeConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			pvcs := []*v1.PersistentVolumeClaim{blockPVC1, blockPVC2, filesystemPVC, unsetModePVC}
+			p := newPluginWithArgsAndListers(ctx, t, getDefaultVolumeRestrictionsArgs(), test.existingPods, []*v1.Node{node}, pvcs, []*v1.PersistentVolume{pv})
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod); status != nil {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			nodeInfo := framework.NewNodeInfo(test.existingPods...)
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, nodeInfo)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCSIBlockVolumeConflicts(t *testing.T) {
+	blockMode := v1.PersistentVolumeBlock
+	makeCSIPV := func(name, handle string) *v1.PersistentVolume {
+		return &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1.PersistentVolumeSpec{
+				VolumeMode: &blockMode,
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{
+						Driver:       "disk.csi.example.com",
+						VolumeHandle: handle,
+					},
+				},
+			},
+		}
+	}
+	makePVC := func(name, volumeName string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName: volumeName,
+				VolumeMode: &blockMode,
+			},
+		}
+	}
+	makeClaimVolume := func(claimName string, readOnly bool) v1.Volume {
+		return v1.Volume{
+			Name: claimName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+					ReadOnly:  readOnly,
+				},
+			},
+		}
+	}
+
+	// pv-1 and pv-2 wrap the same CSI volume handle, pv-3 a different one.
+	pvs := []*v1.PersistentVolume{
+		makeCSIPV("pv-1", "handle-1"),
+		makeCSIPV("pv-2", "handle-1"),
+		makeCSIPV("pv-3", "handle-2"),
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("claim-1", "pv-1"),
+		makePVC("claim-2", "pv-2"),
+		makePVC("claim-3", "pv-3"),
+	}
+	// The driver is registered so the regular read-only sharing rules apply
+	// rather than the UnknownCSIDriverPolicy.
+	csiDrivers := []*storagev1.CSIDriver{
+		{ObjectMeta: metav1.ObjectMeta{Name: "disk.csi.example.com"}},
+	}
+
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("claim-1").Obj()
+	readOnlyPod := st.MakePod().Name("read-only-pod").Namespace(metav1.NamespaceDefault).Volume(makeClaimVolume("claim-1", true)).Obj()
+	existingPodSameHandle := st.MakePod().Name("existing-same-handle").Namespace(metav1.NamespaceDefault).PVC("claim-2").Node("node-1").Obj()
+	existingPodSameHandleReadOnly := st.MakePod().Name("existing-same-handle-ro").Namespace(metav1.NamespaceDefault).Volume(makeClaimVolume("claim-2", true)).Node("node-1").Obj()
+	existingPodOtherHandle := st.MakePod().Name("existing-other-handle").Namespace(metav1.NamespaceDefault).PVC("claim-3").Node("node-1").Obj()
+
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+	}
+
+	tests := []struct {
+		name         string
+		pod          *v1.Pod
+		existingPods []*v1.Pod
+		wantStatus   *framework.Status
+	}{
+		{
+			name:         "same handle through different PVs, all read-write",
+			pod:          pod,
+			existingPods: []*v1.Pod{existingPodSameHandle},
+			wantStatus:   framework.NewStatus(framework.Unschedulable, ErrReasonCSIBlockVolumeConflict),
+		},
+		{
+			name:         "same handle, incoming mapping read-only",
+			pod:          readOnlyPod,
+			existingPods: []*v1.Pod{existingPodSameHandle},
+			wantStatus:   framework.NewStatus(framework.Unschedulable, ErrReasonCSIBlockVolumeConflict),
+		},
+		{
+			name:         "same handle, all mappings read-only",
+			pod:          readOnlyPod,
+			existingPods: []*v1.Pod{existingPodSameHandleReadOnly},
+			wantStatus:   nil,
+		},
+		{
+			name:         "different handles",
+			pod:          pod,
+			existingPods: []*v1.Pod{existingPodOtherHandle},
+			wantStatus:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsListersAndCSIDrivers(ctx, t, getDefaultVolumeRestrictionsArgs(), test.existingPods, []*v1.Node{node}, pvcs, pvs, csiDrivers)
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod); status != nil {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			nodeInfo := framework.NewNodeInfo(test.existingPods...)
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, nodeInfo)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCrossNamespaceVolumeReferences(t *testing.T) {
+	// The pod in "default" references a claim living in "other" using the
+	// "namespace/name" form, which is only resolved when the
+	// CrossNamespaceVolumeDataSource feature gate is enabled.
+	pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC("other/claim-x").Obj()
+	existingPod := st.MakePod().Name("existing").Namespace("other").PVC("claim-x").Node("node-1").Obj()
+	crossNamespacePVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "claim-x"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+		},
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	tests := []struct {
+		name                string
+		gateEnabled         bool
+		existingPods        []*v1.Pod
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
+	}{
+		{
+			name:         "gate enabled resolves the claim and counts the conflict",
+			gateEnabled:  true,
+			existingPods: []*v1.Pod{existingPod},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim other/claim-x is held by pod other/existing"),
+		},
+		{
+			name:                "gate enabled without a conflicting pod skips",
+			gateEnabled:         true,
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
+		},
+		{
+			name: "gate disabled treats the reference as a missing same-namespace claim",
+			preFilterWantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable,
+				`persistentvolumeclaim "other/claim-x" not found`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				return New(ctx, plArgs, fh, feature.Features{EnableCrossNamespaceVolumeDataSource: test.gateEnabled})
+			}
+			snapshot := cache.NewSnapshot(test.existingPods, []*v1.Node{node})
+			p := plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, getDefaultVolumeRestrictionsArgs(),
+				snapshot, []runtime.Object{crossNamespacePVC})
+
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			if diff := cmp.Diff(test.preFilterWantStatus, preFilterGotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
+			}
+			if !preFilterGotStatus.IsSuccess() {
+				return
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, framework.NewNodeInfo(test.existingPods...))
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFeatureGateAttribution(t *testing.T) {
+	// The pod reaches the same ReadWriteOncePod conflict with and without the
+	// CrossNamespaceVolumeDataSource gate, but only the cross-namespace
+	// reference form makes the decision attributable to the gate.
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	attribution := "Conflict decision was influenced by feature-gated behavior"
+
+	tests := []struct {
+		name            string
+		gateEnabled     bool
+		claimNamespace  string
+		claimRef        string
+		wantAttribution bool
+	}{
+		{
+			name:            "gate enabled with a cross-namespace reference logs the gate",
+			gateEnabled:     true,
+			claimNamespace:  "other",
+			claimRef:        "other/claim-x",
+			wantAttribution: true,
+		},
+		{
+			name:           "gate enabled with a same-namespace reference logs nothing",
+			gateEnabled:    true,
+			claimNamespace: metav1.NamespaceDefault,
+			claimRef:       "claim-x",
+		},
+		{
+			name:           "gate disabled logs nothing",
+			claimNamespace: metav1.NamespaceDefault,
+			claimRef:       "claim-x",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := ktesting.NewLogger(t, ktesting.NewConfig(ktesting.BufferLogs(true)))
+			ctx, cancel := context.WithCancel(klog.NewContext(context.Background(), logger))
+			defer cancel()
+			pod := st.MakePod().Name("pod").Namespace(metav1.NamespaceDefault).PVC(test.claimRef).Obj()
+			existingPod := st.MakePod().Name("existing").Namespace(test.claimNamespace).PVC("claim-x").Node("node-1").Obj()
+			pvc := &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: test.claimNamespace, Name: "claim-x"},
+				Spec: v1.PersistentVolumeClaimSpec{
+					AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+				},
+			}
+			pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				return New(ctx, plArgs, fh, feature.Features{EnableCrossNamespaceVolumeDataSource: test.gateEnabled})
+			}
+			snapshot := cache.NewSnapshot([]*v1.Pod{existingPod}, []*v1.Node{node})
+			p := plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, getDefaultVolumeRestrictionsArgs(),
+				snapshot, []runtime.Object{pvc})
+
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			if status := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, framework.NewNodeInfo(existingPod)); status.IsSuccess() {
+				t.Fatalf("Expected the Filter to reject the pod")
+			}
+
+			underlier, ok := logger.GetSink().(ktesting.Underlier)
+			if !ok {
+				t.Fatalf("Expected a ktesting logger, got %T", logger.GetSink())
+			}
+			output := underlier.GetBuffer().String()
+			if got := strings.Contains(output, attribution); got != test.wantAttribution {
+				t.Errorf("expected attribution logged: %v, got: %v, log output:\n%s", test.wantAttribution, got, output)
+			}
+			if test.wantAttribution && !strings.Contains(output, "CrossNamespaceVolumeDataSource") {
+				t.Errorf("expected the gate name in the attribution, log output:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestParallelPVCLookup(t *testing.T) {
+	makePVC := func(name string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+			},
+		}
+	}
+	// Enough claims to cross parallelPVCLookupThreshold.
+	numClaims := 2 * parallelPVCLookupThreshold
+	var pvcs []*v1.PersistentVolumeClaim
+	podBuilder := st.MakePod().Name("pod-with-many-pvcs").Namespace(metav1.NamespaceDefault)
+	for i := 0; i < numClaims; i++ {
+		name := fmt.Sprintf("claim-%d", i)
+		pvcs = append(pvcs, makePVC(name))
+		podBuilder.PVC(name)
+	}
+	pod := podBuilder.Obj()
+	schedulermetrics.Register()
+	podWithMissingPVC := st.MakePod().Name("pod-with-missing-pvc").Namespace(metav1.NamespaceDefault).Obj()
+	podWithMissingPVC.Spec.Volumes = append(pod.Spec.Volumes[:numClaims-1:numClaims-1], v1.Volume{
+		Name: "missing",
+		VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "claim-missing"},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		pod        *v1.Pod
+		wantPVCs   int
+		wantStatus *framework.Status
+	}{
+		{
+			name:     "all claims found",
+			pod:      pod,
+			wantPVCs: numClaims,
+		},
+		{
+			name: "missing claim keeps the NotFound status",
+			pod:  podWithMissingPVC,
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable,
+				`persistentvolumeclaim "claim-missing" not found`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, nil, nil, pvcs).(*VolumeRestrictions)
+
+			got, status := p.readWriteOncePodPVCsForPod(ctx, test.pod)
+			if diff := cmp.Diff(test.wantStatus, status); diff != "" {
+				t.Fatalf("Unexpected status (-want, +got): %s", diff)
+			}
+			if got.Len() != test.wantPVCs {
+				t.Errorf("expected %d ReadWriteOncePod PVCs, got %d", test.wantPVCs, got.Len())
+			}
+		})
+	}
+}
+
+func BenchmarkReadWriteOncePodPVCsForPod(b *testing.B) {
+	const numClaims = 30
+	var pvcs []*v1.PersistentVolumeClaim
+	podBuilder := st.MakePod().Name("pod-with-many-pvcs").Namespace(metav1.NamespaceDefault)
+	for i := 0; i < numClaims; i++ {
+		name := fmt.Sprintf("claim-%d", i)
+		pvcs = append(pvcs, &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+			},
+		})
+		podBuilder.PVC(name)
+	}
+	pod := podBuilder.Obj()
+	schedulermetrics.Register()
+
+	_, ctx := ktesting.NewTestContext(b)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p := newPluginWithListers(ctx, b, nil, nil, pvcs).(*VolumeRestrictions)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, status := p.readWriteOncePodPVCsForPod(ctx, pod); !status.IsSuccess() {
+			b.Fatalf("unexpected status: %v", status)
+		}
+	}
+}
+
+func TestConflictPolicy(t *testing.T) {
+	heldVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+		},
+	}
+	freeVolume := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "bar"},
+		},
+	}
+	existingPod := st.MakePod().Name("existing").Namespace(metav1.NamespaceDefault).Node("node-1").Volume(heldVolume).Obj()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	tests := []struct {
+		name       string
+		policy     ConflictPolicy
+		pod        *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name: "allow overrides a built-in conflict",
+			policy: func(pod *v1.Pod, volume *v1.Volume, nodeInfo *framework.NodeInfo) ConflictPolicyDecision {
+				return ConflictPolicyAllow
+			},
+			pod: st.MakePod().Name("pod").Volume(heldVolume).Obj(),
+		},
+		{
+			name: "defer leaves the decision to the built-in rules",
+			policy: func(pod *v1.Pod, volume *v1.Volume, nodeInfo *framework.NodeInfo) ConflictPolicyDecision {
+				return ConflictPolicyDefer
+			},
+			pod: st.MakePod().Name("pod").Volume(heldVolume).Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict,
+				"GCEPersistentDisk volume gce://foo is held by pod default/existing"),
+		},
+		{
+			name: "deny rejects a pair the built-in rules accept",
+			policy: func(pod *v1.Pod, volume *v1.Volume, nodeInfo *framework.NodeInfo) ConflictPolicyDecision {
+				return ConflictPolicyDeny
+			},
+			pod:        st.MakePod().Name("pod").Volume(freeVolume).Obj(),
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonDiskConflict, ErrReasonMountLayerConflict),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+				return NewWithConflictPolicy(test.policy)(ctx, plArgs, fh, feature.Features{})
+			}
+			snapshot := cache.NewSnapshot([]*v1.Pod{existingPod}, []*v1.Node{node})
+			p := plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, getDefaultVolumeRestrictionsArgs(), snapshot, nil)
+
+			cycleState := framework.NewCycleState()
+			if _, status := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, test.pod, framework.NewNodeInfo(existingPod))
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestReadWriteOncePodGauges(t *testing.T) {
+	makePVC := func(name string, mode v1.PersistentVolumeAccessMode) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{mode},
+			},
+		}
+	}
+	pvcs := []*v1.PersistentVolumeClaim{
+		makePVC("claim-a", v1.ReadWriteOncePod),
+		makePVC("claim-b", v1.ReadWriteOncePod),
+		makePVC("claim-c", v1.ReadWriteOnce),
+		makePVC("claim-d", v1.ReadWriteOncePod),
+	}
+	// claim-a is referenced twice by the same pod and claim-c is not
+	// ReadWriteOncePod; neither may inflate the in-use gauge.
+	existingPods := []*v1.Pod{
+		st.MakePod().Name("holder-a").Namespace(metav1.NamespaceDefault).Node("node-1").PVC("claim-a").PVC("claim-a").PVC("claim-c").Obj(),
+		st.MakePod().Name("holder-b").Namespace(metav1.NamespaceDefault).Node("node-1").PVC("claim-b").Obj(),
+	}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// The in-use gauge refresh reads pods from the informer, so the existing
+	// pods go into both the snapshot and the informer.
+	pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+		return New(ctx, plArgs, fh, feature.Features{})
+	}
+	// pendingPod exists in the informer but is not scheduled; the contending
+	// pod below is deliberately absent from the informer, as if deleted while
+	// still pending.
+	pendingPod := st.MakePod().Name("still-pending").Namespace(metav1.NamespaceDefault).PVC("claim-a").Obj()
+	objects := make([]runtime.Object, 0, len(pvcs)+len(existingPods)+1)
+	for _, pvc := range pvcs {
+		objects = append(objects, pvc)
+	}
+	for _, pod := range existingPods {
+		objects = append(objects, pod)
+	}
+	objects = append(objects, pendingPod)
+	snapshot := cache.NewSnapshot(existingPods, []*v1.Node{node})
+	p := plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, getDefaultVolumeRestrictionsArgs(), snapshot, objects).(*VolumeRestrictions)
+
+	// Metrics are inert until created, which normally happens on registration.
+	metrics.ReadWriteOncePodPVCsInUse.Create(nil)
+	metrics.PodsWaitingOnReadWriteOncePodPVCs.Create(nil)
+
+	expectGauge := func(name string, gauge *componentbasemetrics.Gauge, want float64) {
+		t.Helper()
+		got, err := testutil.GetGaugeMetricValue(gauge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("expected gauge %s to be %v, got %v", name, want, got)
+		}
+	}
+	preFilter := func(pod *v1.Pod) {
+		t.Helper()
+		// A pod without conflicts may legitimately be skipped; the gauges are
+		// updated either way.
+		if _, status := p.PreFilter(ctx, framework.NewCycleState(), pod); !status.IsSuccess() && status.Code() != framework.Skip {
+			t.Fatalf("Unexpected PreFilter status: %v", status)
+		}
+	}
+
+	// The in-use gauge is rebuilt by the periodic refresh, not per attempt.
+	p.refreshReadWriteOncePodPVCsInUse()
+	expectGauge("in-use", metrics.ReadWriteOncePodPVCsInUse, 2)
+
+	// A pod contending for a held claim is counted as waiting, once across attempts.
+	contending := st.MakePod().Name("contending").Namespace(metav1.NamespaceDefault).PVC("claim-a").Obj()
+	preFilter(contending)
+	preFilter(contending)
+	expectGauge("waiting", metrics.PodsWaitingOnReadWriteOncePodPVCs, 1)
+
+	// A pod referencing an unheld claim is not waiting.
+	preFilter(st.MakePod().Name("uncontended").Namespace(metav1.NamespaceDefault).PVC("claim-d").Obj())
+	expectGauge("waiting", metrics.PodsWaitingOnReadWriteOncePodPVCs, 1)
+
+	// A contending pod deleted before it reschedules never re-runs PreFilter,
+	// so the periodic refresh prunes it. The contending pod above was never
+	// created in the informer, which is indistinguishable from having been
+	// deleted; still-pending exists and is kept.
+	preFilter(pendingPod)
+	expectGauge("waiting", metrics.PodsWaitingOnReadWriteOncePodPVCs, 2)
+	p.pruneDeletedContendingPods()
+	expectGauge("waiting", metrics.PodsWaitingOnReadWriteOncePodPVCs, 1)
+	if !p.contendingPods.Has(framework.GetNamespacedName(pendingPod.Namespace, pendingPod.Name)) {
+		t.Errorf("expected still-pending to survive pruning, contending pods: %v", sets.List(p.contendingPods))
+	}
+}
+
+func TestStrictVolumeIdentity(t *testing.T) {
+	rbdWithoutImage := v1.Volume{
+		Name: "rbd-vol",
+		VolumeSource: v1.VolumeSource{
+			RBD: &v1.RBDVolumeSource{CephMonitors: []string{"a"}, RBDPool: "foo"},
+		},
+	}
+	iscsiWithoutIQN := v1.Volume{
+		Name: "iscsi-vol",
+		VolumeSource: v1.VolumeSource{
+			ISCSI: &v1.ISCSIVolumeSource{},
+		},
+	}
+	rbdComplete := v1.Volume{
+		Name: "rbd-vol",
+		VolumeSource: v1.VolumeSource{
+			RBD: &v1.RBDVolumeSource{CephMonitors: []string{"a"}, RBDPool: "foo", RBDImage: "bar"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		strict     bool
+		pod        *v1.Pod
+		wantStatus *framework.Status
+	}{
+		{
+			name:   "strict rejects an RBD volume with no image",
+			strict: true,
+			pod:    st.MakePod().Name("pod").Volume(rbdWithoutImage).Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEmptyVolumeIdentity,
+				`volume "rbd-vol" has an empty image`),
+		},
+		{
+			name:   "strict rejects an ISCSI volume with no IQN",
+			strict: true,
+			pod:    st.MakePod().Name("pod").Volume(iscsiWithoutIQN).Obj(),
+			wantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonEmptyVolumeIdentity,
+				`volume "iscsi-vol" has an empty IQN`),
+		},
+		{
+			name:   "strict accepts a fully specified volume",
+			strict: true,
+			pod:    st.MakePod().Name("pod").Volume(rbdComplete).Obj(),
+		},
+		{
+			name: "lenient ignores empty identity fields",
+			pod:  st.MakePod().Name("pod").Volume(rbdWithoutImage).Obj(),
 		},
 	}
 
-	for name, tc := range testcases {
-		t.Run(name, func(t *testing.T) {
-			logger, ctx := ktesting.NewTestContext(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
 			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
-			p := newPluginWithListers(ctx, t, tc.existingPods, nil, []*v1.PersistentVolumeClaim{tc.existingPVC})
+			args := getDefaultVolumeRestrictionsArgs()
+			args.StrictVolumeIdentity = test.strict
+			p := newPluginWithArgsAndListers(ctx, t, args, nil, nil, nil, nil)
 
-			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterPodDeleted(logger, tc.pod, tc.oldObj, nil)
-			if tc.expectedErr {
-				if err == nil {
-					t.Error("Expect error, but got nil")
-				}
-				return
+			cycleState := framework.NewCycleState()
+			_, gotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, test.pod)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected PreFilter status (-want, +got): %s", diff)
 			}
-			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
+		})
+	}
+}
+
+func TestConflictLayerReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		volume     v1.Volume
+		wantReason string
+	}{
+		{
+			name: "EBS conflicts at the attach layer",
+			volume: v1.Volume{
+				VolumeSource: v1.VolumeSource{
+					AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"},
+				},
+			},
+			wantReason: ErrReasonAttachLayerConflict,
+		},
+		{
+			name: "GCE conflicts at the mount layer",
+			volume: v1.Volume{
+				VolumeSource: v1.VolumeSource{
+					GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: "foo"},
+				},
+			},
+			wantReason: ErrReasonMountLayerConflict,
+		},
+		{
+			name: "ISCSI conflicts at the mount layer",
+			volume: v1.Volume{
+				VolumeSource: v1.VolumeSource{
+					ISCSI: &v1.ISCSIVolumeSource{IQN: "iqn.2016-12.server:storage.target01"},
+				},
+			},
+			wantReason: ErrReasonMountLayerConflict,
+		},
+		{
+			name: "RBD conflicts at the mount layer",
+			volume: v1.Volume{
+				VolumeSource: v1.VolumeSource{
+					RBD: &v1.RBDVolumeSource{CephMonitors: []string{"a"}, RBDPool: "foo", RBDImage: "bar"},
+				},
+			},
+			wantReason: ErrReasonMountLayerConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := conflictLayerReason(&test.volume); got != test.wantReason {
+				t.Errorf("expected reason %q, got %q", test.wantReason, got)
 			}
+		})
+	}
+}
 
-			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
-				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+func TestFeatureStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		args *config.VolumeRestrictionsArgs
+		want map[string]bool
+	}{
+		{
+			name: "default args",
+			args: getDefaultVolumeRestrictionsArgs(),
+			want: map[string]bool{
+				"DiskConflictChecks":             true,
+				"DiskConflictWarnOnly":           false,
+				"ConflictReasonAggregation":      false,
+				"RejectionHistory":               false,
+				"FailOnMissingAccessModes":       false,
+				"ReadWriteOncePodLabelSelector":  false,
+				"AllowSharedWritersAnnotation":   false,
+				"StrictUnknownCSIDriverPolicy":   true,
+				"StrictVolumeIdentity":           false,
+				"ConflictPolicy":                 false,
+				"CrossNamespaceVolumeReferences": false,
+				"SchedulingQueueHints":           false,
+				"VolumeLocalityScoring":          false,
+				"ReadWriteOncePerNode":           false,
+				"ExemptVolumeIdentities":         false,
+				"AssumedPodConflicts":            false,
+				"MultiWriterVolumeSources":       false,
+				"LegacyVolumeSources":            false,
+			},
+		},
+		{
+			name: "optional behaviors enabled",
+			args: &config.VolumeRestrictionsArgs{
+				DiskConflictMode:                config.DiskConflictWarn,
+				EnableConflictReasonAggregation: true,
+				RejectionHistoryLength:          16,
+				FailOnMissingAccessModes:        true,
+				ReadWriteOncePodLabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"enforce": "true"},
+				},
+				AllowSharedWritersAnnotationKey: "example.com/allow-shared-writers",
+				UnknownCSIDriverPolicy:          config.UnknownCSIDriverPermissive,
+				StrictVolumeIdentity:            true,
+				ScoreWeight:                     1,
+				EnforceReadWriteOncePerNode:     true,
+				ExemptVolumeIdentities:          []string{"gce://foo"},
+				MultiWriterVolumeSources:        []string{"RBD"},
+				LegacyVolumeSources:             true,
+			},
+			want: map[string]bool{
+				"DiskConflictChecks":             true,
+				"DiskConflictWarnOnly":           true,
+				"ConflictReasonAggregation":      true,
+				"RejectionHistory":               true,
+				"FailOnMissingAccessModes":       true,
+				"ReadWriteOncePodLabelSelector":  true,
+				"AllowSharedWritersAnnotation":   true,
+				"StrictUnknownCSIDriverPolicy":   false,
+				"StrictVolumeIdentity":           true,
+				"ConflictPolicy":                 false,
+				"CrossNamespaceVolumeReferences": false,
+				"SchedulingQueueHints":           false,
+				"VolumeLocalityScoring":          true,
+				"ReadWriteOncePerNode":           true,
+				"ExemptVolumeIdentities":         true,
+				"AssumedPodConflicts":            false,
+				"MultiWriterVolumeSources":       true,
+				"LegacyVolumeSources":            true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithArgsAndListers(ctx, t, test.args, nil, nil, nil, nil).(*VolumeRestrictions)
+			if diff := cmp.Diff(test.want, p.FeatureStatus()); diff != "" {
+				t.Errorf("Unexpected feature status (-want, +got): %s", diff)
 			}
 		})
 	}
 }
 
-func Test_isSchedulableAfterPersistentVolumeClaimChange(t *testing.T) {
-	podWithOnePVC := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwx-1").Node("node-1").Obj()
-	podWithTwoPVCs := st.MakePod().Name("pod-with-two-pvcs").Namespace(metav1.NamespaceDefault).PVC("claim-with-rwx-1").PVC("claim-with-rwx-2").Node("node-1").Obj()
-	podWithNotEqualNamespace := st.MakePod().Name("pod-with-one-pvc").Namespace(metav1.NamespaceSystem).PVC("claim-with-rwx-1").Node("claim-with-rwx-2").Obj()
+func TestScoreWeight(t *testing.T) {
+	readOnlyVol := func(name string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				GCEPersistentDisk: &v1.GCEPersistentDiskVolumeSource{PDName: name, ReadOnly: true},
+			},
+		}
+	}
+	readOnlyEBSVol := func(volumeID string) v1.Volume {
+		return v1.Volume{
+			VolumeSource: v1.VolumeSource{
+				AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: volumeID, ReadOnly: true},
+			},
+		}
+	}
+	pod := st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod").
+		Volume(readOnlyVol("foo")).Volume(readOnlyVol("bar")).Volume(readOnlyEBSVol("vol-1")).Obj()
+	pods := []*v1.Pod{
+		st.MakePod().Namespace(metav1.NamespaceDefault).Name("holder-a").Node("node-a").
+			Volume(readOnlyVol("foo")).Volume(readOnlyVol("bar")).Volume(readOnlyEBSVol("vol-1")).Obj(),
+		st.MakePod().Namespace(metav1.NamespaceDefault).Name("holder-b").Node("node-b").
+			Volume(readOnlyVol("foo")).Obj(),
+	}
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}},
+	}
 
-	PVC1 := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "claim-with-rwx-1",
+	tests := []struct {
+		name           string
+		scoreWeight    int32
+		expectedScores map[string]int64
+	}{
+		{
+			name:        "neutral weight ranks nodes by locality",
+			scoreWeight: 1,
+			expectedScores: map[string]int64{
+				"node-a": framework.MaxNodeScore,
+				"node-b": framework.MaxNodeScore / 3,
+				"node-c": 0,
+			},
+		},
+		{
+			name:        "higher weight amplifies partial locality, saturating at the maximum",
+			scoreWeight: 2,
+			expectedScores: map[string]int64{
+				"node-a": framework.MaxNodeScore,
+				"node-b": framework.MaxNodeScore / 3 * 2,
+				"node-c": 0,
+			},
+		},
+		{
+			name:        "zero weight disables scoring",
+			scoreWeight: 0,
+			expectedScores: map[string]int64{
+				"node-a": 0,
+				"node-b": 0,
+				"node-c": 0,
+			},
 		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			args := getDefaultVolumeRestrictionsArgs()
+			args.ScoreWeight = test.scoreWeight
+			p := newPluginWithArgsAndListers(ctx, t, args, pods, nodes, nil, nil).(framework.ScorePlugin)
+			cycleState := framework.NewCycleState()
+			for node, want := range test.expectedScores {
+				got, status := p.Score(ctx, cycleState, pod, node)
+				if !status.IsSuccess() {
+					t.Fatalf("Unexpected Score status for node %s: %v", node, status)
+				}
+				if got != want {
+					t.Errorf("expected score %d for node %s, got %d", want, node, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMidCyclePVCMutation(t *testing.T) {
+	claimName := "claim-with-rwop"
+	// The claim as the lister sees it at Filter time: it gained the
+	// ReadWriteOncePod access mode at ResourceVersion 2.
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: metav1.NamespaceDefault, ResourceVersion: "2"},
 		Spec: v1.PersistentVolumeClaimSpec{
-			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
 		},
 	}
+	pod := st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod").PVC(claimName).Obj()
+	holder := st.MakePod().Namespace(metav1.NamespaceDefault).Name("holder").Node("node-1").PVC(claimName).Obj()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
 
-	PVC2 := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "default",
-			Name:      "claim-with-rwx-2",
+	tests := []struct {
+		name string
+		// observedVersion is the claim's ResourceVersion PreFilter observed.
+		observedVersion string
+		wantStatus      *framework.Status
+	}{
+		{
+			name:            "claim unchanged since PreFilter keeps the classification",
+			observedVersion: "2",
+			wantStatus:      nil,
 		},
-		Spec: v1.PersistentVolumeClaimSpec{
-			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+		{
+			name:            "claim gaining ReadWriteOncePod mid-cycle is reclassified",
+			observedVersion: "1",
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/claim-with-rwop is held by pod default/holder"),
 		},
 	}
 
-	testcases := map[string]struct {
-		existingPods   []*v1.Pod
-		pod            *v1.Pod
-		oldObj, newObj interface{}
-		expectedHint   framework.QueueingHint
-		expectedErr    bool
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, []*v1.Pod{holder}, []*v1.Node{node}, []*v1.PersistentVolumeClaim{pvc})
+
+			// The state as PreFilter computed it before the claim changed: the
+			// claim was not classified ReadWriteOncePod then.
+			state := &preFilterState{
+				readWriteOncePodPVCs: sets.New[string](),
+				pvcResourceVersions: map[string]string{
+					framework.GetNamespacedName(metav1.NamespaceDefault, claimName): test.observedVersion,
+				},
+				reconcileOnce: new(sync.Once),
+			}
+			cycleState := framework.NewCycleState()
+			cycleState.Write(preFilterStateKey, state)
+
+			nodeInfo := framework.NewNodeInfo(holder)
+			nodeInfo.SetNode(node)
+			gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo)
+			if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+				t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+			}
+			// A second Filter call in the same cycle must not reconcile again.
+			secondStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo)
+			if diff := cmp.Diff(test.wantStatus, secondStatus); diff != "" {
+				t.Errorf("Unexpected second Filter status (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGenericEphemeralVolumes(t *testing.T) {
+	podUID := "pod-uid"
+	pod := st.MakePod().Namespace(metav1.NamespaceDefault).Name("pod").UID(podUID).Volume(v1.Volume{
+		Name: "data",
+		VolumeSource: v1.VolumeSource{
+			Ephemeral: &v1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{
+					Spec: v1.PersistentVolumeClaimSpec{
+						AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+					},
+				},
+			},
+		},
+	}).Obj()
+	// The claim the ephemeral-volume controller creates for the pod, named
+	// deterministically after the pod and volume.
+	ephemeralClaim := func(ownerUID string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod-data",
+				Namespace: metav1.NamespaceDefault,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Name:       "pod",
+					UID:        types.UID(ownerUID),
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOncePod},
+			},
+		}
+	}
+	holder := st.MakePod().Namespace(metav1.NamespaceDefault).Name("holder").Node("node-1").PVC("pod-data").Obj()
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+
+	tests := []struct {
+		name                string
+		existingPods        []*v1.Pod
+		existingPVCs        []*v1.PersistentVolumeClaim
+		preFilterWantStatus *framework.Status
+		wantStatus          *framework.Status
 	}{
-		"queue-new-object-pvc-belong-pod": {
-			existingPods: []*v1.Pod{},
-			pod:          podWithTwoPVCs,
-			newObj:       PVC1,
-			expectedHint: framework.Queue,
-			expectedErr:  false,
+		{
+			name:         "ephemeral claim not in use",
+			existingPVCs: []*v1.PersistentVolumeClaim{ephemeralClaim(podUID)},
+			// The ephemeral claim resolves and is unheld, so there is nothing
+			// left for Filter to check.
+			preFilterWantStatus: framework.NewStatus(framework.Skip),
 		},
-		"skip-new-object-unused": {
-			existingPods: []*v1.Pod{},
-			pod:          podWithOnePVC,
-			newObj:       PVC2,
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+		{
+			name:                "ephemeral claim held by another pod",
+			existingPods:        []*v1.Pod{holder},
+			existingPVCs:        []*v1.PersistentVolumeClaim{ephemeralClaim(podUID)},
+			preFilterWantStatus: nil,
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonReadWriteOncePodConflict,
+				"PersistentVolumeClaim default/pod-data is held by pod default/holder"),
 		},
-		"skip-nil-old-object": {
-			existingPods: []*v1.Pod{},
-			pod:          podWithOnePVC,
-			newObj:       PVC2,
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+		{
+			name:                "ephemeral claim not created yet",
+			existingPVCs:        []*v1.PersistentVolumeClaim{},
+			preFilterWantStatus: framework.NewStatus(framework.UnschedulableAndUnresolvable, "persistentvolumeclaim \"pod-data\" not found"),
 		},
-		"skip-new-object-not-belong-pod": {
-			existingPods: []*v1.Pod{},
-			pod:          podWithOnePVC,
-			newObj:       PVC2,
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+		{
+			name:                "ephemeral claim owned by another pod",
+			existingPVCs:        []*v1.PersistentVolumeClaim{ephemeralClaim("other-uid")},
+			preFilterWantStatus: framework.AsStatus(fmt.Errorf("PVC default/pod-data was not created for pod default/pod (pod is not owner)")),
 		},
-		"skip-new-object-namespace-not-equal-pod": {
-			existingPods: []*v1.Pod{},
-			pod:          podWithNotEqualNamespace,
-			newObj:       PVC1,
-			expectedHint: framework.QueueSkip,
-			expectedErr:  false,
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			p := newPluginWithListers(ctx, t, test.existingPods, []*v1.Node{node}, test.existingPVCs)
+			cycleState := framework.NewCycleState()
+			_, preFilterGotStatus := p.(framework.PreFilterPlugin).PreFilter(ctx, cycleState, pod)
+			// Statuses are compared by code and message since error statuses
+			// wrap distinct error values.
+			if test.preFilterWantStatus.Code() != preFilterGotStatus.Code() || test.preFilterWantStatus.Message() != preFilterGotStatus.Message() {
+				t.Errorf("expected PreFilter status %v, got %v", test.preFilterWantStatus, preFilterGotStatus)
+			}
+			if test.preFilterWantStatus.IsSuccess() {
+				nodeInfo := framework.NewNodeInfo(test.existingPods...)
+				nodeInfo.SetNode(node)
+				gotStatus := p.(framework.FilterPlugin).Filter(ctx, cycleState, pod, nodeInfo)
+				if diff := cmp.Diff(test.wantStatus, gotStatus); diff != "" {
+					t.Errorf("Unexpected Filter status (-want, +got): %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestPrewarm(t *testing.T) {
+	volState := v1.Volume{
+		VolumeSource: v1.VolumeSource{
+			AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{VolumeID: "vol-1"},
 		},
 	}
+	pods := []*v1.Pod{
+		st.MakePod().Namespace(metav1.NamespaceDefault).Name("existing").Node("node-1").Volume(volState).Obj(),
+	}
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+	}
 
-	for name, tc := range testcases {
-		t.Run(name, func(t *testing.T) {
-			logger, ctx := ktesting.NewTestContext(t)
+	tests := []struct {
+		name             string
+		diskConflictMode config.DiskConflictMode
+		wantWarm         bool
+	}{
+		{
+			name:             "builds the index for every node",
+			diskConflictMode: config.DiskConflictEnforce,
+			wantWarm:         true,
+		},
+		{
+			name:             "no-op when disk conflict checking is off",
+			diskConflictMode: config.DiskConflictOff,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
 			ctx, cancel := context.WithCancel(ctx)
 			defer cancel()
-			p := newPluginWithListers(ctx, t, tc.existingPods, nil, []*v1.PersistentVolumeClaim{tc.newObj.(*v1.PersistentVolumeClaim)})
+			args := getDefaultVolumeRestrictionsArgs()
+			args.DiskConflictMode = test.diskConflictMode
+			p := newPluginWithArgsAndListers(ctx, t, args, pods, nodes, nil, nil).(*VolumeRestrictions)
 
-			actualHint, err := p.(*VolumeRestrictions).isSchedulableAfterPersistentVolumeClaimAdded(logger, tc.pod, tc.oldObj, tc.newObj)
-			if tc.expectedErr {
-				if err == nil {
-					t.Error("Expect error, but got nil")
+			if err := p.Prewarm(ctx); err != nil {
+				t.Fatalf("Prewarm failed: %v", err)
+			}
+			if !test.wantWarm {
+				if p.warmNodeVolumes != nil {
+					t.Fatalf("expected no warm index with DiskConflictMode %q, got %v", test.diskConflictMode, p.warmNodeVolumes)
 				}
 				return
 			}
-			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
+			if len(p.warmNodeVolumes) != len(nodes) {
+				t.Fatalf("expected a warm index for %d nodes, got %d", len(nodes), len(p.warmNodeVolumes))
+			}
+			if _, ok := p.warmNodeVolumes["node-1"].identities["aws://vol-1"]; !ok {
+				t.Errorf("expected the warm index for node-1 to contain the existing pod's EBS volume identity")
+			}
+			if len(p.warmNodeVolumes["node-2"].identities) != 0 {
+				t.Errorf("expected an empty warm index for node-2, got %v", p.warmNodeVolumes["node-2"].identities)
 			}
 
-			if diff := cmp.Diff(tc.expectedHint, actualHint); diff != "" {
-				t.Errorf("Unexpected QueueingHint (-want, +got): %s", diff)
+			// The first cycle checking disk conflicts consumes the warm index.
+			cycleState := framework.NewCycleState()
+			pod := st.MakePod().Name("pod").Volume(volState).Obj()
+			if _, status := p.PreFilter(ctx, cycleState, pod); !status.IsSuccess() {
+				t.Fatalf("PreFilter failed: %v", status)
+			}
+			if p.warmNodeVolumes != nil {
+				t.Errorf("expected PreFilter to consume the warm index")
 			}
 		})
 	}
 }
 
-func newPlugin(ctx context.Context, t *testing.T) framework.Plugin {
-	return newPluginWithListers(ctx, t, nil, nil, nil)
+func newPlugin(ctx context.Context, tb testing.TB) framework.Plugin {
+	return newPluginWithListers(ctx, tb, nil, nil, nil)
+}
+
+func newPluginWithListers(ctx context.Context, tb testing.TB, pods []*v1.Pod, nodes []*v1.Node, pvcs []*v1.PersistentVolumeClaim) framework.Plugin {
+	return newPluginWithArgsAndListers(ctx, tb, getDefaultVolumeRestrictionsArgs(), pods, nodes, pvcs, nil)
+}
+
+// newPluginWithCrossNamespaceRefs builds the plugin with the
+// CrossNamespaceVolumeDataSource feature gate enabled, which is what resolves
+// "namespace/name" claim references.
+func newPluginWithCrossNamespaceRefs(ctx context.Context, tb testing.TB, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) framework.Plugin {
+	pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+		return New(ctx, plArgs, fh, feature.Features{EnableCrossNamespaceVolumeDataSource: true})
+	}
+	snapshot := cache.NewSnapshot(pods, nil)
+	objects := make([]runtime.Object, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		objects = append(objects, pvc)
+	}
+	return plugintesting.SetupPluginWithInformers(ctx, tb, pluginFactory, getDefaultVolumeRestrictionsArgs(), snapshot, objects)
+}
+
+// newPluginWithQueueingHints builds the plugin with the SchedulingQueueHints
+// feature gate enabled, which is what allows the ReadWriteOncePod claim cache
+// to run.
+func newPluginWithQueueingHints(ctx context.Context, tb testing.TB, pvcs []*v1.PersistentVolumeClaim) framework.Plugin {
+	pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
+		return New(ctx, plArgs, fh, feature.Features{EnableSchedulingQueueHint: true})
+	}
+	snapshot := cache.NewSnapshot(nil, nil)
+	objects := make([]runtime.Object, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		objects = append(objects, pvc)
+	}
+	return plugintesting.SetupPluginWithInformers(ctx, tb, pluginFactory, getDefaultVolumeRestrictionsArgs(), snapshot, objects)
+}
+
+func newPluginWithArgsAndListers(ctx context.Context, tb testing.TB, args *config.VolumeRestrictionsArgs, pods []*v1.Pod, nodes []*v1.Node, pvcs []*v1.PersistentVolumeClaim, pvs []*v1.PersistentVolume) framework.Plugin {
+	return newPluginWithArgsListersAndCSIDrivers(ctx, tb, args, pods, nodes, pvcs, pvs, nil)
 }
 
-func newPluginWithListers(ctx context.Context, t *testing.T, pods []*v1.Pod, nodes []*v1.Node, pvcs []*v1.PersistentVolumeClaim) framework.Plugin {
+func newPluginWithArgsListersAndCSIDrivers(ctx context.Context, tb testing.TB, args *config.VolumeRestrictionsArgs, pods []*v1.Pod, nodes []*v1.Node, pvcs []*v1.PersistentVolumeClaim, pvs []*v1.PersistentVolume, csiDrivers []*storagev1.CSIDriver) framework.Plugin {
 	pluginFactory := func(ctx context.Context, plArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
 		return New(ctx, plArgs, fh, feature.Features{})
 	}
 	snapshot := cache.NewSnapshot(pods, nodes)
 
-	objects := make([]runtime.Object, 0, len(pvcs))
+	objects := make([]runtime.Object, 0, len(pvcs)+len(pvs)+len(csiDrivers))
 	for _, pvc := range pvcs {
 		objects = append(objects, pvc)
 	}
+	for _, pv := range pvs {
+		objects = append(objects, pv)
+	}
+	for _, driver := range csiDrivers {
+		objects = append(objects, driver)
+	}
+
+	return plugintesting.SetupPluginWithInformers(ctx, tb, pluginFactory, args, snapshot, objects)
+}
 
-	return plugintesting.SetupPluginWithInformers(ctx, t, pluginFactory, &config.InterPodAffinityArgs{}, snapshot, objects)
+func getDefaultVolumeRestrictionsArgs() *config.VolumeRestrictionsArgs {
+	return &config.VolumeRestrictionsArgs{
+		DiskConflictMode:       config.DiskConflictEnforce,
+		UnknownCSIDriverPolicy: config.UnknownCSIDriverStrict,
+	}
 }
@@ -25,6 +25,7 @@ import (
 	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/kubernetes/pkg/features"
 	volumebindingmetrics "k8s.io/kubernetes/pkg/scheduler/framework/plugins/volumebinding/metrics"
+	volumerestrictionsmetrics "k8s.io/kubernetes/pkg/scheduler/framework/plugins/volumerestrictions/metrics"
 )
 
 const (
@@ -136,6 +137,7 @@ func Register() {
 		InitMetrics()
 		RegisterMetrics(metricsList...)
 		volumebindingmetrics.RegisterVolumeSchedulingMetrics()
+		volumerestrictionsmetrics.RegisterVolumeRestrictionsMetrics()
 
 		if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerQueueingHints) {
 			RegisterMetrics(queueingHintExecutionDuration, InFlightEvents)
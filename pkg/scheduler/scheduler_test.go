@@ -875,7 +875,7 @@ func Test_UnionedGVKs(t *testing.T) {
 				framework.CSINode:               framework.All - framework.Delete,
 				framework.CSIDriver:             framework.Update,
 				framework.CSIStorageCapacity:    framework.All - framework.Delete,
-				framework.PersistentVolume:      framework.All - framework.Delete,
+				framework.PersistentVolume:      framework.All,
 				framework.PersistentVolumeClaim: framework.All - framework.Delete,
 				framework.StorageClass:          framework.All - framework.Delete,
 				framework.VolumeAttachment:      framework.Delete,
@@ -890,7 +890,7 @@ func Test_UnionedGVKs(t *testing.T) {
 				framework.CSINode:               framework.All - framework.Delete,
 				framework.CSIDriver:             framework.Update,
 				framework.CSIStorageCapacity:    framework.All - framework.Delete,
-				framework.PersistentVolume:      framework.All - framework.Delete,
+				framework.PersistentVolume:      framework.All,
 				framework.PersistentVolumeClaim: framework.All - framework.Delete,
 				framework.StorageClass:          framework.All - framework.Delete,
 				framework.VolumeAttachment:      framework.Delete,
@@ -906,7 +906,7 @@ func Test_UnionedGVKs(t *testing.T) {
 				framework.CSINode:               framework.All - framework.Delete,
 				framework.CSIDriver:             framework.Update,
 				framework.CSIStorageCapacity:    framework.All - framework.Delete,
-				framework.PersistentVolume:      framework.All - framework.Delete,
+				framework.PersistentVolume:      framework.All,
 				framework.PersistentVolumeClaim: framework.All - framework.Delete,
 				framework.StorageClass:          framework.All - framework.Delete,
 				framework.VolumeAttachment:      framework.Delete,
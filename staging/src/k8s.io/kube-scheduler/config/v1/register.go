@@ -44,6 +44,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NodeResourcesFitArgs{},
 		&PodTopologySpreadArgs{},
 		&VolumeBindingArgs{},
+		&VolumeRestrictionsArgs{},
 		&NodeAffinityArgs{},
 	)
 	return nil
@@ -191,6 +191,204 @@ type NodeAffinityArgs struct {
 	AddedAffinity *corev1.NodeAffinity `json:"addedAffinity,omitempty"`
 }
 
+// DiskConflictMode defines how conflicts between inline disk volumes on a node
+// are handled by the VolumeRestrictions plugin.
+type DiskConflictMode string
+
+const (
+	// DiskConflictEnforce rejects pods whose inline disk volumes conflict with a
+	// volume used by an existing pod on the node.
+	DiskConflictEnforce DiskConflictMode = "Enforce"
+	// DiskConflictWarn logs conflicts and increments a metric, but does not
+	// reject pods. It is meant for auditing before enabling strict enforcement.
+	DiskConflictWarn DiskConflictMode = "Warn"
+	// DiskConflictOff disables inline disk conflict checking entirely.
+	DiskConflictOff DiskConflictMode = "Off"
+)
+
+// UnknownCSIDriverPolicy defines how the VolumeRestrictions plugin treats CSI
+// volumes whose driver has no CSIDriver object registered in the cluster.
+type UnknownCSIDriverPolicy string
+
+const (
+	// UnknownCSIDriverStrict treats volumes of an unknown driver as
+	// single-attach: two pods referencing the same volume handle conflict.
+	UnknownCSIDriverStrict UnknownCSIDriverPolicy = "Strict"
+	// UnknownCSIDriverPermissive skips conflict checking for volumes of an
+	// unknown driver, assuming the driver coordinates concurrent access itself.
+	UnknownCSIDriverPermissive UnknownCSIDriverPolicy = "Permissive"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeRestrictionsArgs holds arguments used to configure the VolumeRestrictions plugin.
+type VolumeRestrictionsArgs struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// DiskConflictMode controls how conflicts between inline disk volumes
+	// (GCE PD, AWS EBS, Ceph RBD, ISCSI, FibreChannel, FlexVolume, vSphere,
+	// Cinder and AzureDisk) on a node are handled.
+	// Valid values are "Enforce", "Warn" and "Off". "Enforce" rejects
+	// conflicting pods, "Warn" only logs and counts the conflict, and "Off"
+	// disables the checks. Defaults to "Enforce".
+	// +optional
+	DiskConflictMode DiskConflictMode `json:"diskConflictMode,omitempty"`
+
+	// EnableConflictReasonAggregation enables counting, per conflict category,
+	// the nodes a pod failed the Filter on, for verbose failure summaries.
+	// Defaults to false.
+	// +optional
+	EnableConflictReasonAggregation bool `json:"enableConflictReasonAggregation,omitempty"`
+
+	// RejectionHistoryLength is the number of recent Filter rejections the
+	// plugin retains in memory for post-incident inspection. Must be
+	// non-negative. Defaults to 0, which disables recording.
+	// +optional
+	RejectionHistoryLength int32 `json:"rejectionHistoryLength,omitempty"`
+
+	// FailOnMissingAccessModes rejects pods referencing a PersistentVolumeClaim
+	// with empty access modes instead of treating the claim as non-ReadWriteOncePod,
+	// to catch the misconfiguration early. Defaults to false.
+	// +optional
+	FailOnMissingAccessModes bool `json:"failOnMissingAccessModes,omitempty"`
+
+	// ReadWriteOncePodLabelSelector limits ReadWriteOncePod conflict enforcement
+	// to PersistentVolumeClaims matching the selector, for phased rollouts.
+	// An empty selector enforces on all claims.
+	// +optional
+	ReadWriteOncePodLabelSelector *metav1.LabelSelector `json:"readWriteOncePodLabelSelector,omitempty"`
+
+	// ConflictStatusCodes overrides, per conflict category, the status code the
+	// Filter returns when that conflict rejects a node. Keys are category names
+	// ("GCEPersistentDisk", "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel",
+	// "FlexVolume", "VsphereVolume", "Cinder", "AzureDisk", "ScaleIO",
+	// "StorageOS", "ReadWriteOncePod");
+	// values are "Unschedulable" or "UnschedulableAndUnresolvable", the latter
+	// excluding the node from preemption. Unlisted categories default to
+	// Unschedulable.
+	// +optional
+	ConflictStatusCodes map[string]string `json:"conflictStatusCodes,omitempty"`
+
+	// InformerSyncCheckSeconds is the interval, in seconds, at which the plugin
+	// verifies that its PersistentVolumeClaim informer is still synced, warning
+	// when it stays out of sync across consecutive checks. Must be non-negative.
+	// Defaults to 0, which disables the check.
+	// +optional
+	InformerSyncCheckSeconds int64 `json:"informerSyncCheckSeconds,omitempty"`
+
+	// AllowSharedWritersAnnotationKey is the annotation key that opts a
+	// PersistentVolumeClaim out of single-writer conflict enforcement when set
+	// to "true" on the claim, for cluster-aware filesystems that coordinate
+	// writers themselves. Defaults to the empty string, which disables the
+	// allowance.
+	// +optional
+	AllowSharedWritersAnnotationKey string `json:"allowSharedWritersAnnotationKey,omitempty"`
+
+	// UnknownCSIDriverPolicy controls how CSI volumes whose driver has no
+	// CSIDriver object are treated during conflict checking. "Strict" treats
+	// such volumes as single-attach and rejects concurrent use of the same
+	// volume handle, at the cost of false conflicts for multi-attach drivers
+	// that have not registered yet. "Permissive" skips the check, at the cost
+	// of missed conflicts for single-attach drivers. Defaults to "Strict".
+	// +optional
+	UnknownCSIDriverPolicy UnknownCSIDriverPolicy `json:"unknownCSIDriverPolicy,omitempty"`
+
+	// StrictVolumeIdentity rejects pods whose restriction-relevant volumes
+	// leave identity fields empty (an RBD volume with no monitors, pool or
+	// image, or an ISCSI volume with no IQN). Such volumes can never be
+	// matched against other volumes, which usually indicates a
+	// misconfiguration rather than intent. Defaults to false.
+	// +optional
+	StrictVolumeIdentity bool `json:"strictVolumeIdentity,omitempty"`
+
+	// ScoreWeight scales the node scores the plugin's volume-locality Score
+	// extension produces. Weights above one amplify the locality signal,
+	// saturating at the maximum node score; a weight of zero disables the
+	// extension. Must be in the range [0, 100]. Defaults to 1.
+	// +optional
+	ScoreWeight *int32 `json:"scoreWeight,omitempty"`
+
+	// EnabledDiskConflictCategories lists the in-tree disk volume types the
+	// plugin checks for conflicts. Valid entries are "GCEPersistentDisk",
+	// "AWSElasticBlockStore", "ISCSI", "RBD", "FibreChannel", "FlexVolume",
+	// "VsphereVolume", "Cinder" and "AzureDisk"; volume types not listed are
+	// ignored by the disk conflict checks. An empty list disables them all.
+	// Defaults to all nine categories.
+	// +optional
+	EnabledDiskConflictCategories []string `json:"enabledDiskConflictCategories,omitempty"`
+
+	// EmitConflictEvents emits a Warning event on the pod when a volume
+	// conflict rejects a node, naming the conflict and its holder, so the
+	// pending pod's events explain the conflict without scheduler log access.
+	// Events are rate-limited per pod. Defaults to false.
+	// +optional
+	EmitConflictEvents bool `json:"emitConflictEvents,omitempty"`
+
+	// IgnoreTerminatingPods excludes pods that are already terminating (have a
+	// deletionTimestamp) from disk conflict checking, scheduling the incoming
+	// pod optimistically on the assumption that the terminating pods' volumes
+	// are about to be released. Defaults to false.
+	// +optional
+	IgnoreTerminatingPods bool `json:"ignoreTerminatingPods,omitempty"`
+
+	// IgnoreMirrorPods excludes mirror pods (static pods, identified by the
+	// mirror annotation) from disk conflict checking. Mirror pods represent
+	// node-level components whose volumes are not managed through scheduling,
+	// so their presence on a node need not block other pods. Defaults to false.
+	// +optional
+	IgnoreMirrorPods bool `json:"ignoreMirrorPods,omitempty"`
+
+	// EnforceReadWriteOncePerNode extends single-writer enforcement to the
+	// ReadWriteOnce access mode: a pod referencing a bound ReadWriteOnce
+	// claim already in use by a pod on some node may only be scheduled onto
+	// that node. ReadWriteOnce formally restricts a volume to one node, not
+	// one pod, but nothing enforces the node restriction at scheduling time
+	// by default. Defaults to false.
+	// +optional
+	EnforceReadWriteOncePerNode bool `json:"enforceReadWriteOncePerNode,omitempty"`
+
+	// ExemptVolumeIdentities lists volume identities, in the "scheme://identifier"
+	// form the plugin computes (e.g. "gce://disk-1", "aws://vol-1"), that are
+	// exempt from all disk conflict checks. It is an escape hatch for specific
+	// known-safe shared volumes; exempt volumes never cause or suffer conflicts.
+	// Defaults to none.
+	// +optional
+	ExemptVolumeIdentities []string `json:"exemptVolumeIdentities,omitempty"`
+
+	// VerifyReadWriteOncePodHolders verifies, when a ReadWriteOncePod claim is
+	// reported in use, that a pod holding it still exists. A stale usage report
+	// would otherwise park waiting pods indefinitely; with verification enabled
+	// the stale reference is logged and not counted. Defaults to false.
+	// +optional
+	VerifyReadWriteOncePodHolders bool `json:"verifyReadWriteOncePodHolders,omitempty"`
+
+	// FlexVolumeIdentityKeys names the FlexVolume option keys, in order of
+	// preference, whose value identifies the underlying device for conflict
+	// checking. Two FlexVolumes with the same driver and the same value under
+	// an identifying key refer to the same device. Option keys vary per
+	// driver; a volume carrying none of the keys is left out of conflict
+	// checking. Defaults to ["volumeID"].
+	// +optional
+	FlexVolumeIdentityKeys []string `json:"flexVolumeIdentityKeys,omitempty"`
+
+	// MultiWriterVolumeSources lists volume source categories the operator
+	// knows to be safely shared by multiple writers; listed categories never
+	// cause or suffer disk conflicts, regardless of the built-in rules. It is
+	// an escape hatch per backend where ExemptVolumeIdentities exempts single
+	// volumes. Valid entries are the EnabledDiskConflictCategories names.
+	// Defaults to none.
+	// +optional
+	MultiWriterVolumeSources []string `json:"multiWriterVolumeSources,omitempty"`
+
+	// LegacyVolumeSources extends disk conflict checking to the legacy ScaleIO
+	// and StorageOS volume sources, which some older clusters still mount
+	// directly. Both are single-writer per volume: ScaleIO volumes are
+	// identified by system and volume name, StorageOS volumes by volume name,
+	// and either conflicts unless all mounts are read-only. Defaults to false.
+	// +optional
+	LegacyVolumeSources bool `json:"legacyVolumeSources,omitempty"`
+}
+
 // ScoringStrategyType the type of scoring strategy used in NodeResourcesFit plugin.
 type ScoringStrategyType string
 
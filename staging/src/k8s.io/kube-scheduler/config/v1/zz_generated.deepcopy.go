@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -607,3 +608,65 @@ func (in *VolumeBindingArgs) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeRestrictionsArgs) DeepCopyInto(out *VolumeRestrictionsArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.ReadWriteOncePodLabelSelector != nil {
+		in, out := &in.ReadWriteOncePodLabelSelector, &out.ReadWriteOncePodLabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConflictStatusCodes != nil {
+		in, out := &in.ConflictStatusCodes, &out.ConflictStatusCodes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ScoreWeight != nil {
+		in, out := &in.ScoreWeight, &out.ScoreWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnabledDiskConflictCategories != nil {
+		in, out := &in.EnabledDiskConflictCategories, &out.EnabledDiskConflictCategories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExemptVolumeIdentities != nil {
+		in, out := &in.ExemptVolumeIdentities, &out.ExemptVolumeIdentities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FlexVolumeIdentityKeys != nil {
+		in, out := &in.FlexVolumeIdentityKeys, &out.FlexVolumeIdentityKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MultiWriterVolumeSources != nil {
+		in, out := &in.MultiWriterVolumeSources, &out.MultiWriterVolumeSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeRestrictionsArgs.
+func (in *VolumeRestrictionsArgs) DeepCopy() *VolumeRestrictionsArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeRestrictionsArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VolumeRestrictionsArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}